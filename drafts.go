@@ -0,0 +1,163 @@
+package main
+
+import (
+	"cli-notes/input"
+	"cli-notes/scripts/data"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// recordDraft journals the draft of the open view, logging failures so the view carries on
+func recordDraft(journal *data.DraftJournal, draft any) {
+	if err := journal.Record(draft); err != nil {
+		slog.Error("failed to journal draft", "err", err)
+	}
+}
+
+// discardDraft removes the draft of a view that has nothing unsaved left
+func discardDraft(journal *data.DraftJournal) {
+	if err := journal.Discard(); err != nil {
+		slog.Error("failed to remove draft", "err", err)
+	}
+}
+
+// draftChoice is what to do with a draft an earlier session left behind
+type draftChoice int
+
+const (
+	draftResume draftChoice = iota
+	draftDiscard
+	draftKeep
+)
+
+// offerDraftRecovery offers to resume or discard each draft left by a session that didn't exit cleanly
+func offerDraftRecovery(reader input.InputReader) {
+	offerWeekPlanDraft(reader)
+	offerTalkToDraft(reader)
+	offerObjectivesDraft(reader)
+}
+
+func offerWeekPlanDraft(reader input.InputReader) {
+	draft, err := data.LoadWeekPlanDraft()
+	if !draftLoaded(data.WeekPlanDraftName, err) {
+		return
+	}
+
+	fmt.Printf("Unsaved week plan changes from %s:\n", draft.SavedAt.Format("2006-01-02 15:04"))
+	for _, move := range draft.Moves {
+		fmt.Printf("  %s: %s -> %s\n", move.Title, move.From, move.To)
+	}
+	switch promptDraftChoice(reader) {
+	case draftResume:
+		if err := runWeekPlanner(reader, draft); err != nil {
+			fmt.Printf("Error running week planner: %v\n", err)
+		}
+	case draftDiscard:
+		removeDraft(data.WeekPlanDraftName)
+	}
+}
+
+func offerTalkToDraft(reader input.InputReader) {
+	draft, err := data.LoadTalkToDraft()
+	if !draftLoaded(data.TalkToDraftName, err) {
+		return
+	}
+
+	fmt.Printf("Talk-to moves from %s that can still be undone:\n", draft.SavedAt.Format("2006-01-02 15:04"))
+	for _, move := range draft.Moves {
+		fmt.Printf("  %d todos for %s to %s\n", len(move.Todos), move.Person, move.TargetNote)
+	}
+	switch promptDraftChoice(reader) {
+	case draftResume:
+		state, stale, err := data.ResumeTalkToViewState(draft)
+		if err != nil {
+			fmt.Printf("Error resuming talk-to: %v\n", err)
+			return
+		}
+		if stale > 0 {
+			fmt.Printf("Skipped %d moves whose notes changed since\n", stale)
+		}
+		if len(state.UndoStack) == 0 {
+			removeDraft(data.TalkToDraftName)
+			return
+		}
+		if err := runTalkToViewWithState(state, reader); err != nil {
+			fmt.Printf("Error running talk-to view: %v\n", err)
+		}
+	case draftDiscard:
+		removeDraft(data.TalkToDraftName)
+	}
+}
+
+func offerObjectivesDraft(reader input.InputReader) {
+	draft, err := data.LoadObjectivesDraft()
+	if !draftLoaded(data.ObjectivesDraftName, err) {
+		return
+	}
+
+	if draft.ViewMode == data.SingleObjectiveView {
+		fmt.Printf("The objectives view was open on %s at %s\n", draft.Objective, draft.SavedAt.Format("2006-01-02 15:04"))
+	} else {
+		fmt.Printf("The objectives view was open at %s\n", draft.SavedAt.Format("2006-01-02 15:04"))
+	}
+	switch promptDraftChoice(reader) {
+	case draftResume:
+		state, stale, err := data.ResumeObjectivesViewState(draft)
+		if err != nil {
+			fmt.Printf("Error resuming objectives view: %v\n", err)
+			return
+		}
+		if stale {
+			fmt.Printf("%s is no longer the same objective, opening the list\n", draft.Objective)
+		}
+		if err := runObjectivesViewWithState(reader, state); err != nil {
+			fmt.Printf("Error running objectives view: %v\n", err)
+		}
+	case draftDiscard:
+		removeDraft(data.ObjectivesDraftName)
+	}
+}
+
+// draftLoaded reports whether a draft was read, logging drafts that exist but can't be read
+func draftLoaded(name string, err error) bool {
+	if err == nil {
+		return true
+	}
+	if !os.IsNotExist(err) {
+		slog.Warn("failed to read draft", "draft", name, "err", err)
+	}
+	return false
+}
+
+// promptDraftChoice asks whether to resume, discard or keep a draft for the next launch
+func promptDraftChoice(reader input.InputReader) draftChoice {
+	fmt.Print("Resume, discard or keep it for later? (r/d/k): ")
+
+	for {
+		char, _, err := reader.GetKey()
+		if err != nil {
+			fmt.Printf("Error reading input: %v\n", err)
+			return draftKeep
+		}
+
+		switch char {
+		case 'r', 'R':
+			fmt.Println("r")
+			return draftResume
+		case 'd', 'D':
+			fmt.Println("d")
+			fmt.Println("Draft discarded.")
+			return draftDiscard
+		case 'k', 'K':
+			fmt.Println("k")
+			return draftKeep
+		}
+	}
+}
+
+func removeDraft(name string) {
+	if err := data.DiscardDraft(name); err != nil {
+		fmt.Printf("Error removing draft: %v\n", err)
+	}
+}
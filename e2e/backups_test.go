@@ -0,0 +1,107 @@
+package e2e
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeBackupSnapshot writes a backup snapshot of notes the way the program names them, under a fake HOME
+func writeBackupSnapshot(t *testing.T, h *TestHarness, name string, notes map[string]string) {
+	t.Helper()
+	backupDir := filepath.Join(h.TempDir, "home", "Documents", "notes-backups")
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		t.Fatalf("Failed to create backup dir: %v", err)
+	}
+
+	file, err := os.Create(filepath.Join(backupDir, name))
+	if err != nil {
+		t.Fatalf("Failed to create backup: %v", err)
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for path, content := range notes {
+		header := &tar.Header{Name: path, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("Failed to write backup: %v", err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+}
+
+func TestBackups_ListsSnapshotsNewestFirst(t *testing.T) {
+	h := NewTestHarness(t)
+	h.Env = append(h.Env, "HOME="+filepath.Join(h.TempDir, "home"))
+	writeBackupSnapshot(t, h, "notes-2025-11-27T090000.tar.gz", map[string]string{"plan.md": "old"})
+	writeBackupSnapshot(t, h, "notes-2025-11-28T090000.tar.gz", map[string]string{"plan.md": "new", "ideas.md": "x"})
+
+	stdout, _, err := h.RunCommand("backups\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	newest := strings.Index(stdout, "1) 2025-11-28 09:00:00  2 notes")
+	oldest := strings.Index(stdout, "2) 2025-11-27 09:00:00  1 notes")
+	if newest < 0 || oldest < 0 || newest > oldest {
+		t.Errorf("Expected both backups listed newest first, got:\n%s", stdout)
+	}
+}
+
+func TestBackups_NoBackups(t *testing.T) {
+	h := NewTestHarness(t)
+	h.Env = append(h.Env, "HOME="+filepath.Join(h.TempDir, "home"))
+
+	stdout, _, err := h.RunCommand("backups\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "No backups yet") {
+		t.Errorf("Expected no backups, got:\n%s", stdout)
+	}
+}
+
+func TestRestore_SingleNoteFromBackup(t *testing.T) {
+	h := NewTestHarness(t)
+	h.Env = append(h.Env, "HOME="+filepath.Join(h.TempDir, "home"))
+	h.CreateTodoWithContent("plan.md", "plan", "edited by mistake", Today(), 2)
+	h.CreateTodoWithContent("ideas.md", "ideas", "keep me", Today(), 2)
+	writeBackupSnapshot(t, h, "notes-2025-11-28T090000.tar.gz", map[string]string{"plan.md": "# Plan\nthe good version\n"})
+
+	stdout, _, err := h.RunCommand("restore 1 plan\nyexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "Restored plan.md from the backup of 2025-11-28 09:00:00") {
+		t.Errorf("Expected plan.md restored, got:\n%s", stdout)
+	}
+	h.AssertFileContent("plan.md", "# Plan\nthe good version\n")
+	if !strings.Contains(h.ReadFileContent("ideas.md"), "keep me") {
+		t.Error("Expected other notes to be left alone")
+	}
+}
+
+func TestRestore_CancelledKeepsNote(t *testing.T) {
+	h := NewTestHarness(t)
+	h.Env = append(h.Env, "HOME="+filepath.Join(h.TempDir, "home"))
+	h.CreateTodoWithContent("plan.md", "plan", "current version", Today(), 2)
+	writeBackupSnapshot(t, h, "notes-2025-11-28T090000.tar.gz", map[string]string{"plan.md": "old version"})
+
+	stdout, _, err := h.RunCommand("restore 1\nnexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "Cancelled.") {
+		t.Errorf("Expected the restore to be cancelled, got:\n%s", stdout)
+	}
+	if !strings.Contains(h.ReadFileContent("plan.md"), "current version") {
+		t.Error("Expected plan.md to keep its current version")
+	}
+}
@@ -0,0 +1,38 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConflicts_ListsNotesWithConflictMarkers(t *testing.T) {
+	h := NewTestHarness(t)
+	dateStr := Today()
+	h.CreateTodoWithContent("agenda.md", "agenda", "<<<<<<< local\nfrom the laptop\n=======\nfrom the desktop\n>>>>>>> remote", dateStr, 2)
+	h.CreateTodoWithContent("clean.md", "clean", "=======\nnot a conflict", dateStr, 2)
+
+	stdout, _, err := h.RunCommand("conflicts\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "Notes with sync conflicts (1)") || !strings.Contains(stdout, "agenda") {
+		t.Errorf("Expected agenda listed as conflicted, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "clean") {
+		t.Errorf("Expected clean not to be listed, got:\n%s", stdout)
+	}
+}
+
+func TestConflicts_NoConflicts(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodoWithContent("agenda.md", "agenda", "nothing to fix", Today(), 2)
+
+	stdout, _, err := h.RunCommand("conflicts\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "No notes with sync conflicts") {
+		t.Errorf("Expected no conflicts, got:\n%s", stdout)
+	}
+}
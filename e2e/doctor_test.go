@@ -0,0 +1,86 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func createUnhealthyNotes(h *TestHarness) {
+	h.CreateTestFile("parent.md", "---\ntitle: Parent\ntags: [work]\ndone: false\nobjective-role: parent\nobjective-id: abcd1234\n---\n\nSee [[Child]] and [[Missing]]\n")
+	h.CreateTestFile("child.md", "---\ntitle: Child\ndate-due: 2024/3/5\npriority: 7\ndone: false\nobjective-id: abcd1234\n---\n\nchild\n")
+	h.CreateTestFile("stray.md", "---\ntitle: Stray\ndone: false\nobjective-id: deadbeef\n---\n\nstray\n")
+}
+
+func TestDoctor_ReportsProblems(t *testing.T) {
+	h := NewTestHarness(t)
+	createUnhealthyNotes(h)
+
+	stdout, _, err := h.RunCommand("doctor\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		"Checked 3 notes, found 8 problems",
+		"Orphan notes (1):",
+		"stray.md  no links in or out  (no automated fix)",
+		"Dead links (1):",
+		"parent.md  [[Missing]]",
+		"Children without a parent objective (1):",
+		"Objectives without the objective tag (1):",
+		`child.md  date-due: "2024/3/5"  (fix: rewrite as 2024-03-05)`,
+		"Priorities outside 1-3 (1):",
+		"Notes without a priority (2):",
+		"stray.md  no priority  (fix: set to 2)",
+		"Run doctor --fix",
+	} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+	h.VerifyFileContains("child.md", "date-due: 2024/3/5")
+}
+
+func TestDoctor_FixDryRunChangesNothing(t *testing.T) {
+	h := NewTestHarness(t)
+	createUnhealthyNotes(h)
+
+	stdout, _, err := h.RunCommand("doctor --fix --dry-run\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{"3 notes will change:", "child.md: date-due -> 2024-03-05, priority -> 2", "Dry run, no notes were changed"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+	h.VerifyFileContains("child.md", "date-due: 2024/3/5")
+	h.VerifyFileContains("stray.md", "objective-id: deadbeef")
+}
+
+func TestDoctor_FixAppliesOnConfirmation(t *testing.T) {
+	h := NewTestHarness(t)
+	createUnhealthyNotes(h)
+
+	stdout, _, err := h.RunCommand("doctor --fix\nyexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "Fixed 3 notes") {
+		t.Errorf("Expected fixed count, got:\n%s", stdout)
+	}
+	h.VerifyFileContains("child.md", "date-due: 2024-03-05")
+	h.VerifyFileContains("child.md", "priority: 2")
+	h.VerifyFileContains("parent.md", "tags: [work objective]")
+	h.VerifyFileNotContains("stray.md", "objective-id")
+
+	stdout, _, err = h.RunCommand("doctor\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "found 2 problems") {
+		t.Errorf("Expected only the orphan and dead link to remain, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,133 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// crashInWeekPlanner moves the todo to Wednesday in the week planner and kills the program before saving
+func crashInWeekPlanner(t *testing.T, h *TestHarness) {
+	t.Helper()
+	cmd, stdout := startCLI(t, h, "wp\nMw", "Moved todo to Wednesday")
+	cmd.Process.Kill()
+	waitForExit(t, cmd, stdout)
+}
+
+func weekPlanDraftPath(h *TestHarness) string {
+	return filepath.Join(h.NotesDir, ".cli-notes", "week-plan-draft.json")
+}
+
+func TestDrafts_ResumeWeekPlanAfterCrash(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("move-test.md", "Task to Move", []string{}, MondayThisWeek(), false, 1)
+	crashInWeekPlanner(t, h)
+
+	draft, err := os.ReadFile(weekPlanDraftPath(h))
+	if err != nil {
+		t.Fatalf("Expected the move to be journaled before the crash: %v", err)
+	}
+	if !strings.Contains(string(draft), `"to": "`+WednesdayThisWeek()+`"`) {
+		t.Errorf("Expected the move in the draft, got:\n%s", draft)
+	}
+
+	stdout, _, err := h.RunCommand("r\x13qexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Unsaved week plan changes from") || !strings.Contains(stdout, "Task to Move: "+MondayThisWeek()+" -> "+WednesdayThisWeek()) {
+		t.Errorf("Expected the draft to be offered, got:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "Resumed 1 unsaved moves") {
+		t.Errorf("Expected the planner to resume the move, got:\n%s", stdout)
+	}
+	if fm := h.ParseFrontmatter("move-test.md"); fm.DateDue != WednesdayThisWeek() {
+		t.Errorf("Expected the resumed move to be saved, got due date %s", fm.DateDue)
+	}
+	if _, err := os.Stat(weekPlanDraftPath(h)); !os.IsNotExist(err) {
+		t.Errorf("Expected the draft to be gone once the plan was saved")
+	}
+}
+
+func TestDrafts_DiscardWeekPlanDraft(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("move-test.md", "Task to Move", []string{}, MondayThisWeek(), false, 1)
+	crashInWeekPlanner(t, h)
+
+	stdout, _, err := h.RunCommand("dexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Draft discarded.") {
+		t.Errorf("Expected the draft to be discarded, got:\n%s", stdout)
+	}
+	if _, err := os.Stat(weekPlanDraftPath(h)); !os.IsNotExist(err) {
+		t.Errorf("Expected the draft to be removed")
+	}
+	if fm := h.ParseFrontmatter("move-test.md"); fm.DateDue != MondayThisWeek() {
+		t.Errorf("Expected the note unchanged, got due date %s", fm.DateDue)
+	}
+}
+
+func TestDrafts_KeepWeekPlanDraftForLater(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("move-test.md", "Task to Move", []string{}, MondayThisWeek(), false, 1)
+	crashInWeekPlanner(t, h)
+
+	if _, _, err := h.RunCommand("kexit\n"); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	stdout, _, err := h.RunCommand("dexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Unsaved week plan changes from") {
+		t.Errorf("Expected the kept draft to be offered again, got:\n%s", stdout)
+	}
+}
+
+func TestDrafts_ResumeSkipsMovesOfChangedNotes(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("move-test.md", "Task to Move", []string{}, MondayThisWeek(), false, 1)
+	crashInWeekPlanner(t, h)
+
+	// The note was moved to Friday elsewhere after the crash
+	content := strings.Replace(h.ReadFileContent("move-test.md"), "date-due: "+MondayThisWeek(), "date-due: "+FridayThisWeek(), 1)
+	h.CreateTestFile("move-test.md", content)
+
+	stdout, _, err := h.RunCommand("rqexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Resumed 0 unsaved moves") || !strings.Contains(stdout, "skipped 1 whose notes changed since: Task to Move") {
+		t.Errorf("Expected the stale move to be skipped, got:\n%s", stdout)
+	}
+	if fm := h.ParseFrontmatter("move-test.md"); fm.DateDue != FridayThisWeek() {
+		t.Errorf("Expected the newer due date to be kept, got %s", fm.DateDue)
+	}
+}
+
+func TestDrafts_ResumeObjectivesViewAfterCrash(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateObjective("project-objective.md", "Project Objective", "test1234", "Project goals and tasks")
+	h.CreateLinkedTodo("child-todo.md", "Child Todo", "test1234", "- [ ] Task One", Today(), 1)
+
+	cmd, stdout := startCLI(t, h, "ob\no", "OPEN TASKS")
+	cmd.Process.Kill()
+	waitForExit(t, cmd, stdout)
+
+	output, _, err := h.RunCommand("rqqexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(output, "The objectives view was open on project-objective.md") {
+		t.Errorf("Expected the objectives draft to be offered, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Child Todo") {
+		t.Errorf("Expected the objective to open again, got:\n%s", output)
+	}
+	if _, err := os.Stat(filepath.Join(h.NotesDir, ".cli-notes", "objectives-draft.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected the draft to be gone once the view was left")
+	}
+}
@@ -0,0 +1,89 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func createStandups(h *TestHarness) {
+	h.CreateTestFile("standup-2025-01-03.md", "---\ntitle: standup\ndate-created: 2025-01-03\ndone: true\n---\n\n# standup\n")
+	h.CreateTestFile("standup-2025-01-10.md", "---\ntitle: standup\ndate-created: 2025-01-10\ndone: true\n---\n\n# standup\n")
+}
+
+func TestGL_ShowsAllCandidatesOfAmbiguousLink(t *testing.T) {
+	h := NewTestHarness(t)
+	dateStr := Today()
+	createStandups(h)
+	h.CreateTodo("review-"+dateStr+".md", "review", []string{}, dateStr, false, 1)
+	h.CreateTestFile("review-"+dateStr+".md", strings.Replace(h.ReadFileContent("review-"+dateStr+".md"), "Todo content", "See [[standup]]", 1))
+
+	stdout, _, err := h.RunCommand("gt\n\x1b[Bgl\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{"Ambiguous links (1):", "[[standup]] matches 2 notes:", "standup-2025-01-03.md", "standup-2025-01-10.md"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+}
+
+func TestDupes_DryRunChangesNothing(t *testing.T) {
+	h := NewTestHarness(t)
+	createStandups(h)
+	h.CreateTestFile("meeting.md", "---\ntitle: meeting\ndate-created: 2025-01-11\ndone: true\n---\n\nSee [[standup]]\n")
+
+	// The first run adds the IDs every note gets on startup
+	if _, _, err := h.RunCommand("exit\n"); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	notes := []string{"standup-2025-01-03.md", "standup-2025-01-10.md", "meeting.md"}
+	before := make(map[string]string)
+	for _, note := range notes {
+		before[note] = h.ReadFileContent(note)
+	}
+
+	stdout, _, err := h.RunCommand("dupes --dry-run\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{"Duplicate titles (1):", "standup (2 notes)", "standup-2025-01-03.md: standup -> standup 2025-01-03", "meeting.md: 1 links -> standup-2025-01-10.md", "Dry run"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+	for _, note := range notes {
+		if after := h.ReadFileContent(note); after != before[note] {
+			t.Errorf("Expected %s to be unchanged by the dry run, got:\n%s", note, after)
+		}
+	}
+}
+
+func TestDupes_QualifiesLinks(t *testing.T) {
+	h := NewTestHarness(t)
+	createStandups(h)
+	h.CreateTestFile("meeting.md", "---\ntitle: meeting\ndate-created: 2025-01-11\ndone: true\n---\n\nSee [[standup]]\n")
+
+	_, _, err := h.RunCommand("dupes\nqexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	h.VerifyFileContains("meeting.md", "See [[id:"+h.NoteID("standup-2025-01-10.md")+"|standup]]")
+}
+
+func TestDupes_RenamesOlderNotesWithDate(t *testing.T) {
+	h := NewTestHarness(t)
+	createStandups(h)
+
+	_, _, err := h.RunCommand("dupes\nrexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	h.VerifyFileContains("standup-2025-01-03.md", "title: standup 2025-01-03")
+	h.VerifyFileContains("standup-2025-01-03.md", "# standup 2025-01-03")
+	h.VerifyFileContains("standup-2025-01-10.md", "title: standup\n")
+}
@@ -0,0 +1,35 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDateRangeQuery_ExpandsEmbeds(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTestFile("payments.md", "---\ntitle: payments\ndone: true\n---\n\n## Status\nPayments shipped\n\n## Later\nNot embedded\n")
+	h.CreateTestFile("report.md", "---\ntitle: report\ndate-created: "+Today()+"\ndate-due: "+Today()+"\ntags: [todo]\ndone: true\n---\n\n![[payments#Status]]\n![[roadmap]]\n")
+
+	stdout, _, err := h.RunCommand("gd 2020-01-01 2099-01-01\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Created date range query note") {
+		t.Fatalf("Expected the summary note to be created, got:\n%s", stdout)
+	}
+
+	summary := ""
+	for _, name := range h.ListFiles() {
+		if strings.HasPrefix(name, "Date Range Query") {
+			summary = name
+		}
+	}
+	if summary == "" {
+		t.Fatalf("Expected a summary note, got files %v", h.ListFiles())
+	}
+
+	h.VerifyFileContains(summary, "Payments shipped")
+	h.VerifyFileContains(summary, "[missing embed: roadmap]")
+	h.VerifyFileNotContains(summary, "Not embedded")
+	h.VerifyFileNotContains(summary, "![[payments#Status]]")
+}
@@ -0,0 +1,90 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createGraphNotes(h *TestHarness) {
+	dateStr := Today()
+	h.CreateCompletedObjective("launch.md", "Launch", "abcd1234", "See [[Spec]]")
+	h.CreateLinkedTodo("ship-"+dateStr+".md", "Ship", "abcd1234", "ship it", dateStr, 1)
+	h.CreateTestFile("spec.md", "---\ntitle: Spec\ntags: [work]\ndone: true\n---\n\nBased on [[Research]]\n")
+	h.CreateTestFile("research.md", "---\ntitle: Research\ndone: true\n---\n\nreading list\n")
+}
+
+func TestExportGraph_WholeVaultDOT(t *testing.T) {
+	h := NewTestHarness(t)
+	createGraphNotes(h)
+
+	stdout, _, err := h.RunCommand("export-graph dot --tags\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		"digraph notes {",
+		`"launch.md" -> "spec.md" [kind="link"];`,
+		`"spec.md" -> "research.md" [kind="link"];`,
+		`"launch.md" -> "ship-` + Today() + `.md" [kind="objective", style=bold];`,
+		`"spec.md" -> "tag:work" [kind="tag"`,
+		`type="objective"`,
+	} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+}
+
+func TestExportGraph_CentredOnSelectedNote(t *testing.T) {
+	h := NewTestHarness(t)
+	createGraphNotes(h)
+
+	stdout, _, err := h.RunCommand("gt\n\x1b[Bexport-graph mermaid --depth 1\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "flowchart LR") || !strings.Contains(stdout, `["Launch"]`) || !strings.Contains(stdout, `["Ship"]`) {
+		t.Errorf("Expected the note and its objective, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, `["Spec"]`) {
+		t.Errorf("Expected notes 2 hops away to be left out, got:\n%s", stdout)
+	}
+}
+
+func TestExportGraph_WritesJSONFile(t *testing.T) {
+	h := NewTestHarness(t)
+	createGraphNotes(h)
+
+	stdout, _, err := h.RunCommand("export-graph json --out graph.json\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "Exported 4 notes and 3 links to graph.json") {
+		t.Errorf("Expected export summary, got:\n%s", stdout)
+	}
+	content, err := os.ReadFile(filepath.Join(h.TempDir, "graph.json"))
+	if err != nil {
+		t.Fatalf("Expected graph.json to be written: %v", err)
+	}
+	if !strings.Contains(string(content), `"id": "spec.md"`) || !strings.Contains(string(content), `"kind": "objective"`) {
+		t.Errorf("Unexpected JSON export:\n%s", content)
+	}
+}
+
+func TestExportGraph_UnknownFormat(t *testing.T) {
+	h := NewTestHarness(t)
+
+	stdout, _, err := h.RunCommand("export-graph png\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, `unknown graph format "png"`) {
+		t.Errorf("Expected unknown format error, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,73 @@
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// lineOf returns the 1-based line of a note file holding the text
+func lineOf(h *TestHarness, fileName, text string) int {
+	for i, line := range strings.Split(h.ReadFileContent(fileName), "\n") {
+		if line == text {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func TestOpen_HeadingLinkJumpsToLine(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTestFile("meeting.md", "---\ntitle: meeting\ndone: true\n---\n\n# meeting\n\n## Plan\nShip it ^ship\n")
+
+	stdout, _, err := h.RunCommand("o [[meeting#Plan|the plan]]\no meeting^ship\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		fmt.Sprintf("+%d notes/meeting.md", lineOf(h, "meeting.md", "## Plan")),
+		fmt.Sprintf("+%d notes/meeting.md", lineOf(h, "meeting.md", "Ship it ^ship")),
+	} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+}
+
+func TestOpen_MissingHeadingOpensTop(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTestFile("meeting.md", "---\ntitle: meeting\ndone: true\n---\n\n# meeting\n")
+
+	stdout, _, err := h.RunCommand("o meeting#Missing\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "Section not found") {
+		t.Errorf("Expected a missing section message, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "+0") {
+		t.Errorf("Expected no line argument, got:\n%s", stdout)
+	}
+}
+
+func TestGL_ResolvesHeadingAndAliasLinks(t *testing.T) {
+	h := NewTestHarness(t)
+	dateStr := Today()
+	h.CreateTestFile("meeting.md", "---\ntitle: meeting\ndone: true\n---\n\n## Plan\n")
+	h.CreateTodo("review-"+dateStr+".md", "review", []string{}, dateStr, false, 1)
+	h.CreateTestFile("review-"+dateStr+".md", strings.Replace(h.ReadFileContent("review-"+dateStr+".md"), "Todo content", "See [[meeting#Plan|the plan]]", 1))
+
+	stdout, _, err := h.RunCommand("gt\n\x1b[Bgl\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "meeting.md") {
+		t.Errorf("Expected the linked note in output, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "Unresolved links") {
+		t.Errorf("Expected the heading link to resolve, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,37 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelpCommand(t *testing.T) {
+	h := NewTestHarness(t)
+
+	stdout, _, err := h.RunCommand("help\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "Weekly planner") {
+		t.Errorf("Expected help to list commands, got:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "Aliases and macros:") {
+		t.Errorf("Expected help to list aliases, got:\n%s", stdout)
+	}
+}
+
+func TestCommandAlias(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("planned-2025-11-28.md", "planned", []string{"todo"}, "2025-11-28", false, 2)
+
+	// "wk" is an alias for the week planner
+	stdout, _, err := h.RunCommand("wk\nqexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if strings.Contains(stdout, "Unknown command") {
+		t.Errorf("Expected alias to resolve, got:\n%s", stdout)
+	}
+}
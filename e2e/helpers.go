@@ -269,6 +269,17 @@ func (h *TestHarness) ReadFileContent(filename string) string {
 	return string(content)
 }
 
+// NoteID returns the stable id from a file's frontmatter
+func (h *TestHarness) NoteID(filename string) string {
+	for _, line := range strings.Split(h.ReadFileContent(filename), "\n") {
+		if strings.HasPrefix(line, "id: ") {
+			return strings.TrimPrefix(line, "id: ")
+		}
+	}
+	h.t.Fatalf("File %s has no id in its frontmatter", filename)
+	return ""
+}
+
 // VerifyTodoMarkedComplete verifies a specific line contains a completed todo [x]
 func (h *TestHarness) VerifyTodoMarkedComplete(filename string, lineNumber int) {
 	content := h.ReadFileContent(filename)
@@ -0,0 +1,82 @@
+package e2e
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// commitNotes commits the notes directory, putting it under git first
+func commitNotes(h *TestHarness, message string) {
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", message},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = h.NotesDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			h.t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+}
+
+func TestHist_DiffsAndRestoresVersion(t *testing.T) {
+	h := NewTestHarness(t)
+	dateStr := Today()
+	filename := "plan-" + dateStr + ".md"
+	h.CreateTodoWithContent(filename, "plan", "first draft", dateStr, 1)
+	commitNotes(h, "create plan")
+	h.CreateTodoWithContent(filename, "plan", "second draft", dateStr, 1)
+	commitNotes(h, "edit plan")
+
+	stdout, _, err := h.RunCommand("gt\n\x1b[Bhist\njryqexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{"HISTORY: plan (2 commits)", "edit plan", "- first draft", "+ second draft", "Restored " + filename} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+	h.VerifyFileContains(filename, "first draft")
+	h.VerifyFileNotContains(filename, "second draft")
+}
+
+func TestHist_RestoresDeletedNote(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateObjective("launch.md", "Launch", "abcd1234", "Ship it")
+	commitNotes(h, "create launch")
+	if err := os.Remove(filepath.Join(h.NotesDir, "launch.md")); err != nil {
+		t.Fatalf("Failed to delete launch.md: %v", err)
+	}
+	commitNotes(h, "delete launch")
+
+	stdout, _, err := h.RunCommand("hist --deleted\nryqexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{"DELETED NOTES (1)", "launch.md", "Ship it", "Restored launch.md"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+	h.VerifyFileContains("launch.md", "Ship it")
+}
+
+func TestHist_WithoutGit(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTestFile("note.md", "---\ntitle: note\n---\nbody")
+
+	stdout, _, err := h.RunCommand("hist --deleted\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "notes are not under git yet") {
+		t.Errorf("Expected a message that the notes are not under git, got:\n%s", stdout)
+	}
+}
@@ -205,6 +205,30 @@ func TestGG_Quit(t *testing.T) {
 	// Should exit cleanly after q
 }
 
+func TestGG_DepthAndPath(t *testing.T) {
+	h := NewTestHarness(t)
+	dateStr := Today()
+
+	// center -> target -> another
+	h.CreateTodoWithContent("center-"+dateStr+".md", "Center Note", "[[Target]]", dateStr, 1)
+	h.CreateTodoWithContent("target-"+dateStr+".md", "Target", "[[Another]]", dateStr, 2)
+	h.CreateTodo("another-"+dateStr+".md", "Another", []string{}, dateStr, false, 3)
+
+	// Run: select center, open graph, show 2 hops, find the path to Another, quit
+	input := "gt\n\x1b[Bgg\n+pAnother\nq\nexit\n"
+
+	stdout, _, err := h.RunCommand(input)
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{"Depth: 2", "── 2 hops away ──", "Another  (via Target)", "Path (2 hops): Center Note → Target → Another"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+}
+
 // ============================================
 // ln (Link Note) Tests
 // ============================================
@@ -234,8 +258,8 @@ func TestLN_AddsLinkToNote(t *testing.T) {
 		// Success indication
 	}
 
-	// Verify the file content includes the link
-	h.AssertFileContent(sourceFile, "[[Target Note]]")
+	// Verify the file content includes an ID-backed link to the target
+	h.AssertFileContent(sourceFile, "[[id:"+h.NoteID(targetFile)+"|Target Note]]")
 }
 
 // ============================================
@@ -264,8 +288,8 @@ func TestGS_TwoNoteLinking(t *testing.T) {
 		// Success indication
 	}
 
-	// Verify the file content includes the link
-	h.AssertFileContent(sourceFile, "[[Target Note]]")
+	// Verify the file content includes an ID-backed link to the target
+	h.AssertFileContent(sourceFile, "[[id:"+h.NoteID(targetFile)+"|Target Note]]")
 }
 
 func TestGS_PendingLinkBannerShows(t *testing.T) {
@@ -0,0 +1,50 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLog_NothingLoggedYet(t *testing.T) {
+	h := NewTestHarness(t)
+
+	stdout, _, err := h.RunCommand("log\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Nothing logged yet") {
+		t.Errorf("Expected an empty log, got:\n%s", stdout)
+	}
+}
+
+func TestLog_ShowsLatestEntries(t *testing.T) {
+	h := NewTestHarness(t)
+	stateDir := filepath.Join(h.NotesDir, ".cli-notes")
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		t.Fatalf("Failed to create state directory: %v", err)
+	}
+	entries := strings.Join([]string{
+		`time=2026-10-19T09:00:00.000Z level=INFO msg="backup snapshot taken" path=notes-1.tar.gz`,
+		`time=2026-10-19T09:05:00.000Z level=ERROR msg="git commit failed" err="index.lock exists"`,
+		`time=2026-10-19T09:10:00.000Z level=WARN msg="git sync left notes with conflicts" notes=plan.md`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(stateDir, "cli-notes.log"), []byte(entries), 0644); err != nil {
+		t.Fatalf("Failed to write log: %v", err)
+	}
+
+	stdout, _, err := h.RunCommand("log 2\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Last 2 log entries") {
+		t.Errorf("Expected the last 2 entries, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "backup snapshot taken") {
+		t.Errorf("Expected the oldest entry to be left out, got:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "git commit failed") || !strings.Contains(stdout, "git sync left notes with conflicts") {
+		t.Errorf("Expected the latest entries, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,50 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func createMentions(h *TestHarness) string {
+	dateStr := Today()
+	filename := "payments-" + dateStr + ".md"
+	h.CreateTodo(filename, "payments", []string{}, dateStr, false, 1)
+	h.CreateTestFile("meeting.md", "---\ntitle: meeting\ndone: true\n---\n\nTalked about payments\n")
+	h.CreateTestFile("retro.md", "---\ntitle: retro\ndone: true\n---\n\nPayments slipped, see [[payments]]\n")
+	return filename
+}
+
+func TestUM_ListsAndLinksOneMention(t *testing.T) {
+	h := NewTestHarness(t)
+	filename := createMentions(h)
+
+	stdout, _, err := h.RunCommand("gt\n\x1b[Bum\n1\nq\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{"Unlinked mentions of payments (2):", "1) meeting.md:", "Talked about payments", "2) retro.md:", "Linked 1 mentions in 1 notes"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+	h.VerifyFileContains("meeting.md", "Talked about [[id:"+h.NoteID(filename)+"|payments]]")
+	h.VerifyFileContains("retro.md", "Payments slipped, see [[payments]]")
+}
+
+func TestUM_LinksAllMentions(t *testing.T) {
+	h := NewTestHarness(t)
+	filename := createMentions(h)
+
+	stdout, _, err := h.RunCommand("gt\n\x1b[Bum\na\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "Linked 2 mentions in 2 notes") {
+		t.Errorf("Expected linked count, got:\n%s", stdout)
+	}
+	id := h.NoteID(filename)
+	h.VerifyFileContains("meeting.md", "Talked about [[id:"+id+"|payments]]")
+	h.VerifyFileContains("retro.md", "[[id:"+id+"|Payments]] slipped, see [[payments]]")
+}
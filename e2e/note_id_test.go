@@ -0,0 +1,50 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStartup_AddsIDsToExistingNotes(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTestFile("legacy.md", "---\ntitle: legacy\ndone: true\n---\n\nOld note\n")
+	h.CreateTestFile("plain.md", "No frontmatter here\n")
+
+	stdout, _, err := h.RunCommand("exit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if id := h.NoteID("legacy.md"); len(id) != 8 {
+		t.Errorf("Expected an 8-character ID, got '%s'", id)
+	}
+	h.VerifyFileContains("legacy.md", "Old note")
+	h.VerifyFileNotContains("plain.md", "id:")
+	if !strings.Contains(stdout, "Added IDs to 1 notes") {
+		t.Errorf("Expected migrated note count, got:\n%s", stdout)
+	}
+}
+
+func TestRename_KeepsIDAndRelabelsIDLinks(t *testing.T) {
+	h := NewTestHarness(t)
+	dateStr := Today()
+	filename := "payments-" + dateStr + ".md"
+	h.CreateTodo(filename, "payments", []string{}, dateStr, false, 1)
+	h.CreateTestFile("meeting.md", "---\ntitle: meeting\ndone: true\nid: 0000aaaa\n---\n\nDiscussed [[id:1234abcd|payments]]\n")
+
+	// Give the todo a known ID so the link points at it
+	content := strings.Replace(h.ReadFileContent(filename), "---\n\n", "id: 1234abcd\n---\n\n", 1)
+	h.CreateTestFile(filename, content)
+
+	_, _, err := h.RunCommand("gt\n\x1b[Br billing\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	renamed := "billing-" + dateStr + ".md"
+	h.AssertFileExists(renamed)
+	if id := h.NoteID(renamed); id != "1234abcd" {
+		t.Errorf("Expected the renamed note to keep ID 1234abcd, got '%s'", id)
+	}
+	h.VerifyFileContains("meeting.md", "[[id:1234abcd|billing]]")
+}
@@ -0,0 +1,51 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func createRelatedNotes(h *TestHarness) string {
+	dateStr := Today()
+	filename := "payments-" + dateStr + ".md"
+	h.CreateTodoWithContent(filename, "payments rollout", "Roll out payments on android", dateStr, 1)
+	h.CreateTestFile("android.md", "---\ntitle: android payments\ndone: true\n---\n\nThe payments screen on android\n")
+	h.CreateTestFile("holiday.md", "---\ntitle: holiday\ndone: true\n---\n\nBook flights\n")
+	return filename
+}
+
+func TestRel_ListsAndLinksRelatedNote(t *testing.T) {
+	h := NewTestHarness(t)
+	filename := createRelatedNotes(h)
+
+	stdout, _, err := h.RunCommand("gt\n\x1b[Brel\n1\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, expected := range []string{"Related to payments rollout (1):", "1) android payments  (100% similar text)", "Linked to \"android payments\""} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+		}
+	}
+	if strings.Contains(stdout, "holiday") {
+		t.Errorf("Expected unrelated notes to be left out, got:\n%s", stdout)
+	}
+	h.VerifyFileContains(filename, "[[id:"+h.NoteID("android.md")+"|android payments]]")
+	if !strings.Contains(stdout, "No related notes for payments rollout") {
+		t.Errorf("Expected the linked note to drop out of the list, got:\n%s", stdout)
+	}
+}
+
+func TestRel_RequiresSelectedNote(t *testing.T) {
+	h := NewTestHarness(t)
+	createRelatedNotes(h)
+
+	stdout, _, err := h.RunCommand("rel\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "No file selected") {
+		t.Errorf("Expected no file selected, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,28 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRename_RewritesLinksInOtherNotes(t *testing.T) {
+	h := NewTestHarness(t)
+	dateStr := Today()
+	filename := "payments-" + dateStr + ".md"
+	h.CreateTodo(filename, "payments", []string{}, dateStr, false, 1)
+	h.CreateTestFile("meeting.md", "---\ntitle: meeting\ndone: true\n---\n\nDiscussed [[payments|the payments work]] and [[payments#Plan]]\n")
+	h.CreateTestFile("other.md", "---\ntitle: other\ndone: true\n---\n\nNothing to see\n")
+
+	stdout, _, err := h.RunCommand("gt\n\x1b[Br billing\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	h.AssertFileExists("billing-" + dateStr + ".md")
+	h.VerifyFileContains("meeting.md", "[[billing|the payments work]]")
+	h.VerifyFileContains("meeting.md", "[[billing#Plan]]")
+	h.VerifyFileNotContains("meeting.md", "[[payments")
+	if !strings.Contains(stdout, "Updated links in 1 notes") {
+		t.Errorf("Expected updated note count, got:\n%s", stdout)
+	}
+}
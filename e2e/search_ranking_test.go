@@ -0,0 +1,49 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func createRankingNotes(h *TestHarness) {
+	h.CreateTestFile("aaa-mention.md", "---\ntitle: Weekly sync\ntags: [work]\ndone: false\n---\nWe talked about the budget once")
+	h.CreateTestFile("zzz-budget.md", "---\ntitle: Budget planning\ntags: [finance]\ndone: false\n---\nPlanning the yearly budgets")
+	h.CreateTestFile("mmm-plan.md", "---\ntitle: Plan\ntags: [misc]\ndone: false\n---\nA budget for planning trips")
+}
+
+func TestGq_RanksByRelevance(t *testing.T) {
+	h := NewTestHarness(t)
+	createRankingNotes(h)
+
+	stdout, _, err := h.RunCommand("gq budget\n")
+	if err != nil {
+		t.Fatalf("Failed to run gq: %v", err)
+	}
+
+	title := strings.Index(stdout, "zzz-budget.md")
+	mention := strings.Index(stdout, "aaa-mention.md")
+	if title < 0 || mention < 0 || title > mention {
+		t.Errorf("Expected the title match before the passing mention, got:\n%s", stdout)
+	}
+}
+
+func TestGs_PhraseQuery(t *testing.T) {
+	h := NewTestHarness(t)
+	createRankingNotes(h)
+
+	stdout, _, err := h.RunCommand("gs\n\"budget for planning\"\nq\n")
+	if err != nil {
+		t.Fatalf("Failed to run gs: %v", err)
+	}
+	if !strings.Contains(stdout, "1 match") || !strings.Contains(stdout, "Plan") {
+		t.Errorf("Expected only the note with the phrase, got:\n%s", stdout)
+	}
+
+	stdout, _, err = h.RunCommand("gs\nbudgets planning\nq\n")
+	if err != nil {
+		t.Fatalf("Failed to run gs: %v", err)
+	}
+	if !strings.Contains(stdout, "2 match") {
+		t.Errorf("Expected stemmed words to match both planning notes, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,148 @@
+package e2e
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShutdown_CtrlCAtThePromptExits(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("task.md", "Task", []string{}, Today(), false, 1)
+
+	stdout, _, err := h.RunCommand("\x03gt\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Exiting...") {
+		t.Errorf("Expected Ctrl+C to exit, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "task.md") {
+		t.Errorf("Expected no command to run after Ctrl+C, got:\n%s", stdout)
+	}
+}
+
+func TestShutdown_CtrlCInWeekPlannerOffersToSave(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("move-test.md", "Task to Move", []string{}, MondayThisWeek(), false, 1)
+
+	// Move to Wednesday, then Ctrl+C and save; the trailing command must not run
+	stdout, _, err := h.RunCommand("wp\nMw\x03ygt\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Save before exiting?") || !strings.Contains(stdout, "Changes saved successfully!") {
+		t.Errorf("Expected a save prompt, got:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "Exiting...") {
+		t.Errorf("Expected Ctrl+C to exit the program, got:\n%s", stdout)
+	}
+	if fm := h.ParseFrontmatter("move-test.md"); fm.DateDue != WednesdayThisWeek() {
+		t.Errorf("Expected the move to be saved, got due date %s", fm.DateDue)
+	}
+}
+
+func TestShutdown_CtrlCInWeekPlannerCanBeCancelled(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("move-test.md", "Task to Move", []string{}, MondayThisWeek(), false, 1)
+
+	stdout, _, err := h.RunCommand("wp\nMw\x03c\x13q")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Returning to week planner") {
+		t.Errorf("Expected to return to the planner, got:\n%s", stdout)
+	}
+	if fm := h.ParseFrontmatter("move-test.md"); fm.DateDue != WednesdayThisWeek() {
+		t.Errorf("Expected the move to be saved after returning, got due date %s", fm.DateDue)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe to read while the program writes to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// startCLI starts the program with the input, keeping stdin open so it keeps waiting for keys,
+// and returns once the output contains ready
+func startCLI(t *testing.T, h *TestHarness, input, ready string) (*exec.Cmd, *syncBuffer) {
+	t.Helper()
+	cmd := exec.Command(h.CLIPath)
+	cmd.Dir = h.TempDir
+	cmd.Env = h.Env
+	stdout := &syncBuffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stdout
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatalf("Failed to get stdin pipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	t.Cleanup(func() { cmd.Process.Kill() })
+
+	io.WriteString(stdin, input)
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(stdout.String(), ready) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Never saw %q:\n%s", ready, stdout.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return cmd, stdout
+}
+
+// waitForExit waits for the program to end after a signal
+func waitForExit(t *testing.T, cmd *exec.Cmd, stdout *syncBuffer) {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("The program didn't exit:\n%s", stdout.String())
+	}
+}
+
+func TestShutdown_SigtermKeepsWeekPlanDraft(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("move-test.md", "Task to Move", []string{}, MondayThisWeek(), false, 1)
+
+	cmd, stdout := startCLI(t, h, "wp\nMw", "Moved todo to Wednesday")
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("Failed to signal: %v", err)
+	}
+	waitForExit(t, cmd, stdout)
+
+	output := stdout.String()
+	if !strings.Contains(output, "Unsaved changes kept in") || !strings.Contains(output, "Exiting...") {
+		t.Errorf("Expected the changes to be kept and the program to exit, got:\n%s", output)
+	}
+	if fm := h.ParseFrontmatter("move-test.md"); fm.DateDue != MondayThisWeek() {
+		t.Errorf("Expected the note itself unchanged, got due date %s", fm.DateDue)
+	}
+	if _, err := os.Stat(filepath.Join(h.NotesDir, ".cli-notes", "week-plan-draft.json")); err != nil {
+		t.Errorf("Expected a week plan draft: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTagsCommand_ListsCounts(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("first.md", "first", []string{"work", "todo"}, FutureDate(1), false, 2)
+	h.CreateTodo("second.md", "second", []string{"todo"}, FutureDate(1), false, 2)
+
+	stdout, _, err := h.RunCommand("tags\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	todoIdx := strings.Index(stdout, "todo  2")
+	workIdx := strings.Index(stdout, "work  1")
+	if todoIdx < 0 || workIdx < 0 {
+		t.Fatalf("Expected tag counts, got:\n%s", stdout)
+	}
+	if todoIdx > workIdx {
+		t.Errorf("Expected most used tag first, got:\n%s", stdout)
+	}
+}
+
+func TestTagRename_DryRunLeavesNotesUnchanged(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("first.md", "first", []string{"work", "todo"}, FutureDate(1), false, 2)
+
+	stdout, _, err := h.RunCommand("tag-rename todo task --dry-run\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "first.md: [work todo] -> [work task]") {
+		t.Errorf("Expected preview of the change, got:\n%s", stdout)
+	}
+	h.VerifyFileContains("first.md", "tags: [work, todo]")
+}
+
+func TestTagRename_AppliesAfterConfirmation(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("first.md", "first", []string{"work", "todo"}, FutureDate(1), false, 2)
+	h.CreateTodo("second.md", "second", []string{"todo"}, FutureDate(1), false, 2)
+	h.CreateTodo("third.md", "third", []string{"work"}, FutureDate(1), false, 2)
+
+	stdout, _, err := h.RunCommand("tag-rename todo task\nyexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "Updated 2 notes") {
+		t.Errorf("Expected 2 notes updated, got:\n%s", stdout)
+	}
+	h.VerifyFileContains("first.md", "tags: [work task]")
+	h.VerifyFileContains("second.md", "tags: [task]")
+	h.VerifyFileContains("third.md", "tags: [work]")
+}
+
+func TestTagMerge_RefusesUnknownTarget(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("first.md", "first", []string{"todo"}, FutureDate(1), false, 2)
+
+	stdout, _, err := h.RunCommand("tag-merge todo missing\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "tag not found: missing") {
+		t.Errorf("Expected unknown tag error, got:\n%s", stdout)
+	}
+	h.VerifyFileContains("first.md", "tags: [todo]")
+}
+
+func TestTagsCommand_ShowsHierarchy(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("android.md", "android", []string{"work/payments/android"}, FutureDate(1), false, 2)
+	h.CreateTodo("hiring.md", "hiring", []string{"work/hiring"}, FutureDate(1), false, 2)
+
+	stdout, _, err := h.RunCommand("tags\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	for _, line := range []string{"work         2", "  hiring     1", "  payments   1", "    android  1"} {
+		if !strings.Contains(stdout, line) {
+			t.Errorf("Expected line %q in tag tree, got:\n%s", line, stdout)
+		}
+	}
+}
+
+func TestGetTagsAll_MatchesNestedTags(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("android.md", "android", []string{"work/payments/android"}, FutureDate(1), false, 2)
+	h.CreateTodo("workshop.md", "workshop", []string{"workshop"}, FutureDate(1), false, 2)
+
+	stdout, _, err := h.RunCommand("gta work\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if !strings.Contains(stdout, "android.md") {
+		t.Errorf("Expected nested tag to match, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "workshop.md") {
+		t.Errorf("Expected workshop not to match work, got:\n%s", stdout)
+	}
+}
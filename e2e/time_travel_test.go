@@ -0,0 +1,67 @@
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsOf_ListsTodosOverdueAtPastCommit(t *testing.T) {
+	h := NewTestHarness(t)
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	h.CreateTodo("late.md", "late", []string{"todo"}, yesterday, false, 1)
+	h.CreateTodo("later.md", "later", []string{"todo"}, FutureDate(7), false, 2)
+	commitNotes(h, "plan")
+	h.CreateTodo("late.md", "late", []string{"todo"}, yesterday, true, 1)
+	commitNotes(h, "finish late")
+
+	stdout, _, err := h.RunCommand("asof HEAD~1\nasof HEAD~1 open\nasof HEAD\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	sections := strings.Split(stdout, "todos as of")
+	if len(sections) != 4 {
+		t.Fatalf("Expected 3 listings, got:\n%s", stdout)
+	}
+	if !strings.Contains(sections[1], "\"plan\"") || !strings.Contains(sections[1], "late.md") || strings.Contains(sections[1], "later.md") {
+		t.Errorf("Expected late.md overdue at the plan commit, got:\n%s", sections[1])
+	}
+	if !strings.Contains(sections[2], "late.md") || !strings.Contains(sections[2], "later.md") {
+		t.Errorf("Expected both todos open at the plan commit, got:\n%s", sections[2])
+	}
+	if !strings.Contains(sections[3], "None") {
+		t.Errorf("Expected nothing overdue now, got:\n%s", sections[3])
+	}
+	h.AssertFrontmatterValue("late.md", func(fm Frontmatter) error {
+		if !fm.Done {
+			return fmt.Errorf("expected late.md to stay done on disk")
+		}
+		return nil
+	})
+}
+
+func TestChurn_ReportsMovedDueDates(t *testing.T) {
+	h := NewTestHarness(t)
+	h.CreateTodo("review.md", "API review", []string{"todo"}, FutureDate(1), false, 2)
+	h.CreateTodo("steady.md", "steady", []string{"todo"}, FutureDate(3), false, 2)
+	commitNotes(h, "plan")
+	h.CreateTodo("review.md", "API review", []string{"todo"}, FutureDate(2), false, 2)
+	commitNotes(h, "move review")
+	h.CreateTodo("review.md", "API review", []string{"todo"}, FutureDate(4), false, 2)
+	commitNotes(h, "move review again")
+
+	stdout, _, err := h.RunCommand("churn\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	expected := " 2  API review  " + FutureDate(1) + " → " + FutureDate(2) + " → " + FutureDate(4)
+	if !strings.Contains(stdout, expected) {
+		t.Errorf("Expected %q in output, got:\n%s", expected, stdout)
+	}
+	if strings.Contains(stdout, "steady") {
+		t.Errorf("Expected steady not to be reported, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,85 @@
+package e2e
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeMirror copies the notes into the ~/Documents/notes mirror with a manifest of their hashes,
+// the way the backup sync leaves it
+func writeMirror(t *testing.T, h *TestHarness, names ...string) string {
+	t.Helper()
+	// Let the first run give the notes their IDs, so the mirror matches what the program sees
+	if _, _, err := h.RunCommand("exit\n"); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	mirrorDir := filepath.Join(h.TempDir, "home", "Documents", "notes")
+	if err := os.MkdirAll(mirrorDir, 0755); err != nil {
+		t.Fatalf("Failed to create mirror: %v", err)
+	}
+
+	var manifest strings.Builder
+	for _, name := range names {
+		content := h.ReadFileContent(name)
+		if err := os.WriteFile(filepath.Join(mirrorDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write mirror: %v", err)
+		}
+		sum := sha256.Sum256([]byte(content))
+		fmt.Fprintf(&manifest, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	if err := os.WriteFile(filepath.Join(mirrorDir, ".manifest.sha256"), []byte(manifest.String()), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	return mirrorDir
+}
+
+func TestVerifyBackup_AllMatch(t *testing.T) {
+	h := NewTestHarness(t)
+	h.Env = append(h.Env, "HOME="+filepath.Join(h.TempDir, "home"))
+	h.CreateTodoWithContent("plan.md", "plan", "the plan", Today(), 2)
+	writeMirror(t, h, "plan.md")
+
+	stdout, _, err := h.RunCommand("verify-backup\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "1 notes checked, all match") {
+		t.Errorf("Expected the backup to match, got:\n%s", stdout)
+	}
+}
+
+func TestVerifyBackup_RepairsCorruptedNote(t *testing.T) {
+	h := NewTestHarness(t)
+	h.Env = append(h.Env, "HOME="+filepath.Join(h.TempDir, "home"))
+	h.CreateTodoWithContent("plan.md", "plan", "the plan", Today(), 2)
+	h.CreateTodoWithContent("ideas.md", "ideas", "some ideas", Today(), 2)
+	mirrorDir := writeMirror(t, h, "plan.md", "ideas.md")
+	corrupted := strings.Replace(h.ReadFileContent("plan.md"), "the plan", "the plxn", 1)
+	os.WriteFile(filepath.Join(mirrorDir, "plan.md"), []byte(corrupted), 0644)
+
+	stdout, _, err := h.RunCommand("verify-backup\nexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Changed since they were copied (1)") || !strings.Contains(stdout, "verify-backup --repair") {
+		t.Errorf("Expected plan.md reported as corrupt, got:\n%s", stdout)
+	}
+
+	stdout, _, err = h.RunCommand("verify-backup --repair\nyexit\n")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Repaired 1 notes in "+mirrorDir) {
+		t.Errorf("Expected plan.md repaired, got:\n%s", stdout)
+	}
+	repaired, _ := os.ReadFile(filepath.Join(mirrorDir, "plan.md"))
+	if string(repaired) != h.ReadFileContent("plan.md") {
+		t.Errorf("Expected the mirror copy to match the note again, got:\n%s", repaired)
+	}
+}
@@ -5,10 +5,10 @@ go 1.24.0
 toolchain go1.24.2
 
 require (
-	github.com/sahilm/fuzzy v0.1.1 // indirect
-	golang.org/x/sys v0.37.0 // indirect
-	golang.org/x/term v0.36.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/sahilm/fuzzy v0.1.1
+	golang.org/x/sys v0.37.0
+	golang.org/x/term v0.36.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/eiannone/keyboard v0.0.0-20220611211555-0d226195f203
@@ -0,0 +1,76 @@
+package input
+
+import (
+	"sync"
+
+	"github.com/eiannone/keyboard"
+)
+
+// keyPump reads the keys of one source, such as the keyboard, in a single long-lived goroutine
+// Keys are read one at a time on request, and a read its caller gave up on stays pending,
+// so its key goes to whoever reads the source next instead of being swallowed
+type keyPump struct {
+	read     func() (rune, keyboard.Key, error)
+	start    sync.Once
+	requests chan struct{}
+	keys     chan keyRead
+
+	mu      sync.Mutex
+	pending bool // A read was requested and its key not taken yet
+	waiting int  // Callers waiting for the key
+}
+
+type keyRead struct {
+	char rune
+	key  keyboard.Key
+	err  error
+}
+
+func newKeyPump(read func() (rune, keyboard.Key, error)) *keyPump {
+	return &keyPump{read: read, requests: make(chan struct{}, 1), keys: make(chan keyRead)}
+}
+
+// next returns the next key, or false when changed receives first and the read stays pending
+// A nil changed channel waits for the key
+func (p *keyPump) next(changed <-chan struct{}) (keyRead, bool) {
+	p.start.Do(func() { go p.run() })
+
+	p.mu.Lock()
+	p.waiting++
+	if !p.pending {
+		p.pending = true
+		p.requests <- struct{}{}
+	}
+	p.mu.Unlock()
+
+	select {
+	case read := <-p.keys:
+		p.mu.Lock()
+		p.waiting--
+		p.pending = false
+		p.mu.Unlock()
+		return read, true
+	case <-changed:
+		p.mu.Lock()
+		p.waiting--
+		p.mu.Unlock()
+		return keyRead{}, false
+	}
+}
+
+func (p *keyPump) run() {
+	for range p.requests {
+		char, key, err := p.read()
+
+		p.mu.Lock()
+		if err != nil && p.waiting == 0 {
+			// Nobody waits for the read any more, so its failure, such as the keyboard closing meanwhile, isn't reported
+			p.pending = false
+			p.mu.Unlock()
+			continue
+		}
+		p.mu.Unlock()
+
+		p.keys <- keyRead{char: char, key: key, err: err}
+	}
+}
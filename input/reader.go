@@ -2,6 +2,8 @@ package input
 
 import (
 	"bufio"
+	"sync"
+	"sync/atomic"
 
 	"github.com/eiannone/keyboard"
 )
@@ -12,29 +14,80 @@ type InputReader interface {
 }
 
 // KeyboardReader reads from physical keyboard (production)
+// Every KeyboardReader shares one key pump, since they all read the same keyboard
 type KeyboardReader struct{}
 
+var keyboardPump = newKeyPump(readKeyboard)
+
 func (k *KeyboardReader) GetKey() (rune, keyboard.Key, error) {
-	return keyboard.GetKey()
+	read, _ := keyboardPump.next(nil)
+	return read.char, read.key, read.err
+}
+
+func (k *KeyboardReader) keyPump() *keyPump {
+	return keyboardPump
+}
+
+func readKeyboard() (rune, keyboard.Key, error) {
+	if keyboardShutDown.Load() {
+		select {}
+	}
+	char, key, err := keyboard.GetKey()
+	if err != nil && keyboardShutDown.Load() {
+		select {}
+	}
+	return char, key, err
+}
+
+// keyboardShutDown is set once the program shuts down, so reads never return again
+var keyboardShutDown atomic.Bool
+
+// ShutDownKeyboard restores the terminal for a shutdown the open view doesn't know about
+// Pending and later keyboard reads wait forever instead of failing, so views stop where they are
+func ShutDownKeyboard() {
+	keyboardShutDown.Store(true)
+	keyboard.Close()
 }
 
 // StdinReader reads from buffered stdin (testing)
+// StdinReaders of the same buffered reader share one key pump
 type StdinReader struct {
-	reader *bufio.Reader
+	pump *keyPump
 }
 
+// stdinPumps holds the key pump of each buffered reader
+var (
+	stdinPumpsMutex sync.Mutex
+	stdinPumps      = make(map[*bufio.Reader]*keyPump)
+)
+
 func NewStdinReader(reader *bufio.Reader) *StdinReader {
-	return &StdinReader{reader: reader}
+	stdinPumpsMutex.Lock()
+	defer stdinPumpsMutex.Unlock()
+
+	pump, ok := stdinPumps[reader]
+	if !ok {
+		pump = newKeyPump(func() (rune, keyboard.Key, error) {
+			r, _, err := reader.ReadRune()
+			if err != nil {
+				return 0, 0, err
+			}
+
+			char, key := mapRuneToKeyboard(r, reader)
+			return char, key, nil
+		})
+		stdinPumps[reader] = pump
+	}
+	return &StdinReader{pump: pump}
 }
 
 func (s *StdinReader) GetKey() (rune, keyboard.Key, error) {
-	r, _, err := s.reader.ReadRune()
-	if err != nil {
-		return 0, 0, err
-	}
+	read, _ := s.pump.next(nil)
+	return read.char, read.key, read.err
+}
 
-	char, key := mapRuneToKeyboard(r, s.reader)
-	return char, key, nil
+func (s *StdinReader) keyPump() *keyPump {
+	return s.pump
 }
 
 // mapRuneToKeyboard converts stdin runes to keyboard key constants
@@ -42,6 +95,8 @@ func mapRuneToKeyboard(r rune, reader *bufio.Reader) (char rune, key keyboard.Ke
 	switch r {
 	case '\n':
 		key = keyboard.KeyEnter
+	case '\x03': // Ctrl+C
+		key = keyboard.KeyCtrlC
 	case '\x13': // Ctrl+S
 		key = keyboard.KeyCtrlS
 	case '\x0E': // Ctrl+N
@@ -0,0 +1,43 @@
+package input
+
+import "github.com/eiannone/keyboard"
+
+// KeyNotesChanged is returned by a WatchedReader instead of a key when notes changed on disk,
+// so the view can refresh and render again before waiting for the next key
+const KeyNotesChanged keyboard.Key = 0xF000
+
+// WatchedReader wraps a reader so a view waiting for a key also wakes up when notes change
+// A key read while the view was refreshing is kept and returned by the next read of the same
+// keyboard or stdin, whether from this reader, another view's or the command line's
+type WatchedReader struct {
+	reader  InputReader
+	changed <-chan struct{}
+	pump    *keyPump
+}
+
+// NewWatchedReader returns a reader that also returns KeyNotesChanged whenever changed receives
+// A nil changed channel never wakes it up
+func NewWatchedReader(reader InputReader, changed <-chan struct{}) *WatchedReader {
+	return &WatchedReader{reader: reader, changed: changed, pump: pumpOf(reader)}
+}
+
+func (w *WatchedReader) GetKey() (rune, keyboard.Key, error) {
+	if w.changed == nil {
+		return w.reader.GetKey()
+	}
+
+	read, ok := w.pump.next(w.changed)
+	if !ok {
+		return 0, KeyNotesChanged, nil
+	}
+	return read.char, read.key, read.err
+}
+
+// pumpOf returns the key pump shared by the readers of the keyboard or of stdin,
+// and a pump of its own for any other reader
+func pumpOf(reader InputReader) *keyPump {
+	if pumped, ok := reader.(interface{ keyPump() *keyPump }); ok {
+		return pumped.keyPump()
+	}
+	return newKeyPump(reader.GetKey)
+}
@@ -0,0 +1,72 @@
+package input
+
+import (
+	"bufio"
+	"io"
+	"testing"
+
+	"github.com/eiannone/keyboard"
+)
+
+// blockingReader returns the keys sent to it, waiting like a keyboard until one is pressed
+type blockingReader struct {
+	keys chan rune
+}
+
+func (b *blockingReader) GetKey() (rune, keyboard.Key, error) {
+	return <-b.keys, 0, nil
+}
+
+func TestWatchedReader_WakesUpOnChangesAndKeepsPendingKey(t *testing.T) {
+	reader := &blockingReader{keys: make(chan rune)}
+	changed := make(chan struct{}, 1)
+	watched := NewWatchedReader(reader, changed)
+
+	changed <- struct{}{}
+	if _, key, err := watched.GetKey(); err != nil || key != KeyNotesChanged {
+		t.Fatalf("Expected KeyNotesChanged, got %v, %v", key, err)
+	}
+
+	// The key read started before the change must not be lost
+	go func() { reader.keys <- 'j' }()
+	if char, _, err := watched.GetKey(); err != nil || char != 'j' {
+		t.Fatalf("Expected j, got %q, %v", char, err)
+	}
+
+	go func() { reader.keys <- 'k' }()
+	if char, _, err := watched.GetKey(); err != nil || char != 'k' {
+		t.Fatalf("Expected k, got %q, %v", char, err)
+	}
+}
+
+func TestWatchedReader_WithoutChangesReadsDirectly(t *testing.T) {
+	reader := &blockingReader{keys: make(chan rune, 1)}
+	watched := NewWatchedReader(reader, nil)
+
+	reader.keys <- 'q'
+	if char, _, err := watched.GetKey(); err != nil || char != 'q' {
+		t.Fatalf("Expected q, got %q, %v", char, err)
+	}
+}
+
+func TestWatchedReader_PendingKeyGoesToTheNextReaderOfStdin(t *testing.T) {
+	pipeReader, pipeWriter := io.Pipe()
+	stdin := bufio.NewReader(pipeReader)
+	changed := make(chan struct{}, 1)
+
+	// A view gives up on its read when notes change and exits
+	view := NewWatchedReader(NewStdinReader(stdin), changed)
+	changed <- struct{}{}
+	if _, key, err := view.GetKey(); err != nil || key != KeyNotesChanged {
+		t.Fatalf("Expected KeyNotesChanged, got %v, %v", key, err)
+	}
+
+	// The command line reads next and must get every key, in order
+	commandLine := NewStdinReader(stdin)
+	go pipeWriter.Write([]byte("ab"))
+	for _, expected := range []rune{'a', 'b'} {
+		if char, _, err := commandLine.GetKey(); err != nil || char != expected {
+			t.Fatalf("Expected %q, got %q, %v", expected, char, err)
+		}
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"bufio"
 	"cli-notes/input"
 	"cli-notes/scripts"
+	"cli-notes/scripts/config"
 	"cli-notes/scripts/data"
 	"cli-notes/scripts/presentation"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -46,21 +49,145 @@ func main() {
 	closeChannel := make(chan bool)
 	var searchedFilesStore = data.NewSearchedFilesStore()
 
-	go scripts.StartBackupSync("./notes")
+	if logFile := setupLogging(); logFile != nil {
+		defer logFile.Close()
+	}
+
+	signals := notifyShutdownSignals()
+	migrateNoteIDs()
+	startNotesWatcher()
+
+	backupSync := scripts.NewBackupSync("./notes", backupMirrorDirs(), backupPolicy())
+	go backupSync.Start()
 	go scripts.StartGitVersioning("./notes")
+	go scripts.StartRemoteSync("./notes", remoteSyncOptions(), config.GIT_SYNC_INTERVAL_SECONDS*time.Second)
 
 	go setupCommandScanner(searchedFilesStore, func() {
 		closeChannel <- true
 	})
 
-	<-closeChannel
+	select {
+	case <-closeChannel:
+	case sig := <-signals:
+		shutDownOnSignal(sig)
+	}
 
-	scripts.RunFinalSync("./notes")
-	scripts.RunFinalGitCommit("./notes")
+	runFinalSyncs(backupSync, config.SHUTDOWN_TIMEOUT_SECONDS*time.Second, signals)
 
+	if status := presentation.LogStatusLine(); status != "" {
+		fmt.Println(status)
+	}
 	fmt.Println("Exiting...")
 }
 
+// setupLogging sends the logs to the vault's state directory, so background work never prints over the views
+func setupLogging() *scripts.RotatingFile {
+	level, err := scripts.ParseLogLevel(config.LOG_LEVEL)
+	if err != nil {
+		fmt.Printf("%v, logging at info\n", err)
+	}
+
+	stateDir, err := data.StateDirectory()
+	if err != nil {
+		fmt.Printf("Not logging: %v\n", err)
+		return nil
+	}
+	return scripts.SetupLogging(stateDir, level)
+}
+
+// notifiedLogProblems is how many background problems the command line has already mentioned
+var notifiedLogProblems int
+
+// printNewLogProblems mentions the background problems logged since the last command, once
+func printNewLogProblems() {
+	problems, _ := scripts.UnseenLogProblems()
+	if problems > 0 && problems != notifiedLogProblems {
+		fmt.Println(presentation.LogStatusLine())
+	}
+	notifiedLogProblems = problems
+}
+
+// notesWatcher publishes the notes changed on disk while the program runs, nil when they aren't watched
+var notesWatcher *scripts.NoteWatcher
+
+// startNotesWatcher watches the notes for changes made outside the open view, so views refresh
+// and the search index reads changed notes again
+func startNotesWatcher() {
+	if os.Getenv("CLI_NOTES_TEST_MODE") == "true" {
+		return
+	}
+
+	watcher, err := scripts.WatchNotes("./notes")
+	if err != nil {
+		slog.Info("not watching notes for changes", "err", err)
+		return
+	}
+	notesWatcher = watcher
+
+	subscription := watcher.Subscribe()
+	go func() {
+		for range subscription.Changed() {
+			invalidateSearchIndex(subscription.Take())
+		}
+	}()
+}
+
+// invalidateSearchIndex makes the search index read the changed notes again, or every note when events were lost
+func invalidateSearchIndex(events []scripts.NoteEvent) {
+	if scripts.NotesRescanned(events) {
+		data.ResetSearchIndex()
+		return
+	}
+	data.InvalidateSearchIndex(scripts.NoteEventNames(events))
+}
+
+// subscribeToNoteChanges starts collecting note changes for a view, nil when notes aren't watched
+func subscribeToNoteChanges() *scripts.NoteSubscription {
+	if notesWatcher == nil {
+		return nil
+	}
+	return notesWatcher.Subscribe()
+}
+
+// backupPolicy returns the backup snapshot schedule and retention defined in the config
+func backupPolicy() scripts.BackupPolicy {
+	return scripts.BackupPolicy{
+		SnapshotInterval: config.BACKUP_SNAPSHOT_INTERVAL_MINUTES * time.Minute,
+		KeepHourly:       config.BACKUP_KEEP_HOURLY,
+		KeepDaily:        config.BACKUP_KEEP_DAILY,
+		KeepWeekly:       config.BACKUP_KEEP_WEEKLY,
+	}
+}
+
+// backupMirrorDirs returns the directories the notes are mirrored to, including those added in the config
+func backupMirrorDirs() []string {
+	dirs, err := scripts.BackupMirrorDirs(config.BACKUP_EXTRA_MIRRORS)
+	if err != nil {
+		slog.Error("finding backup mirrors failed", "err", err)
+	}
+	return dirs
+}
+
+// remoteSyncOptions returns the remote sync settings defined in the config
+func remoteSyncOptions() scripts.RemoteSyncOptions {
+	return scripts.RemoteSyncOptions{
+		Remote: config.GIT_SYNC_REMOTE,
+		Rebase: config.GIT_SYNC_REBASE,
+	}
+}
+
+// migrateNoteIDs gives every note without a stable ID one, so ID-backed links can point at it
+func migrateNoteIDs() {
+	migrated, err := data.MigrateNoteIDs()
+	if err != nil {
+		fmt.Printf("Error adding note IDs: %v\n", err)
+		return
+	}
+	if migrated > 0 {
+		fmt.Printf("Added IDs to %d notes\n", migrated)
+	}
+}
+
 func setupCommandScanner(fileStore *data.SearchedFilesStore, onClose func()) {
 	if os.Getenv("CLI_NOTES_TEST_MODE") == "true" {
 		runTestMode(fileStore, onClose)
@@ -73,15 +200,30 @@ func setupCommandScanner(fileStore *data.SearchedFilesStore, onClose func()) {
 	}
 	defer closeKeyboard()
 
+	keys := &input.KeyboardReader{}
+	offerDraftRecovery(keys)
+	if interruptRequested.Load() {
+		onClose()
+		return
+	}
+
 	command := presentation.WIPCommand{}
+	completer := newCompleter()
 
 	fmt.Print("> ")
 	for {
-		char, key, err := keyboard.GetKey()
+		char, key, err := keys.GetKey()
 		if err != nil {
 			panic(err)
 		}
 
+		// Raw mode reads Ctrl+C as a key, so exit the way the exit command does
+		if key == keyboard.KeyCtrlC {
+			fmt.Println("")
+			onClose()
+			return
+		}
+
 		nextCommand, err := presentation.CommandHandler(
 			char,
 			key,
@@ -93,7 +235,7 @@ func setupCommandScanner(fileStore *data.SearchedFilesStore, onClose func()) {
 				return scripts.GetUncompletedTasksInFiles(files)
 			},
 			func() { fmt.Print("\b \b") },
-			data.QueryNonFinishedObjectives,
+			completer,
 		)
 
 		if err != nil {
@@ -147,6 +289,11 @@ func setupCommandScanner(fileStore *data.SearchedFilesStore, onClose func()) {
 			}
 
 			handleCommand(completedCommand, onClose, fileStore, nil)
+			printNewLogProblems()
+			if interruptRequested.Load() {
+				onClose()
+				return
+			}
 			fmt.Print("> ")
 			command = presentation.WIPCommand{}
 
@@ -161,8 +308,41 @@ func setupCommandScanner(fileStore *data.SearchedFilesStore, onClose func()) {
 
 }
 
+// commandAliases returns the aliases and macros defined in the config
+func commandAliases() presentation.CommandAliases {
+	return presentation.CommandAliases{
+		Aliases: config.COMMAND_ALIASES,
+		Macros:  config.COMMAND_MACROS,
+	}
+}
+
+// newCompleter registers the Tab completion providers for the command line
+func newCompleter() *presentation.Completer {
+	commandNames := append(presentation.CommandNames(), commandAliases().Names()...)
+
+	return presentation.NewCompleter().
+		Register(presentation.CompleteCommand, presentation.StaticProvider(commandNames)).
+		Register(presentation.CompleteTag, data.QueryAllTags).
+		Register(presentation.CompletePerson, data.QueryTalkToPeople).
+		Register(presentation.CompleteNoteTitle, presentation.TitlesProvider(func() ([]scripts.File, error) {
+			return data.QueryFiles("")
+		})).
+		Register(presentation.CompleteObjective, presentation.TitlesProvider(data.QueryNonFinishedObjectives))
+}
+
 func handleCommand(command presentation.CompletedCommand, onClose func(), fileStore *data.SearchedFilesStore, testModeReader *bufio.Reader) {
+	commands, err := commandAliases().ResolveCommand(command)
+	if err != nil {
+		fmt.Printf("Error resolving command: %v\n", err)
+		return
+	}
 
+	for _, resolved := range commands {
+		runCommand(resolved, onClose, fileStore, testModeReader)
+	}
+}
+
+func runCommand(command presentation.CompletedCommand, onClose func(), fileStore *data.SearchedFilesStore, testModeReader *bufio.Reader) {
 	switch command.Name {
 	case "gt":
 		if len(command.Queries) == 0 {
@@ -220,7 +400,7 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 			fmt.Println("Please provide a query to search")
 			return
 		}
-		files, err := scripts.QueryAllFiles(command.Queries, data.QueryFiles)
+		files, err := data.SearchNotes(command.Queries)
 		if err != nil {
 			fmt.Printf("Error querying notes: %v", err)
 		}
@@ -264,7 +444,20 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 		handleCreateFile("plan", command.Queries, scripts.CreateSevenQuestions)
 
 	case "o":
-		if command.SelectedFile.Name != "" {
+		if len(command.Queries) > 0 && command.Queries[0] != "" {
+			linkText := strings.TrimSuffix(strings.TrimPrefix(command.Queries[0], "[["), "]]")
+			targets, err := data.LoadLinkTargets()
+			if err != nil {
+				fmt.Printf("Error finding note: %v\n", err)
+				return
+			}
+			file := targets.ResolveFile(strings.TrimSuffix(linkText, ".md"))
+			if file == nil {
+				fmt.Printf("Note not found: %s\n", command.Queries[0])
+				return
+			}
+			openLinkInEditor(file.Name, linkText)
+		} else if command.SelectedFile.Name != "" {
 			openNoteInEditor(command.SelectedFile.Name)
 		} else {
 			fmt.Println("Please provide a file name to open")
@@ -274,6 +467,9 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 		onClose()
 		return
 
+	case "help":
+		fmt.Print(presentation.RenderHelp(commandAliases()))
+
 	case "cs":
 		file, err := scripts.CreateStandup(data.GetTeamNames, data.WriteFile)
 		if err != nil {
@@ -474,7 +670,16 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 		}
 
 		newTitle := strings.Join(command.Queries, "-")
-		renamedFile, err := scripts.RenameFile(newTitle, command.SelectedFile, data.WriteFile)
+
+		// Find the notes linking to this one so their links can be rewritten
+		linkIndex, err := data.BuildLinkIndex()
+		if err != nil {
+			fmt.Printf("Error building link index: %v\n", err)
+			return
+		}
+		referencing := linkIndex.ReferencingFiles(command.SelectedFile.Name)
+
+		renamedFile, updatedCount, err := scripts.RenameFileWithLinks(newTitle, command.SelectedFile, referencing, data.WriteFilesAtomically)
 		if err != nil {
 			fmt.Printf("Error renaming file: %v\n", err)
 			return
@@ -491,6 +696,7 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 		fileStore.SetFilesSearched(previousFiles)
 
 		fmt.Printf("Renamed %v to %v\n", command.SelectedFile.Name, renamedFile.Name)
+		fmt.Printf("Updated links in %d notes\n", updatedCount)
 
 	case "gd":
 		if len(command.Queries) != 2 {
@@ -522,6 +728,13 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 			return
 		}
 
+		// Embedded notes are copied into the summary so it reads on its own
+		files, err = data.ExpandEmbedsInFiles(files)
+		if err != nil {
+			fmt.Printf("Error expanding embedded notes: %v\n", err)
+			return
+		}
+
 		// Create a combined note
 		newFile, err := scripts.CreateDateRangeQueryNote(startDate, endDate, files, data.WriteFile)
 		if err != nil {
@@ -532,6 +745,128 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 		fmt.Printf("Created date range query note: %s\n", newFile.Name)
 		openNoteInEditor(newFile.Name)
 
+	case "tags":
+		files, err := data.QueryFiles("")
+		if err != nil {
+			fmt.Printf("Error loading notes: %v\n", err)
+			return
+		}
+
+		tree := scripts.BuildTagTree(files)
+		if len(tree) == 0 {
+			fmt.Println("No tags found")
+			return
+		}
+		presentation.PrintTagTree(tree)
+
+	case "tag-rename", "tag-merge", "tag-rm":
+		var reader input.InputReader
+		if testModeReader != nil {
+			reader = input.NewStdinReader(testModeReader)
+		} else {
+			reader = &input.KeyboardReader{}
+		}
+		handleTagCommand(command, reader)
+
+	case "um":
+		if command.SelectedFile.Name == "" {
+			fmt.Println("No file selected")
+			return
+		}
+		var reader input.InputReader
+		if testModeReader != nil {
+			reader = input.NewStdinReader(testModeReader)
+		} else {
+			reader = &input.KeyboardReader{}
+		}
+		handleUnlinkedMentionsCommand(command, reader)
+
+	case "rel":
+		if command.SelectedFile.Name == "" {
+			fmt.Println("No file selected")
+			return
+		}
+		var reader input.InputReader
+		if testModeReader != nil {
+			reader = input.NewStdinReader(testModeReader)
+		} else {
+			reader = &input.KeyboardReader{}
+		}
+		handleRelatedCommand(command, reader)
+
+	case "dupes":
+		var reader input.InputReader
+		if testModeReader != nil {
+			reader = input.NewStdinReader(testModeReader)
+		} else {
+			reader = &input.KeyboardReader{}
+		}
+		handleDupesCommand(command, reader)
+
+	case "doctor":
+		var reader input.InputReader
+		if testModeReader != nil {
+			reader = input.NewStdinReader(testModeReader)
+		} else {
+			reader = &input.KeyboardReader{}
+		}
+		handleDoctorCommand(command, reader)
+
+	case "export-graph":
+		handleExportGraphCommand(command)
+
+	case "conflicts":
+		files, err := data.QueryConflictedNotes()
+		if err != nil {
+			fmt.Printf("Error finding conflicts: %v\n", err)
+			return
+		}
+		if len(files) == 0 {
+			fmt.Println("No notes with sync conflicts")
+			return
+		}
+		fmt.Printf("Notes with sync conflicts (%d), keep the right lines between the markers:\n", len(files))
+		onFilesFetched(files, fileStore)
+
+	case "asof":
+		handleAsOfCommand(command, fileStore)
+
+	case "churn":
+		handleChurnCommand(command)
+
+	case "backups":
+		handleBackupsCommand()
+
+	case "log":
+		handleLogCommand(command)
+
+	case "restore":
+		var reader input.InputReader
+		if testModeReader != nil {
+			reader = input.NewStdinReader(testModeReader)
+		} else {
+			reader = &input.KeyboardReader{}
+		}
+		handleRestoreCommand(command, reader)
+
+	case "verify-backup":
+		var reader input.InputReader
+		if testModeReader != nil {
+			reader = input.NewStdinReader(testModeReader)
+		} else {
+			reader = &input.KeyboardReader{}
+		}
+		handleVerifyBackupCommand(command, reader)
+
+	case "hist":
+		var reader input.InputReader
+		if testModeReader != nil {
+			reader = input.NewStdinReader(testModeReader)
+		} else {
+			reader = &input.KeyboardReader{}
+		}
+		handleHistoryCommand(command, reader)
+
 	case "wp", "week":
 		var reader input.InputReader
 		if testModeReader != nil {
@@ -539,7 +874,7 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 		} else {
 			reader = &input.KeyboardReader{}
 		}
-		err := runWeekPlanner(reader)
+		err := runWeekPlanner(reader, nil)
 		if err != nil {
 			fmt.Printf("Error running week planner: %v\n", err)
 			return
@@ -578,7 +913,7 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 						command.SelectedFile.Title, existingParent.Title)
 					fmt.Print("Re-link to \"" + targetObjective.Title + "\"? (y/n): ")
 
-					char, _, err := keyboard.GetKey()
+					char, _, err := (&input.KeyboardReader{}).GetKey()
 					if err != nil || (char != 'y' && char != 'Y') {
 						fmt.Println("\nCancelled.")
 						return
@@ -680,16 +1015,31 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 			return
 		}
 
-		links, err := data.GetLinksFrom(command.SelectedFile.Name)
+		resolutions, err := data.GetLinkResolutions(command.SelectedFile.Name)
 		if err != nil {
 			fmt.Printf("Error getting links: %v\n", err)
 			return
 		}
 
-		// Check for unresolved links
-		unresolved, err := data.GetUnresolvedLinks(command.SelectedFile.Name)
-		if err != nil {
-			fmt.Printf("Error checking links: %v\n", err)
+		// Every candidate of an ambiguous link is listed, so any of them can be opened
+		links := make([]scripts.File, 0, len(resolutions))
+		seen := make(map[string]bool)
+		ambiguous := make([]data.LinkResolution, 0)
+		unresolved := make([]string, 0)
+		for _, resolution := range resolutions {
+			if len(resolution.Candidates) == 0 {
+				unresolved = append(unresolved, resolution.LinkText)
+				continue
+			}
+			if resolution.IsAmbiguous() {
+				ambiguous = append(ambiguous, resolution)
+			}
+			for _, candidate := range resolution.Candidates {
+				if !seen[candidate.Name] {
+					seen[candidate.Name] = true
+					links = append(links, candidate)
+				}
+			}
 		}
 
 		if len(links) == 0 && len(unresolved) == 0 {
@@ -702,6 +1052,12 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 			onFilesFetched(links, fileStore)
 		}
 
+		if len(ambiguous) > 0 {
+			fmt.Printf("\nAmbiguous links (%d):\n", len(ambiguous))
+			presentation.PrintAmbiguousLinks(ambiguous)
+			fmt.Println("Run dupes to rename the notes or qualify the links")
+		}
+
 		// Show unresolved links and offer to create them
 		if len(unresolved) > 0 {
 			fmt.Printf("\nUnresolved links (%d):\n", len(unresolved))
@@ -711,7 +1067,7 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 
 			// Prompt to create first unresolved link
 			fmt.Printf("\nCreate note for [[%s]]? (y/n): ", unresolved[0])
-			char, _, _ := keyboard.GetKey()
+			char, _, _ := (&input.KeyboardReader{}).GetKey()
 			fmt.Println()
 
 			if char == 'y' || char == 'Y' {
@@ -773,7 +1129,7 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 		}
 
 		// Insert link to the selected note
-		err = data.InsertLinkAtTop(command.SelectedFile.Name, selectedNote.Title)
+		err = data.InsertIDLinkAtTop(command.SelectedFile.Name, *selectedNote)
 		if err != nil {
 			fmt.Printf("Error inserting link: %v\n", err)
 			return
@@ -819,7 +1175,7 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 				fmt.Println("  • Become independent parent objective")
 				fmt.Print("Continue? (y/n): ")
 
-				char, _, err := keyboard.GetKey()
+				char, _, err := (&input.KeyboardReader{}).GetKey()
 				if err != nil || (char != 'y' && char != 'Y') {
 					fmt.Println("\nCancelled.")
 					return
@@ -850,78 +1206,1003 @@ func handleCommand(command presentation.CompletedCommand, onClose func(), fileSt
 			initialQuery = strings.Join(command.Queries, ", ")
 		}
 
-		err := runSearchView(initialQuery, reader, fileStore)
-		if err != nil {
-			fmt.Printf("Error running search: %v\n", err)
+		err := runSearchView(initialQuery, reader, fileStore)
+		if err != nil {
+			fmt.Printf("Error running search: %v\n", err)
+			return
+		}
+
+	default:
+		fmt.Println("Unknown command.")
+		return
+	}
+}
+
+func onFilesFetched(files []scripts.File, fileStore *data.SearchedFilesStore) {
+	fileStore.SetFilesSearched(files)
+	presentation.PrintAllFiles(files)
+}
+
+func searchRecentFilesPrintIfNotFound(search func() *scripts.File) scripts.File {
+	file := search()
+	if file == nil {
+		fmt.Println("No files have been searched yet.")
+		fmt.Println("")
+		return scripts.File{}
+	}
+
+	return *file
+}
+
+func openNoteInEditor(fileName string) {
+	filePath := "notes/" + fileName
+	err := presentation.OpenNoteInEditor(filePath, closeKeyboard, func() {
+		if err := reopenKeyboard(); err != nil {
+			fmt.Printf("Error reopening keyboard: %v\n", err)
+		}
+	})
+	if err != nil {
+		fmt.Printf("Error opening note in editor: %v", err)
+	}
+}
+
+// openLinkInEditor opens the note a link points at, on the heading or block it targets
+func openLinkInEditor(fileName string, linkText string) {
+	line, err := data.FindLinkLine(fileName, linkText)
+	if err != nil {
+		fmt.Printf("Error finding link target: %v\n", err)
+	}
+	if anchor, _ := scripts.ParseLinkAnchor(linkText); !anchor.IsZero() && line == 0 {
+		fmt.Printf("Section not found, opening the top of %s\n", fileName)
+	}
+
+	filePath := "notes/" + fileName
+	err = presentation.OpenNoteInEditorAtLine(filePath, line, closeKeyboard, func() {
+		if err := reopenKeyboard(); err != nil {
+			fmt.Printf("Error reopening keyboard: %v\n", err)
+		}
+	})
+	if err != nil {
+		fmt.Printf("Error opening note in editor: %v\n", err)
+	}
+}
+
+func handleCreateFile(fileType string, queries []string, createFn func(string, scripts.OnFileCreated) (scripts.File, error)) {
+	if len(queries) < 1 {
+		fmt.Printf("Please provide a title for the new %s\n", fileType)
+		return
+	}
+	title := strings.Join(queries, "-")
+	file, err := createFn(title, data.WriteFile)
+	if err != nil {
+		fmt.Printf("Error writing file: %v\n", err)
+		return
+	}
+	openNoteInEditor(file.Name)
+}
+
+func handleCreateTodo(queries []string) {
+	if len(queries) < 1 || queries[0] == "" {
+		fmt.Println("Please provide a title for the new todo")
+		return
+	}
+
+	title := queries[0]
+	var checkboxItems []string
+	if len(queries) > 1 {
+		checkboxItems = queries[1:]
+	}
+
+	file, err := scripts.CreateTodoWithCheckboxes(title, checkboxItems, data.WriteFile)
+	if err != nil {
+		fmt.Printf("Error writing file: %v\n", err)
+		return
+	}
+	openNoteInEditor(file.Name)
+}
+
+// handleTagCommand previews a bulk tag rename, merge or removal and applies it on confirmation
+// All touched notes are committed together so the operation can be reverted as one
+func handleTagCommand(command presentation.CompletedCommand, reader input.InputReader) {
+	args := make([]string, 0, len(command.Queries))
+	dryRun := false
+	for _, query := range command.Queries {
+		if query == "--dry-run" {
+			dryRun = true
+			continue
+		}
+		args = append(args, query)
+	}
+
+	files, err := data.QueryFiles("")
+	if err != nil {
+		fmt.Printf("Error loading notes: %v\n", err)
+		return
+	}
+
+	var changes []scripts.TagChange
+	var message string
+	switch command.Name {
+	case "tag-rename":
+		if len(args) != 2 {
+			fmt.Println("Usage: tag-rename <old> <new> [--dry-run]")
+			return
+		}
+		changes, err = scripts.PlanTagRename(files, args[0], args[1])
+		message = fmt.Sprintf("tags: rename %s to %s", args[0], args[1])
+	case "tag-merge":
+		if len(args) != 2 {
+			fmt.Println("Usage: tag-merge <source> <target> [--dry-run]")
+			return
+		}
+		changes, err = scripts.PlanTagMerge(files, args[0], args[1])
+		message = fmt.Sprintf("tags: merge %s into %s", args[0], args[1])
+	case "tag-rm":
+		if len(args) != 1 {
+			fmt.Println("Usage: tag-rm <tag> [--dry-run]")
+			return
+		}
+		changes, err = scripts.PlanTagRemoval(files, args[0])
+		message = fmt.Sprintf("tags: remove %s", args[0])
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	fmt.Printf("%d notes will change:\n", len(changes))
+	presentation.PrintTagChanges(changes)
+
+	if dryRun {
+		fmt.Println("Dry run, no notes were changed")
+		return
+	}
+
+	fmt.Print("Apply changes? (y/n): ")
+	for {
+		char, _, err := reader.GetKey()
+		if err != nil {
+			fmt.Printf("Error reading input: %v\n", err)
+			return
+		}
+
+		if char == 'n' || char == 'N' {
+			fmt.Println("n")
+			fmt.Println("Cancelled.")
+			return
+		}
+		if char == 'y' || char == 'Y' {
+			fmt.Println("y")
+			break
+		}
+	}
+
+	err = scripts.ApplyTagChanges(changes, data.WriteFile)
+	if err != nil {
+		fmt.Printf("Error updating tags: %v\n", err)
+		return
+	}
+
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.File.Name)
+	}
+	scripts.RunOperationCommit("./notes", message, paths)
+	fmt.Printf("Updated %d notes\n", len(changes))
+}
+
+// noteNames returns the file names of notes, for committing the notes an operation wrote
+func noteNames(files []scripts.File) []string {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+// handleDupesCommand lists notes sharing a title and walks through fixing each group,
+// either by renaming the older notes with their date or by qualifying the links with IDs
+func handleDupesCommand(command presentation.CompletedCommand, reader input.InputReader) {
+	dryRun := len(command.Queries) > 0 && command.Queries[0] == "--dry-run"
+
+	files, err := data.QueryFiles("")
+	if err != nil {
+		fmt.Printf("Error loading notes: %v\n", err)
+		return
+	}
+
+	groups := scripts.FindDuplicateTitles(files)
+	if len(groups) == 0 {
+		fmt.Println("No duplicate titles")
+		return
+	}
+
+	fmt.Printf("Duplicate titles (%d):\n", len(groups))
+	for _, title := range groupTitles(groups) {
+		// Earlier fixes change notes, so plan each group from the current notes
+		group, found := findDuplicateTitleGroup(scripts.FindDuplicateTitles(files), title)
+		if !found {
+			continue
+		}
+
+		// Qualified links point at IDs, so every note in the group needs one
+		// A dry run previews without them, since adding them changes the notes
+		if !dryRun {
+			if err := ensureGroupIDs(&group); err != nil {
+				fmt.Printf("\nSkipping %s: %v\n", title, err)
+				continue
+			}
+		}
+
+		renamed := scripts.PlanDatedTitles(group, files)
+		qualifications := scripts.PlanLinkQualifications(group, files)
+
+		fmt.Println()
+		presentation.PrintDuplicateTitleGroup(group)
+		fmt.Println("  r) Rename older notes with their date:")
+		presentation.PrintDatedTitles(group, renamed)
+		if len(qualifications) > 0 {
+			fmt.Println("  q) Qualify links with note IDs:")
+			presentation.PrintLinkQualifications(qualifications)
+		}
+
+		if dryRun {
+			continue
+		}
+
+		fmt.Print("Fix? (r/q/s): ")
+		var choice rune
+		for choice == 0 {
+			char, _, err := reader.GetKey()
+			if err != nil {
+				fmt.Printf("Error reading input: %v\n", err)
+				return
+			}
+			switch {
+			case char == 'r' || char == 'R':
+				choice = 'r'
+			case (char == 'q' || char == 'Q') && len(qualifications) > 0:
+				choice = 'q'
+			case char == 's' || char == 'S':
+				choice = 's'
+			}
+		}
+		fmt.Println(string(choice))
+
+		switch choice {
+		case 'r':
+			if err := data.WriteFilesAtomically(renamed); err != nil {
+				fmt.Printf("Error renaming notes: %v\n", err)
+				return
+			}
+			paths := append(noteNames(group.Files), noteNames(renamed)...)
+			scripts.RunOperationCommit("./notes", fmt.Sprintf("links: date duplicate titles of %s", group.Title), paths)
+			fmt.Printf("Renamed %d notes\n", len(renamed))
+		case 'q':
+			updated := make([]scripts.File, 0, len(qualifications))
+			for _, qualification := range qualifications {
+				updated = append(updated, qualification.File)
+			}
+			if err := data.WriteFilesAtomically(updated); err != nil {
+				fmt.Printf("Error qualifying links: %v\n", err)
+				return
+			}
+			paths := append(noteNames(group.Files), noteNames(updated)...)
+			scripts.RunOperationCommit("./notes", fmt.Sprintf("links: qualify links to %s", group.Title), paths)
+			fmt.Printf("Qualified links in %d notes\n", len(updated))
+		}
+
+		if choice != 's' {
+			if files, err = data.QueryFiles(""); err != nil {
+				fmt.Printf("Error loading notes: %v\n", err)
+				return
+			}
+		}
+	}
+
+	if dryRun {
+		fmt.Println("\nDry run, no notes were changed")
+	}
+}
+
+// ensureGroupIDs gives every note of a duplicate title group an ID, stopping at the first that can't get one
+func ensureGroupIDs(group *scripts.DuplicateTitleGroup) error {
+	for i, file := range group.Files {
+		if file.ID != "" {
+			continue
+		}
+		id, err := data.EnsureNoteID(file.Name)
+		if err != nil {
+			return fmt.Errorf("error adding an ID to %s: %w", file.Name, err)
+		}
+		group.Files[i].ID = id
+	}
+	return nil
+}
+
+// handleUnlinkedMentionsCommand lists plain-text mentions of the selected note in other notes
+// and turns the chosen mention, or all of them, into links
+func handleUnlinkedMentionsCommand(command presentation.CompletedCommand, reader input.InputReader) {
+	target, err := data.LoadFileByName(command.SelectedFile.Name)
+	if err != nil {
+		fmt.Printf("Error loading note: %v\n", err)
+		return
+	}
+
+	// Mentions become ID-backed links, so the note needs an ID
+	if target.ID == "" {
+		if id, err := data.EnsureNoteID(target.Name); err == nil {
+			target.ID = id
+		}
+	}
+
+	for {
+		mentions, err := data.FindUnlinkedMentions(target.Name)
+		if err != nil {
+			fmt.Printf("Error finding mentions: %v\n", err)
+			return
+		}
+		if len(mentions) == 0 {
+			fmt.Printf("No unlinked mentions of %s\n", target.Title)
+			return
+		}
+
+		fmt.Printf("\nUnlinked mentions of %s (%d):\n", target.Title, len(mentions))
+		presentation.PrintMentions(mentions)
+		fmt.Print("Link which? (number, a = all, q = quit): ")
+
+		choice, err := getLineInput(reader)
+		if err != nil || choice == "q" {
+			return
+		}
+
+		if choice == "a" {
+			linkMentions(mentions, target)
+			return
+		}
+
+		number, err := strconv.Atoi(choice)
+		if err != nil || number < 1 || number > len(mentions) {
+			fmt.Printf("Invalid choice: %s\n", choice)
+			continue
+		}
+		linkMentions(mentions[number-1:number], target)
+	}
+}
+
+// relatedLimit is how many related notes rel lists unless told otherwise
+const relatedLimit = 10
+
+// handleRelatedCommand lists the notes most related to the selected note and links the chosen ones
+func handleRelatedCommand(command presentation.CompletedCommand, reader input.InputReader) {
+	limit := relatedLimit
+	if len(command.Queries) > 0 && command.Queries[0] != "" {
+		number, err := strconv.Atoi(command.Queries[0])
+		if err != nil || number < 1 {
+			fmt.Println("Usage: rel [number of notes]")
+			return
+		}
+		limit = number
+	}
+
+	source := command.SelectedFile
+	for {
+		related, err := data.FindRelatedNotes(source.Name, limit)
+		if err != nil {
+			fmt.Printf("Error finding related notes: %v\n", err)
+			return
+		}
+		if len(related) == 0 {
+			fmt.Printf("No related notes for %s\n", source.Title)
+			return
+		}
+
+		fmt.Printf("\nRelated to %s (%d):\n", source.Title, len(related))
+		presentation.PrintRelatedNotes(related)
+		fmt.Print("Link which? (number, q = quit): ")
+
+		choice, err := getLineInput(reader)
+		if err != nil || choice == "q" {
+			return
+		}
+
+		number, err := strconv.Atoi(choice)
+		if err != nil || number < 1 || number > len(related) {
+			fmt.Printf("Invalid choice: %s\n", choice)
+			continue
+		}
+
+		target := related[number-1].File
+		if err := data.InsertIDLinkAtTop(source.Name, target); err != nil {
+			fmt.Printf("Error adding link: %v\n", err)
+			return
+		}
+		fmt.Printf("Linked to \"%s\"\n", target.Title)
+	}
+}
+
+// linkMentions turns the mentions into links to the target and commits the changed notes together
+func linkMentions(mentions []scripts.Mention, target scripts.File) {
+	byFile := make(map[string][]scripts.Mention)
+	files := make([]scripts.File, 0)
+	for _, mention := range mentions {
+		if _, seen := byFile[mention.File.Name]; !seen {
+			files = append(files, mention.File)
+		}
+		byFile[mention.File.Name] = append(byFile[mention.File.Name], mention)
+	}
+
+	updated := make([]scripts.File, 0, len(files))
+	for _, file := range files {
+		updated = append(updated, scripts.LinkMentions(file, byFile[file.Name], target))
+	}
+
+	if err := data.WriteFilesAtomically(updated); err != nil {
+		fmt.Printf("Error linking mentions: %v\n", err)
+		return
+	}
+	scripts.RunOperationCommit("./notes", fmt.Sprintf("links: link mentions of %s", target.Title), noteNames(updated))
+	fmt.Printf("Linked %d mentions in %d notes\n", len(mentions), len(updated))
+}
+
+// handleDoctorCommand reports vault problems and, with --fix, applies the safe fixes on confirmation
+// All fixed notes are written and committed together
+func handleDoctorCommand(command presentation.CompletedCommand, reader input.InputReader) {
+	fix := false
+	dryRun := false
+	for _, query := range command.Queries {
+		switch query {
+		case "":
+		case "--fix":
+			fix = true
+		case "--dry-run":
+			dryRun = true
+		default:
+			fmt.Println("Usage: doctor [--fix [--dry-run]]")
+			return
+		}
+	}
+
+	notes, err := data.LoadVaultNotes()
+	if err != nil {
+		fmt.Printf("Error loading notes: %v\n", err)
+		return
+	}
+
+	issues := scripts.CheckVaultHealth(notes)
+	if len(issues) == 0 {
+		fmt.Printf("Checked %d notes, no problems found\n", len(notes))
+		return
+	}
+
+	fmt.Printf("Checked %d notes, found %d problems\n", len(notes), len(issues))
+	presentation.PrintHealthIssues(issues)
+
+	if !fix {
+		fmt.Println("Run doctor --fix to apply the fixes, or doctor --fix --dry-run to preview them")
+		return
+	}
+
+	fixes, err := scripts.PlanHealthFixes(notes, issues)
+	if err != nil {
+		fmt.Printf("Error planning fixes: %v\n", err)
+		return
+	}
+	if len(fixes) == 0 {
+		fmt.Println("Nothing can be fixed automatically")
+		return
+	}
+
+	fmt.Printf("%d notes will change:\n", len(fixes))
+	presentation.PrintHealthFixes(fixes)
+
+	if dryRun {
+		fmt.Println("Dry run, no notes were changed")
+		return
+	}
+
+	fmt.Print("Apply fixes? (y/n): ")
+	for {
+		char, _, err := reader.GetKey()
+		if err != nil {
+			fmt.Printf("Error reading input: %v\n", err)
+			return
+		}
+
+		if char == 'n' || char == 'N' {
+			fmt.Println("n")
+			fmt.Println("Cancelled.")
+			return
+		}
+		if char == 'y' || char == 'Y' {
+			fmt.Println("y")
+			break
+		}
+	}
+
+	files := make([]scripts.File, 0, len(fixes))
+	for _, fix := range fixes {
+		files = append(files, fix.File)
+	}
+	if err := data.WriteFilesAtomically(files); err != nil {
+		fmt.Printf("Error writing fixes: %v\n", err)
+		return
+	}
+
+	scripts.RunOperationCommit("./notes", fmt.Sprintf("doctor: fix %d notes", len(files)), noteNames(files))
+	fmt.Printf("Fixed %d notes\n", len(files))
+}
+
+// handleHistoryCommand opens the git history of the selected note, or of the deleted notes
+// Usage: hist [--deleted]
+func handleHistoryCommand(command presentation.CompletedCommand, reader input.InputReader) {
+	deleted := false
+	for _, query := range command.Queries {
+		switch query {
+		case "":
+		case "--deleted":
+			deleted = true
+		default:
+			fmt.Println("Usage: hist [--deleted]")
+			return
+		}
+	}
+
+	var state *data.HistoryViewState
+	var err error
+	if deleted {
+		state, err = data.NewDeletedNotesViewState()
+	} else {
+		if command.SelectedFile.Name == "" {
+			fmt.Println("No file selected")
+			return
+		}
+		state, err = data.NewHistoryViewState(command.SelectedFile.Name)
+	}
+	if err != nil {
+		fmt.Printf("Error loading history: %v\n", err)
+		return
+	}
+
+	if err := runHistoryView(state, reader); err != nil {
+		fmt.Printf("Error running history view: %v\n", err)
+	}
+}
+
+// handleAsOfCommand lists the todos as they were at a past date or commit, read from git history
+// Overdue todos are those due by that date. Usage: asof <date|commit> [overdue|open|done]
+func handleAsOfCommand(command presentation.CompletedCommand, fileStore *data.SearchedFilesStore) {
+	args := make([]string, 0, len(command.Queries))
+	for _, query := range command.Queries {
+		if query != "" {
+			args = append(args, query)
+		}
+	}
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Println("Usage: asof <date|commit> [overdue|open|done]")
+		return
+	}
+	kind := "overdue"
+	if len(args) == 2 {
+		kind = args[1]
+	}
+
+	snapshot, err := data.LoadVaultSnapshot(args[0])
+	if err != nil {
+		fmt.Printf("Error loading notes at %s: %v\n", args[0], err)
+		return
+	}
+	day := snapshot.Commit.Date
+	if date, err := time.Parse("2006-01-02", args[0]); err == nil {
+		day = date
+	}
+
+	var files []scripts.File
+	switch kind {
+	case "overdue":
+		files, err = scripts.GetOverdueTodosAt(func(dateQuery scripts.DateQuery) ([]scripts.File, error) {
+			return snapshot.QueryTodosWithDateCriteria(dateQuery)
+		}, day)
+	case "open":
+		files, err = scripts.GetTodos(snapshot.QueryFilesByDone)
+	case "done":
+		files, err = snapshot.QueryFilesByDone(true)
+		files = scripts.SortTodosByPriorityAndDueDate(files)
+	default:
+		fmt.Println("Usage: asof <date|commit> [overdue|open|done]")
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error querying notes at %s: %v\n", args[0], err)
+		return
+	}
+
+	fmt.Printf("%s todos as of %s (commit %s %s %q):\n", strings.ToUpper(kind[:1])+kind[1:], day.Format("2006-01-02"),
+		snapshot.Commit.ShortHash(), snapshot.Commit.Date.Format("2006-01-02 15:04"), snapshot.Commit.Subject)
+	if len(files) == 0 {
+		fmt.Println("None")
+		return
+	}
+	onFilesFetched(files, fileStore)
+}
+
+// handleChurnCommand reports the todos whose due date was moved most often, this quarter by default
+// Usage: churn [start-date [end-date]]
+func handleChurnCommand(command presentation.CompletedCommand) {
+	args := make([]string, 0, len(command.Queries))
+	for _, query := range command.Queries {
+		if query != "" {
+			args = append(args, query)
+		}
+	}
+	if len(args) > 2 {
+		fmt.Println("Usage: churn [start-date [end-date]]")
+		return
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month()-(now.Month()-1)%3, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	for i, arg := range args {
+		date, err := time.ParseInLocation("2006-01-02", arg, time.Local)
+		if err != nil {
+			fmt.Println("Invalid date format. Please use YYYY-MM-DD")
+			return
+		}
+		if i == 0 {
+			start = date
+		} else {
+			end = date
+		}
+	}
+
+	churn, err := data.QueryDueDateChurn(start, end.Add(24*time.Hour-time.Second))
+	if err != nil {
+		fmt.Printf("Error counting due date changes: %v\n", err)
+		return
+	}
+
+	if len(churn) == 0 {
+		fmt.Printf("No due dates moved between %s and %s\n", start.Format("2006-01-02"), end.Format("2006-01-02"))
+		return
+	}
+	fmt.Printf("Due dates moved between %s and %s, most moved first:\n", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	presentation.PrintDueDateChurn(churn)
+}
+
+// handleLogCommand prints the latest log entries and clears the background problem indicator
+// Usage: log [n], 20 entries by default
+func handleLogCommand(command presentation.CompletedCommand) {
+	limit := 20
+	for _, query := range command.Queries {
+		if query == "" {
+			continue
+		}
+		n, err := strconv.Atoi(query)
+		if err != nil || n < 1 {
+			fmt.Println("Usage: log [n]")
+			return
+		}
+		limit = n
+	}
+
+	stateDir, err := data.StateDirectory()
+	if err != nil {
+		fmt.Printf("Error finding the log: %v\n", err)
+		return
+	}
+	entries, err := scripts.ReadRecentLogEntries(stateDir, limit)
+	if err != nil {
+		fmt.Printf("Error reading the log: %v\n", err)
+		return
+	}
+
+	scripts.MarkLogProblemsSeen()
+	notifiedLogProblems = 0
+	if len(entries) == 0 {
+		fmt.Println("Nothing logged yet")
+		return
+	}
+	fmt.Printf("Last %d log entries from %s:\n", len(entries), filepath.Join(stateDir, scripts.LOG_FILE_NAME))
+	presentation.PrintLogEntries(entries)
+}
+
+// handleBackupsCommand lists the backup snapshots newest first, numbered for restore
+func handleBackupsCommand() {
+	backupDir, err := scripts.BackupSnapshotsDir()
+	if err != nil {
+		fmt.Printf("Error finding backups: %v\n", err)
+		return
+	}
+	snapshots, err := scripts.ListBackupSnapshots(backupDir)
+	if err != nil {
+		fmt.Printf("Error listing backups: %v\n", err)
+		return
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("No backups yet in %s\n", backupDir)
+		return
+	}
+
+	noteCounts := make([]int, len(snapshots))
+	for i, snapshot := range snapshots {
+		noteCounts[i], err = scripts.CountBackupNotes(snapshot)
+		if err != nil {
+			noteCounts[i] = -1
+		}
+	}
+
+	fmt.Printf("Backups in %s, newest first:\n", backupDir)
+	presentation.PrintBackupSnapshots(snapshots, noteCounts)
+	fmt.Println("Run restore <n> [note] to restore a note, or the whole vault, from a backup")
+}
+
+// handleRestoreCommand restores a note, or the whole vault, from a backup snapshot listed by backups
+// The whole vault is backed up first, so the restore can be undone. Usage: restore <n> [note]
+func handleRestoreCommand(command presentation.CompletedCommand, reader input.InputReader) {
+	args := make([]string, 0, len(command.Queries))
+	for _, query := range command.Queries {
+		if query != "" {
+			args = append(args, query)
+		}
+	}
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Println("Usage: restore <n> [note]")
+		return
+	}
+
+	backupDir, err := scripts.BackupSnapshotsDir()
+	if err != nil {
+		fmt.Printf("Error finding backups: %v\n", err)
+		return
+	}
+	snapshots, err := scripts.ListBackupSnapshots(backupDir)
+	if err != nil {
+		fmt.Printf("Error listing backups: %v\n", err)
+		return
+	}
+	number, err := strconv.Atoi(args[0])
+	if err != nil || number < 1 || number > len(snapshots) {
+		fmt.Printf("No backup %s, run backups to list them\n", args[0])
+		return
+	}
+	snapshot := snapshots[number-1]
+	taken := snapshot.Time.Format("2006-01-02 15:04:05")
+
+	notes, err := scripts.ReadBackupSnapshot(snapshot)
+	if err != nil {
+		fmt.Printf("Error reading backup: %v\n", err)
+		return
+	}
+
+	noteName := ""
+	if len(args) == 2 {
+		noteName = args[1]
+		if _, exists := notes[noteName]; !exists && !strings.HasSuffix(noteName, ".md") {
+			noteName += ".md"
+		}
+		if _, exists := notes[noteName]; !exists {
+			fmt.Printf("%s is not in the backup of %s\n", args[1], taken)
+			return
+		}
+		fmt.Printf("Restore %s from the backup of %s? (y/n): ", noteName, taken)
+	} else {
+		fmt.Printf("Replace the vault with the %d notes of the backup of %s? Notes not in it are removed, after backing up the current notes. (y/n): ", len(notes), taken)
+	}
+
+	for {
+		char, _, err := reader.GetKey()
+		if err != nil {
+			fmt.Printf("Error reading input: %v\n", err)
+			return
+		}
+
+		if char == 'n' || char == 'N' {
+			fmt.Println("n")
+			fmt.Println("Cancelled.")
+			return
+		}
+		if char == 'y' || char == 'Y' {
+			fmt.Println("y")
+			break
+		}
+	}
+
+	if noteName != "" {
+		if err := scripts.RestoreBackupNote(notes, "./notes", noteName); err != nil {
+			fmt.Printf("Error restoring %s: %v\n", noteName, err)
+			return
+		}
+		scripts.RunOperationCommit("./notes", fmt.Sprintf("restore: %s from backup %s", noteName, taken), []string{noteName})
+		fmt.Printf("Restored %s from the backup of %s\n", noteName, taken)
+		return
+	}
+
+	current, err := scripts.CreateBackupSnapshot("./notes", backupDir, time.Now())
+	if err != nil {
+		fmt.Printf("Error backing up the current notes, nothing was restored: %v\n", err)
+		return
+	}
+	restored, removed, err := scripts.RestoreBackupVault(notes, "./notes")
+	if err != nil {
+		fmt.Printf("Error restoring the vault: %v\n", err)
+		return
+	}
+	// The restore replaces every note, so the whole vault goes in its commit
+	scripts.RunOperationCommit("./notes", fmt.Sprintf("restore: vault from backup %s", taken), []string{"."})
+	fmt.Printf("Restored %d notes and removed %d from the backup of %s\n", restored, removed, taken)
+	fmt.Printf("The notes before the restore are in the backup of %s\n", current.Time.Format("2006-01-02 15:04:05"))
+}
+
+// handleVerifyBackupCommand checks every mirror of the notes against the vault and its manifest of hashes
+// With --repair, damaged notes are copied to the mirrors again. Usage: verify-backup [--repair]
+func handleVerifyBackupCommand(command presentation.CompletedCommand, reader input.InputReader) {
+	repair := false
+	for _, query := range command.Queries {
+		switch query {
+		case "":
+		case "--repair":
+			repair = true
+		default:
+			fmt.Println("Usage: verify-backup [--repair]")
+			return
+		}
+	}
+
+	mirrorDirs, err := scripts.BackupMirrorDirs(config.BACKUP_EXTRA_MIRRORS)
+	if err != nil {
+		fmt.Printf("Error finding backups: %v\n", err)
+		return
+	}
+
+	damaged := make([]scripts.BackupVerification, 0)
+	for _, dir := range mirrorDirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			fmt.Printf("No backup yet in %s\n", dir)
+			continue
+		}
+
+		verification, err := scripts.VerifyBackup("./notes", dir)
+		if err != nil {
+			fmt.Printf("Error verifying %s: %v\n", dir, err)
+			continue
+		}
+		presentation.PrintBackupVerification(verification)
+		if len(verification.Damaged()) > 0 || verification.NoManifest {
+			damaged = append(damaged, verification)
+		}
+	}
+
+	if len(damaged) == 0 {
+		return
+	}
+	if !repair {
+		fmt.Println("Run verify-backup --repair to copy the damaged notes again")
+		return
+	}
+
+	fmt.Print("Copy the damaged notes to the backups again? (y/n): ")
+	for {
+		char, _, err := reader.GetKey()
+		if err != nil {
+			fmt.Printf("Error reading input: %v\n", err)
+			return
+		}
+
+		if char == 'n' || char == 'N' {
+			fmt.Println("n")
+			fmt.Println("Cancelled.")
 			return
 		}
+		if char == 'y' || char == 'Y' {
+			fmt.Println("y")
+			break
+		}
+	}
 
-	default:
-		fmt.Println("Unknown command.")
-		return
+	for _, verification := range damaged {
+		repaired, err := scripts.RepairBackup("./notes", verification)
+		if err != nil {
+			fmt.Printf("Error repairing %s: %v\n", verification.Dir, err)
+			continue
+		}
+		fmt.Printf("Repaired %d notes in %s\n", repaired, verification.Dir)
 	}
 }
 
-func onFilesFetched(files []scripts.File, fileStore *data.SearchedFilesStore) {
-	fileStore.SetFilesSearched(files)
-	presentation.PrintAllFiles(files)
-}
+// handleExportGraphCommand writes the note graph as DOT, Mermaid or JSON
+// With a selected note the export is centred on it, otherwise it covers the whole vault
+// Usage: export-graph <dot|mermaid|json> [--depth N] [--tags] [--all] [--out FILE]
+func handleExportGraphCommand(command presentation.CompletedCommand) {
+	format := ""
+	outPath := ""
+	wholeVault := false
+	options := data.GraphExportOptions{Depth: 1}
+
+	args := command.Queries
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "":
+			continue
+		case "--tags":
+			options.Tags = true
+		case "--all":
+			wholeVault = true
+		case "--depth", "--out":
+			if i+1 >= len(args) {
+				fmt.Printf("Missing value for %s\n", args[i])
+				return
+			}
+			if args[i] == "--out" {
+				outPath = args[i+1]
+			} else {
+				depth, err := strconv.Atoi(args[i+1])
+				if err != nil || depth < 1 {
+					fmt.Println("Depth must be a number of 1 or more")
+					return
+				}
+				options.Depth = depth
+			}
+			i++
+		default:
+			if format != "" {
+				fmt.Printf("Unknown argument: %s\n", args[i])
+				return
+			}
+			format = args[i]
+		}
+	}
 
-func searchRecentFilesPrintIfNotFound(search func() *scripts.File) scripts.File {
-	file := search()
-	if file == nil {
-		fmt.Println("No files have been searched yet.")
-		fmt.Println("")
-		return scripts.File{}
+	if format == "" {
+		fmt.Println("Usage: export-graph <dot|mermaid|json> [--depth N] [--tags] [--all] [--out FILE]")
+		return
 	}
 
-	return *file
-}
+	if !wholeVault {
+		options.Center = command.SelectedFile.Name
+	}
 
-func openNoteInEditor(fileName string) {
-	filePath := "notes/" + fileName
-	err := presentation.OpenNoteInEditor(filePath, closeKeyboard, func() {
-		if err := reopenKeyboard(); err != nil {
-			fmt.Printf("Error reopening keyboard: %v\n", err)
-		}
-	})
+	graph, err := data.BuildGraphExport(options)
 	if err != nil {
-		fmt.Printf("Error opening note in editor: %v", err)
+		fmt.Printf("Error building graph: %v\n", err)
+		return
 	}
-}
 
-func handleCreateFile(fileType string, queries []string, createFn func(string, scripts.OnFileCreated) (scripts.File, error)) {
-	if len(queries) < 1 {
-		fmt.Printf("Please provide a title for the new %s\n", fileType)
+	output, err := scripts.FormatGraph(graph, format)
+	if err != nil {
+		fmt.Printf("Error exporting graph: %v\n", err)
 		return
 	}
-	title := strings.Join(queries, "-")
-	file, err := createFn(title, data.WriteFile)
-	if err != nil {
-		fmt.Printf("Error writing file: %v\n", err)
+
+	if outPath == "" {
+		fmt.Print(output)
 		return
 	}
-	openNoteInEditor(file.Name)
-}
 
-func handleCreateTodo(queries []string) {
-	if len(queries) < 1 || queries[0] == "" {
-		fmt.Println("Please provide a title for the new todo")
+	if err := os.WriteFile(outPath, []byte(output), 0644); err != nil {
+		fmt.Printf("Error writing %s: %v\n", outPath, err)
 		return
 	}
+	fmt.Printf("Exported %d notes and %d links to %s\n", len(graph.Nodes), len(graph.Edges), outPath)
+}
 
-	title := queries[0]
-	var checkboxItems []string
-	if len(queries) > 1 {
-		checkboxItems = queries[1:]
+// groupTitles returns the titles of the duplicate title groups
+func groupTitles(groups []scripts.DuplicateTitleGroup) []string {
+	titles := make([]string, 0, len(groups))
+	for _, group := range groups {
+		titles = append(titles, group.Title)
 	}
+	return titles
+}
 
-	file, err := scripts.CreateTodoWithCheckboxes(title, checkboxItems, data.WriteFile)
-	if err != nil {
-		fmt.Printf("Error writing file: %v\n", err)
-		return
+// findDuplicateTitleGroup returns the group with the given title
+func findDuplicateTitleGroup(groups []scripts.DuplicateTitleGroup, title string) (scripts.DuplicateTitleGroup, bool) {
+	for _, group := range groups {
+		if strings.EqualFold(group.Title, title) {
+			return group, true
+		}
 	}
-	openNoteInEditor(file.Name)
+	return scripts.DuplicateTitleGroup{}, false
 }
 
 func isValidDate(date string) bool {
@@ -929,7 +2210,8 @@ func isValidDate(date string) bool {
 	return err == nil
 }
 
-func runWeekPlanner(reader input.InputReader) error {
+// runWeekPlanner opens the week planner, with the moves of a draft from an earlier session when one is given
+func runWeekPlanner(reader input.InputReader, draft *data.WeekPlanDraft) error {
 	// Ensure terminal is cleaned up on all exit paths
 	defer func() {
 		// Clear screen and reset cursor
@@ -959,12 +2241,36 @@ func runWeekPlanner(reader input.InputReader) error {
 	}
 
 	lastMessage := ""
+	if draft != nil {
+		stale, err := state.ResumeDraft(draft)
+		if err != nil {
+			return fmt.Errorf("error resuming week plan draft: %w", err)
+		}
+		lastMessage = fmt.Sprintf("Resumed %d unsaved moves, Ctrl+S to save them", len(state.Plan.Changes))
+		if len(stale) > 0 {
+			lastMessage += fmt.Sprintf("; skipped %d whose notes changed since: %s", len(stale), strings.Join(stale, ", "))
+		}
+	}
+
+	subscription := subscribeToNoteChanges()
+	defer subscription.Close()
+	watched := input.NewWatchedReader(reader, subscription.Changed())
+
+	// Journal the unsaved moves as they happen, so a crash or a closed terminal doesn't lose them
+	journal := data.NewDraftJournal(data.WeekPlanDraftName)
 
 	// Main week planner event loop
 	for {
+		if draft := state.Plan.Draft(); draft != nil {
+			recordDraft(journal, draft)
+		} else {
+			discardDraft(journal)
+		}
+
 		// Render the UI
 		display := presentation.RenderWeekView(state, termWidth, termHeight)
 		fmt.Print(display)
+		fmt.Print(presentation.RenderLogStatus(termWidth))
 
 		// Display last message if any
 		if lastMessage != "" {
@@ -973,11 +2279,34 @@ func runWeekPlanner(reader input.InputReader) error {
 		}
 
 		// Get keyboard input
-		char, key, err := reader.GetKey()
+		char, key, err := watched.GetKey()
 		if err != nil {
 			return fmt.Errorf("error reading keyboard input: %w", err)
 		}
 
+		// Pick up notes changed by an external editor or another process, keeping unsaved moves
+		if key == input.KeyNotesChanged {
+			events := subscription.Take()
+			if scripts.NotesRescanned(events) {
+				err = state.RefreshAllTodos()
+			} else {
+				err = state.RefreshChangedTodos(scripts.NoteEventNames(events))
+			}
+			if err != nil {
+				lastMessage = fmt.Sprintf("Error refreshing notes: %v", err)
+			}
+			continue
+		}
+
+		// Ctrl+C leaves the program, offering to save the plan like q does
+		if interrupted(key) {
+			if state.Plan.HasChanges() && promptSaveChanges(state, reader) {
+				interruptRequested.Store(false)
+				continue
+			}
+			break
+		}
+
 		// Check for capital letter switch-day commands
 		if presentation.IsSwitchDayKey(char) {
 			targetDay, ok := presentation.ParseSwitchToDay(char)
@@ -1025,6 +2354,15 @@ func runWeekPlanner(reader input.InputReader) error {
 			continue
 		}
 
+		// Handle save, committing the saved notes right away
+		if input.Action == presentation.Save {
+			if err := saveWeekPlan(state); err != nil {
+				return err
+			}
+			lastMessage = "Changes saved successfully"
+			continue
+		}
+
 		// Handle reset with confirmation (special case - needs confirmation)
 		if input.Action == presentation.Reset {
 			if promptResetConfirmation(state, reader) {
@@ -1155,10 +2493,23 @@ func runWeekPlanner(reader input.InputReader) error {
 		lastMessage = message
 	}
 
+	// Saved or discarded on the way out, so the draft is no longer needed
+	discardDraft(journal)
+
 	// Screen will be cleared by defer
 	return nil
 }
 
+// saveWeekPlan saves the week plan and commits the notes it wrote right away
+func saveWeekPlan(state *data.WeekPlannerState) error {
+	notes := state.Plan.SavedNotes()
+	if err := state.Save(); err != nil {
+		return err
+	}
+	scripts.RunDescribedCommit("./notes", "week plan", notes)
+	return nil
+}
+
 // promptSaveChanges prompts the user to save changes before exiting
 // Returns false if user wants to exit, true if user cancels exit
 func promptSaveChanges(state *data.WeekPlannerState, reader input.InputReader) bool {
@@ -1174,7 +2525,7 @@ func promptSaveChanges(state *data.WeekPlannerState, reader input.InputReader) b
 		switch char {
 		case 'y', 'Y':
 			fmt.Println("y")
-			err := state.Save()
+			err := saveWeekPlan(state)
 			if err != nil {
 				fmt.Printf("Error saving changes: %v\n", err)
 				fmt.Println("Press any key to continue...")
@@ -1303,7 +2654,18 @@ func runObjectivesViewWithState(reader input.InputReader, state *data.Objectives
 		termHeight = 30
 	}
 
+	subscription := subscribeToNoteChanges()
+	defer subscription.Close()
+	watched := input.NewWatchedReader(reader, subscription.Changed())
+
+	// Journal where the view is, so a crash or a closed terminal can come back to it
+	// Edits are written at once, so the draft is dropped however the view is left
+	journal := data.NewDraftJournal(data.ObjectivesDraftName)
+	defer discardDraft(journal)
+
 	for {
+		recordDraft(journal, state.Draft())
+
 		// Render current view
 		var display string
 		if state.ViewMode == data.ObjectivesListView {
@@ -1312,6 +2674,7 @@ func runObjectivesViewWithState(reader input.InputReader, state *data.Objectives
 			display = presentation.RenderSingleObjectiveView(state, termWidth, termHeight)
 		}
 		fmt.Print(display)
+		fmt.Print(presentation.RenderLogStatus(termWidth))
 
 		if lastMessage != "" {
 			fmt.Printf("\n%s\n", lastMessage)
@@ -1319,11 +2682,23 @@ func runObjectivesViewWithState(reader input.InputReader, state *data.Objectives
 		}
 
 		// Get input
-		char, key, err := reader.GetKey()
+		char, key, err := watched.GetKey()
 		if err != nil {
 			return fmt.Errorf("error reading input: %w", err)
 		}
 
+		if interrupted(key) {
+			return nil
+		}
+
+		if key == input.KeyNotesChanged {
+			subscription.Take()
+			if err := state.Refresh(); err != nil {
+				lastMessage = fmt.Sprintf("Error refreshing: %v", err)
+			}
+			continue
+		}
+
 		// Handle 'dd' for delete
 		if char == 'd' && lastChar == 'd' {
 			lastChar = rune(0) // Reset
@@ -1453,7 +2828,21 @@ func runObjectivesViewWithState(reader input.InputReader, state *data.Objectives
 
 		case presentation.ObjEditParent:
 			if state.ViewMode == data.SingleObjectiveView {
+				before := *state.CurrentObjective
+				linkIndex, indexErr := data.BuildLinkIndex()
+
 				openNoteInEditor(state.CurrentObjective.Name)
+
+				// Renaming the objective in the editor keeps links to it working
+				after, err := data.LoadFileByName(before.Name)
+				if err == nil && indexErr == nil && after.Title != before.Title {
+					rewritten := scripts.PlanLinkRewrites(linkIndex.ReferencingFiles(before.Name), scripts.NewLinkRename(before, after))
+					if err := data.WriteFilesAtomically(rewritten); err != nil {
+						lastMessage = fmt.Sprintf("Error updating links: %v", err)
+					} else {
+						lastMessage = fmt.Sprintf("Updated links in %d notes", len(rewritten))
+					}
+				}
 				state.Refresh()
 			}
 
@@ -1627,12 +3016,6 @@ func runObjectivesViewWithState(reader input.InputReader, state *data.Objectives
 }
 
 func runTalkToView(filterPerson string, reader input.InputReader) error {
-	// Get terminal dimensions
-	termWidth, termHeight, _ := term.GetSize(int(os.Stdout.Fd()))
-	if termWidth == 0 {
-		termWidth, termHeight = 100, 30 // Default dimensions
-	}
-
 	// Initialize state
 	state, err := data.NewTalkToViewState(filterPerson)
 	if err != nil {
@@ -1649,12 +3032,34 @@ func runTalkToView(filterPerson string, reader input.InputReader) error {
 		return nil
 	}
 
+	return runTalkToViewWithState(state, reader)
+}
+
+func runTalkToViewWithState(state *data.TalkToViewState, reader input.InputReader) error {
+	// Get terminal dimensions
+	termWidth, termHeight, _ := term.GetSize(int(os.Stdout.Fd()))
+	if termWidth == 0 {
+		termWidth, termHeight = 100, 30 // Default dimensions
+	}
+
 	lastMessage := ""
 
+	// Journal the moves that can still be undone, so a crash or a closed terminal doesn't lose them
+	// Moves can't be undone once the view is left, so the draft goes with it
+	journal := data.NewDraftJournal(data.TalkToDraftName)
+	defer discardDraft(journal)
+
 	for {
+		if draft := state.Draft(); draft != nil {
+			recordDraft(journal, draft)
+		} else {
+			discardDraft(journal)
+		}
+
 		// Render current view
 		display := presentation.RenderTalkToView(state, termWidth, termHeight)
 		fmt.Print(display)
+		fmt.Print(presentation.RenderLogStatus(termWidth))
 
 		if lastMessage != "" {
 			fmt.Printf("\n%s\n", lastMessage)
@@ -1667,6 +3072,16 @@ func runTalkToView(filterPerson string, reader input.InputReader) error {
 			return fmt.Errorf("error reading input: %w", err)
 		}
 
+		if interrupted(key) {
+			break
+		}
+
+		// An undo pops its move while handling, so hold on to it for the commit
+		var lastMove data.MoveChange
+		if len(state.UndoStack) > 0 {
+			lastMove = state.UndoStack[len(state.UndoStack)-1]
+		}
+
 		// Parse and handle input
 		input := presentation.ParseTalkToInput(char, key, state.ViewMode, state.SearchMode)
 		shouldExit, message, err := presentation.HandleTalkToInput(state, input)
@@ -1675,7 +3090,12 @@ func runTalkToView(filterPerson string, reader input.InputReader) error {
 		}
 
 		// Handle special messages
-		if strings.HasPrefix(message, "OPEN_NOTE:") {
+		if message == "MOVED:" {
+			move := state.UndoStack[len(state.UndoStack)-1]
+			scripts.RunOperationCommit("./notes", fmt.Sprintf("talk-to: move %d todos for %s to %s", len(move.Todos), move.Person, move.TargetNote), move.Paths())
+		} else if message == "UNDONE:" {
+			scripts.RunOperationCommit("./notes", fmt.Sprintf("talk-to: undo move of %d todos for %s to %s", len(lastMove.Todos), lastMove.Person, lastMove.TargetNote), lastMove.Paths())
+		} else if strings.HasPrefix(message, "OPEN_NOTE:") {
 			fileName := strings.TrimPrefix(message, "OPEN_NOTE:")
 			fmt.Print("\033[2J\033[H") // Clear screen
 			openNoteInEditor(fileName)
@@ -1743,10 +3163,15 @@ func runGraphView(centerFile scripts.File, reader input.InputReader, fileStore *
 
 	lastMessage := ""
 
+	subscription := subscribeToNoteChanges()
+	defer subscription.Close()
+	watched := input.NewWatchedReader(reader, subscription.Changed())
+
 	for {
 		// Render the UI
 		display := presentation.RenderGraphView(state, termWidth, termHeight)
 		fmt.Print(display)
+		fmt.Print(presentation.RenderLogStatus(termWidth))
 
 		if lastMessage != "" {
 			fmt.Printf("\n%s\n", lastMessage)
@@ -1754,11 +3179,23 @@ func runGraphView(centerFile scripts.File, reader input.InputReader, fileStore *
 		}
 
 		// Get input
-		char, key, err := reader.GetKey()
+		char, key, err := watched.GetKey()
 		if err != nil {
 			return fmt.Errorf("error reading input: %w", err)
 		}
 
+		if interrupted(key) {
+			return nil
+		}
+
+		if key == input.KeyNotesChanged {
+			subscription.Take()
+			if err := state.Refresh(); err != nil {
+				lastMessage = fmt.Sprintf("Error refreshing: %v", err)
+			}
+			continue
+		}
+
 		switch {
 		case char == 'q' || key == keyboard.KeyEsc:
 			return nil
@@ -1783,6 +3220,128 @@ func runGraphView(centerFile scripts.File, reader input.InputReader, fileStore *
 				openNoteInEditor(node.File.Name)
 				state.Refresh()
 			}
+
+		case char == '+' || char == '=':
+			err = state.IncreaseDepth()
+
+		case char == '-':
+			err = state.DecreaseDepth()
+
+		case char == 't':
+			fmt.Print("\nShow notes tagged: ")
+			tag, inputErr := getLineInput(reader)
+			if inputErr == nil {
+				err = state.SetTagFilter(tag)
+			}
+
+		case char == 's':
+			err = state.CycleStatusFilter()
+
+		case char == 'y':
+			err = state.CycleTypeFilter()
+
+		case char == 'x':
+			err = state.ClearFilters()
+
+		case char == 'p':
+			// Shortest link path from the center to a note picked by title
+			fmt.Print("\nPath to note: ")
+			title, inputErr := getLineInput(reader)
+			if inputErr != nil {
+				break
+			}
+			target := state.ResolveLink(title)
+			if target == nil {
+				lastMessage = fmt.Sprintf("Note not found: %s", title)
+				break
+			}
+			err = state.FindPathTo(*target)
+		}
+
+		if err != nil {
+			lastMessage = fmt.Sprintf("Error: %v", err)
+		}
+	}
+}
+
+// runHistoryView shows the commits of a note with a diff against the current version
+// Restoring writes the selected version back and commits it, keeping the history intact
+func runHistoryView(state *data.HistoryViewState, reader input.InputReader) error {
+	// Ensure terminal is cleaned up on all exit paths
+	defer func() {
+		fmt.Print("\033[2J\033[H")
+	}()
+
+	termWidth, termHeight, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		termWidth = 100
+		termHeight = 30
+	}
+
+	lastMessage := ""
+
+	for {
+		fmt.Print(presentation.RenderHistoryView(state, termWidth, termHeight))
+		fmt.Print(presentation.RenderLogStatus(termWidth))
+
+		if lastMessage != "" {
+			fmt.Printf("\n%s\n", lastMessage)
+			lastMessage = ""
+		}
+
+		char, key, err := reader.GetKey()
+		if err != nil {
+			return fmt.Errorf("error reading input: %w", err)
+		}
+
+		if interrupted(key) {
+			return nil
+		}
+
+		switch {
+		case char == 'q' || key == keyboard.KeyEsc:
+			return nil
+
+		case char == 'j' || key == keyboard.KeyArrowDown:
+			state.SelectNext()
+
+		case char == 'k' || key == keyboard.KeyArrowUp:
+			state.SelectPrevious()
+
+		case char == 'r':
+			version := state.SelectedVersion()
+			if version == nil {
+				break
+			}
+
+			fmt.Printf("\nRestore %s to %s from %s? (y/n): ", state.RestoreTarget(), version.ShortHash(), version.Date.Format("2006-01-02 15:04"))
+			confirmed := false
+			for {
+				answer, _, err := reader.GetKey()
+				if err != nil {
+					return fmt.Errorf("error reading input: %w", err)
+				}
+				if answer == 'y' || answer == 'Y' || answer == 'n' || answer == 'N' {
+					confirmed = answer == 'y' || answer == 'Y'
+					break
+				}
+			}
+			if !confirmed {
+				lastMessage = "Cancelled."
+				break
+			}
+
+			shortHash := version.ShortHash()
+			fileName, err := state.RestoreSelected()
+			if err != nil {
+				lastMessage = fmt.Sprintf("Error: %v", err)
+				break
+			}
+			scripts.RunOperationCommit("./notes", fmt.Sprintf("restore: %s from %s", fileName, shortHash), []string{fileName})
+			lastMessage = fmt.Sprintf("Restored %s from %s", fileName, shortHash)
+			if err := state.Refresh(); err != nil {
+				lastMessage = fmt.Sprintf("Error: %v", err)
+			}
 		}
 	}
 }
@@ -1851,17 +3410,30 @@ func runSearchViewForLinking(linkSourceFileName string, reader input.InputReader
 		return nil, fmt.Errorf("error initializing search: %w", err)
 	}
 
+	subscription := subscribeToNoteChanges()
+	defer subscription.Close()
+	watched := input.NewWatchedReader(reader, subscription.Changed())
+
 	for {
 		// Render the UI
 		display := presentation.RenderSearchView(state, termWidth, termHeight)
 		fmt.Print(display)
+		fmt.Print(presentation.RenderLogStatus(termWidth))
 
 		// Get input
-		char, key, err := reader.GetKey()
+		char, key, err := watched.GetKey()
 		if err != nil {
 			return nil, fmt.Errorf("error reading input: %w", err)
 		}
 
+		if key == input.KeyNotesChanged {
+			invalidateSearchIndex(subscription.Take())
+			if err := state.Refresh(); err != nil {
+				return nil, fmt.Errorf("error refreshing search: %w", err)
+			}
+			continue
+		}
+
 		// Parse input with state awareness for link mode
 		input := presentation.ParseSearchInputWithState(char, key, state)
 
@@ -1905,6 +3477,15 @@ func runSearchViewForLinking(linkSourceFileName string, reader input.InputReader
 
 		case presentation.SearchCycleMatchMode:
 			state.CycleMatchMode()
+
+		case presentation.SearchOpenTagTree:
+			state.EnterTagTreeMode()
+
+		case presentation.SearchApplyTagFilter:
+			state.ApplySelectedTagFilter()
+
+		case presentation.SearchClearTagFilter:
+			state.ClearTagFilter()
 		}
 	}
 }
@@ -1930,10 +3511,15 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 
 	lastMessage := ""
 
+	subscription := subscribeToNoteChanges()
+	defer subscription.Close()
+	watched := input.NewWatchedReader(reader, subscription.Changed())
+
 	for {
 		// Render the UI
 		display := presentation.RenderSearchView(state, termWidth, termHeight)
 		fmt.Print(display)
+		fmt.Print(presentation.RenderLogStatus(termWidth))
 
 		if lastMessage != "" {
 			fmt.Printf("\033[%d;1H%s", termHeight+1, lastMessage)
@@ -1941,13 +3527,24 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 		}
 
 		// Get input
-		char, key, err := reader.GetKey()
+		char, key, err := watched.GetKey()
 		if err != nil {
 			return fmt.Errorf("error reading input: %w", err)
 		}
 
+		if key == input.KeyNotesChanged {
+			invalidateSearchIndex(subscription.Take())
+			if err := state.Refresh(); err != nil {
+				lastMessage = fmt.Sprintf("Error refreshing: %v", err)
+			}
+			continue
+		}
+
 		// Parse input with state awareness for link mode
 		input := presentation.ParseSearchInputWithState(char, key, state)
+		if interrupted(key) {
+			input.Action = presentation.SearchQuit
+		}
 
 		switch input.Action {
 		case presentation.SearchNoAction:
@@ -1983,7 +3580,7 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 							if err != nil {
 								lastMessage = fmt.Sprintf("Error: %v", err)
 							} else if selectedNote != nil {
-								err := data.InsertLinkAtTop(result.File.Name, selectedNote.Title)
+								err := data.InsertIDLinkAtTop(result.File.Name, *selectedNote)
 								if err != nil {
 									lastMessage = fmt.Sprintf("Error adding link: %v", err)
 								} else {
@@ -2038,12 +3635,7 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 						default:
 							lastMessage = executeSearchAction(action, result, state)
 						}
-						// Refresh state after action
-						oldFilterMode := state.FilterMode
-						state, _ = data.NewSearchState(state.Query)
-						state.FilterMode = oldFilterMode
-						state.UpdateQuery(state.Query)
-						state.ViewMode = data.SearchModeNormal
+						state = reloadSearchState(state, data.SearchModeNormal)
 					}
 				}
 				state.ExitActionsMode()
@@ -2086,11 +3678,7 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 					lastMessage = fmt.Sprintf("Error: %v", err)
 				} else {
 					lastMessage = fmt.Sprintf("Priority set to P%d", priority)
-					// Refresh state
-					oldFilterMode := state.FilterMode
-					state, _ = data.NewSearchState(state.Query)
-					state.FilterMode = oldFilterMode
-					state.UpdateQuery(state.Query)
+					state = reloadSearchState(state, data.SearchModeInsert)
 				}
 			}
 
@@ -2107,11 +3695,7 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 					} else {
 						lastMessage = "Marked as incomplete"
 					}
-					// Refresh state
-					oldFilterMode := state.FilterMode
-					state, _ = data.NewSearchState(state.Query)
-					state.FilterMode = oldFilterMode
-					state.UpdateQuery(state.Query)
+					state = reloadSearchState(state, data.SearchModeInsert)
 				}
 			}
 
@@ -2123,10 +3707,7 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 					lastMessage = fmt.Sprintf("Error: %v", err)
 				} else {
 					lastMessage = "Due date set to today"
-					oldFilterMode := state.FilterMode
-					state, _ = data.NewSearchState(state.Query)
-					state.FilterMode = oldFilterMode
-					state.UpdateQuery(state.Query)
+					state = reloadSearchState(state, data.SearchModeInsert)
 				}
 			}
 
@@ -2136,6 +3717,15 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 		case presentation.SearchCycleMatchMode:
 			state.CycleMatchMode()
 
+		case presentation.SearchOpenTagTree:
+			state.EnterTagTreeMode()
+
+		case presentation.SearchApplyTagFilter:
+			state.ApplySelectedTagFilter()
+
+		case presentation.SearchClearTagFilter:
+			state.ClearTagFilter()
+
 		case presentation.SearchSetLinkSource:
 			// First step of two-note linking: set current note as source
 			result := state.GetSelectedResult()
@@ -2154,7 +3744,7 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 					lastMessage = "Cannot link a note to itself"
 				} else {
 					// Add link from source to target
-					err := data.InsertLinkAtTop(state.PendingLinkSource.Name, result.File.Title)
+					err := data.InsertIDLinkAtTop(state.PendingLinkSource.Name, result.File)
 					if err != nil {
 						lastMessage = fmt.Sprintf("Error adding link: %v", err)
 					} else {
@@ -2196,24 +3786,14 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 						}
 					}
 				}
-				// Refresh state
-				oldFilterMode := state.FilterMode
-				state, _ = data.NewSearchState(state.Query)
-				state.FilterMode = oldFilterMode
-				state.UpdateQuery(state.Query)
-				state.ViewMode = data.SearchModeNormal
+				state = reloadSearchState(state, data.SearchModeNormal)
 			}
 
 		case presentation.SearchOpenGraph:
 			result := state.GetSelectedResult()
 			if result != nil {
 				runGraphView(result.File, reader, fileStore)
-				// Refresh state after returning
-				oldFilterMode := state.FilterMode
-				state, _ = data.NewSearchState(state.Query)
-				state.FilterMode = oldFilterMode
-				state.UpdateQuery(state.Query)
-				state.ViewMode = data.SearchModeNormal
+				state = reloadSearchState(state, data.SearchModeNormal)
 			}
 
 		case presentation.SearchOpenObjective:
@@ -2232,12 +3812,7 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 					} else {
 						lastMessage = fmt.Sprintf("Error: %v", err)
 					}
-					// Refresh state after returning
-					oldFilterMode := state.FilterMode
-					state, _ = data.NewSearchState(state.Query)
-					state.FilterMode = oldFilterMode
-					state.UpdateQuery(state.Query)
-					state.ViewMode = data.SearchModeNormal
+					state = reloadSearchState(state, data.SearchModeNormal)
 				} else {
 					lastMessage = "Note is not linked to any objective"
 				}
@@ -2246,10 +3821,29 @@ func runSearchView(initialQuery string, reader input.InputReader, fileStore *dat
 	}
 }
 
+// reloadSearchState reads the notes again after an action changed them, keeping the query and filters
+func reloadSearchState(state *data.SearchState, mode data.SearchViewMode) *data.SearchState {
+	reloaded, err := data.NewSearchState(state.Query)
+	if err != nil {
+		return state
+	}
+	reloaded.FilterMode = state.FilterMode
+	reloaded.TagFilter = state.TagFilter
+	reloaded.UpdateQuery(reloaded.Query)
+	reloaded.ViewMode = mode
+	return reloaded
+}
+
 func executeSearchAction(action *data.QuickAction, result *data.SearchResult, state *data.SearchState) string {
 	switch action.Key {
 	case 'e':
-		openNoteInEditor(result.File.Name)
+		// A [[link]] query opens the note on the section it targets
+		query := strings.TrimSpace(state.Query)
+		if strings.HasPrefix(query, "[[") && strings.HasSuffix(query, "]]") {
+			openLinkInEditor(result.File.Name, query[2:len(query)-2])
+		} else {
+			openNoteInEditor(result.File.Name)
+		}
 		return "Note opened"
 
 	case 'd':
@@ -2278,6 +3872,17 @@ func executeSearchAction(action *data.QuickAction, result *data.SearchResult, st
 		}
 		return "Due date set to today"
 
+	case 'r':
+		related := state.RelatedNotes(result.File)
+		if len(related) == 0 {
+			return "No related notes"
+		}
+		target := related[0].File
+		if err := data.InsertIDLinkAtTop(result.File.Name, target); err != nil {
+			return fmt.Sprintf("Error adding link: %v", err)
+		}
+		return fmt.Sprintf("Linked to \"%s\"", target.Title)
+
 	default:
 		return ""
 	}
@@ -0,0 +1,380 @@
+package scripts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backup snapshots are named notes-<time>.tar.gz after the time they were taken
+const (
+	backupSnapshotPrefix = "notes-"
+	backupSnapshotSuffix = ".tar.gz"
+	backupTimeLayout     = "2006-01-02T150405"
+)
+
+// BackupPolicy is how often snapshots are taken and which of them are kept
+// The newest snapshot of each of the last KeepHourly hours, KeepDaily days and KeepWeekly weeks is kept
+type BackupPolicy struct {
+	SnapshotInterval time.Duration
+	KeepHourly       int
+	KeepDaily        int
+	KeepWeekly       int
+}
+
+// BackupSnapshot is a compressed archive of the notes taken at a point in time
+type BackupSnapshot struct {
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// BackupSnapshotsDir returns the directory the backup snapshots are kept in
+func BackupSnapshotsDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "Documents", "notes-backups"), nil
+}
+
+// CreateBackupSnapshot archives every note of srcDir into a new snapshot in backupDir
+// The archive is written to a temporary file first, so a snapshot is never left half written
+func CreateBackupSnapshot(srcDir, backupDir string, now time.Time) (BackupSnapshot, error) {
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return BackupSnapshot{}, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	path := filepath.Join(backupDir, backupSnapshotPrefix+now.Format(backupTimeLayout)+backupSnapshotSuffix)
+	for {
+		// Never replace a snapshot taken within the same second, such as the one being restored
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		now = now.Add(time.Second)
+		path = filepath.Join(backupDir, backupSnapshotPrefix+now.Format(backupTimeLayout)+backupSnapshotSuffix)
+	}
+	temp, err := os.CreateTemp(backupDir, ".snapshot-*")
+	if err != nil {
+		return BackupSnapshot{}, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	err = writeNotesArchive(srcDir, temp)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return BackupSnapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return BackupSnapshot{}, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return BackupSnapshot{}, err
+	}
+	return BackupSnapshot{Path: path, Time: parseBackupTime(path), Size: info.Size()}, nil
+}
+
+// writeNotesArchive writes the .md files of srcDir as a gzipped tar
+func writeNotesArchive(srcDir string, out io.Writer) error {
+	gz := gzip.NewWriter(out)
+	archive := tar.NewWriter(gz)
+
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != srcDir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir // Skip .git
+			}
+			return nil
+		}
+		if !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		header := &tar.Header{
+			Name:    filepath.ToSlash(relPath),
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: info.ModTime(),
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = archive.Write(content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ListBackupSnapshots returns the snapshots in backupDir, newest first
+func ListBackupSnapshots(backupDir string) ([]BackupSnapshot, error) {
+	entries, err := os.ReadDir(backupDir)
+	if os.IsNotExist(err) {
+		return []BackupSnapshot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]BackupSnapshot, 0, len(entries))
+	for _, entry := range entries {
+		path := filepath.Join(backupDir, entry.Name())
+		taken := parseBackupTime(path)
+		if entry.IsDir() || taken.IsZero() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, BackupSnapshot{Path: path, Time: taken, Size: info.Size()})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.After(snapshots[j].Time)
+	})
+	return snapshots, nil
+}
+
+// parseBackupTime reads the time a snapshot was taken from its name, zero when it is not a snapshot
+func parseBackupTime(path string) time.Time {
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, backupSnapshotPrefix) || !strings.HasSuffix(name, backupSnapshotSuffix) {
+		return time.Time{}
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupSnapshotPrefix), backupSnapshotSuffix)
+	taken, err := time.ParseInLocation(backupTimeLayout, stamp, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return taken
+}
+
+// BackupSnapshotDue reports whether a new snapshot should be taken: the notes changed since the latest
+// snapshot and it is older than the interval. The very first snapshot is always due
+func BackupSnapshotDue(srcDir string, snapshots []BackupSnapshot, now time.Time, interval time.Duration) bool {
+	if len(snapshots) == 0 {
+		return true
+	}
+	latest := snapshots[0]
+	return now.Sub(latest.Time) >= interval && notesChangedSince(srcDir, latest.Time)
+}
+
+// notesChangedSince reports whether a note was written, added or deleted after a time
+// Deleting a note only changes the modification time of its directory
+func notesChangedSince(srcDir string, since time.Time) bool {
+	changed := false
+	_ = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || changed {
+			return filepath.SkipDir
+		}
+		if info.IsDir() && path != srcDir && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if (info.IsDir() || strings.HasSuffix(info.Name(), ".md")) && info.ModTime().After(since) {
+			changed = true
+		}
+		return nil
+	})
+	return changed
+}
+
+// PruneBackupSnapshots deletes the snapshots the policy doesn't keep and returns them
+func PruneBackupSnapshots(backupDir string, policy BackupPolicy) ([]BackupSnapshot, error) {
+	snapshots, err := ListBackupSnapshots(backupDir)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := backupsToKeep(snapshots, policy)
+	pruned := make([]BackupSnapshot, 0)
+	for _, snapshot := range snapshots {
+		if keep[snapshot.Path] {
+			continue
+		}
+		if err := os.Remove(snapshot.Path); err != nil {
+			return pruned, fmt.Errorf("failed to delete snapshot %s: %w", filepath.Base(snapshot.Path), err)
+		}
+		pruned = append(pruned, snapshot)
+	}
+	return pruned, nil
+}
+
+// backupsToKeep picks the newest snapshot of each of the last hours, days and weeks that have one,
+// from snapshots sorted newest first. The newest snapshot is always kept
+func backupsToKeep(snapshots []BackupSnapshot, policy BackupPolicy) map[string]bool {
+	keep := make(map[string]bool)
+	if len(snapshots) > 0 {
+		keep[snapshots[0].Path] = true
+	}
+
+	periods := []struct {
+		count int
+		key   func(time.Time) string
+	}{
+		{policy.KeepHourly, func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{policy.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{policy.KeepWeekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+	}
+	for _, period := range periods {
+		seen := make(map[string]bool)
+		for _, snapshot := range snapshots {
+			key := period.key(snapshot.Time)
+			if seen[key] {
+				continue
+			}
+			if len(seen) == period.count {
+				break
+			}
+			seen[key] = true
+			keep[snapshot.Path] = true
+		}
+	}
+	return keep
+}
+
+// ReadBackupSnapshot returns the content of every note in a snapshot, by path
+func ReadBackupSnapshot(snapshot BackupSnapshot) (map[string]string, error) {
+	return readBackupArchive(snapshot, true)
+}
+
+// CountBackupNotes returns how many notes a snapshot holds, without keeping their content
+func CountBackupNotes(snapshot BackupSnapshot) (int, error) {
+	notes, err := readBackupArchive(snapshot, false)
+	return len(notes), err
+}
+
+// readBackupArchive reads the notes of a snapshot by path, with their content when withContent is set
+func readBackupArchive(snapshot BackupSnapshot, withContent bool) (map[string]string, error) {
+	file, err := os.Open(snapshot.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", filepath.Base(snapshot.Path), err)
+	}
+	defer gz.Close()
+
+	notes := make(map[string]string)
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %s: %w", filepath.Base(snapshot.Path), err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		notes[header.Name] = ""
+		if !withContent {
+			continue
+		}
+		content, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from snapshot: %w", header.Name, err)
+		}
+		notes[header.Name] = string(content)
+	}
+	return notes, nil
+}
+
+// RestoreBackupNote writes a single note of a snapshot back into notesDir
+func RestoreBackupNote(notes map[string]string, notesDir, name string) error {
+	content, exists := notes[name]
+	if !exists {
+		return fmt.Errorf("%s is not in the backup", name)
+	}
+	path, err := backupNotePath(notesDir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// RestoreBackupVault makes notesDir hold exactly the notes of a snapshot
+// Returns how many notes were written and how many notes missing from the snapshot were removed
+func RestoreBackupVault(notes map[string]string, notesDir string) (int, int, error) {
+	restored := 0
+	for name := range notes {
+		if err := RestoreBackupNote(notes, notesDir, name); err != nil {
+			return restored, 0, fmt.Errorf("failed to restore %s: %w", name, err)
+		}
+		restored++
+	}
+
+	removed := 0
+	err := filepath.Walk(notesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != notesDir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath, err := filepath.Rel(notesDir, path)
+		if err != nil {
+			return err
+		}
+		if _, inBackup := notes[filepath.ToSlash(relPath)]; inBackup || !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return restored, removed, err
+}
+
+// backupNotePath returns where a note of a snapshot is restored, refusing paths outside notesDir
+func backupNotePath(notesDir, name string) (string, error) {
+	path := filepath.Join(notesDir, filepath.FromSlash(name))
+	relPath, err := filepath.Rel(notesDir, path)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid note path in backup: %s", name)
+	}
+	return path, nil
+}
@@ -0,0 +1,267 @@
+package scripts
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// BACKUP_MANIFEST_NAME is the file in each mirror that records the SHA-256 of every note copied to it,
+// in the format of sha256sum so it can also be checked by hand
+const BACKUP_MANIFEST_NAME = ".manifest.sha256"
+
+// BackupManifest maps the slash-separated path of each note in a mirror to the SHA-256 of its content
+type BackupManifest map[string]string
+
+// BackupVerification is what verifying a mirror against the notes and its manifest found
+type BackupVerification struct {
+	Dir        string
+	Checked    int      // Notes in the mirror
+	Missing    []string // Notes in the vault or the manifest that are not in the mirror
+	Extra      []string // Notes in the mirror that are not in the vault
+	Differing  []string // Notes whose copy in the mirror differs from the vault
+	Corrupt    []string // Notes whose copy no longer matches the hash recorded when it was copied
+	NoManifest bool     // The mirror has no readable manifest to check the copies against
+}
+
+// OK reports whether the mirror matches both the vault and its manifest
+func (v BackupVerification) OK() bool {
+	return len(v.Missing) == 0 && len(v.Extra) == 0 && len(v.Differing) == 0 && len(v.Corrupt) == 0 && !v.NoManifest
+}
+
+// Damaged lists the notes a repair copies again: missing, differing or corrupt, without duplicates
+func (v BackupVerification) Damaged() []string {
+	damaged := make([]string, 0)
+	for _, paths := range [][]string{v.Missing, v.Differing, v.Corrupt} {
+		for _, path := range paths {
+			if !containsString(damaged, path) {
+				damaged = append(damaged, path)
+			}
+		}
+	}
+	sort.Strings(damaged)
+	return damaged
+}
+
+// ReadBackupManifest reads the manifest of a mirror, an os.IsNotExist error when it has none
+func ReadBackupManifest(dstDir string) (BackupManifest, error) {
+	file, err := os.Open(filepath.Join(dstDir, BACKUP_MANIFEST_NAME))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	manifest := BackupManifest{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		hash, path, found := strings.Cut(scanner.Text(), "  ")
+		if !found || len(hash) != sha256.Size*2 || path == "" {
+			return nil, fmt.Errorf("malformed manifest line %d in %s", line, dstDir)
+		}
+		manifest[path] = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// writeBackupManifest replaces the manifest of a mirror, sorted by path
+func writeBackupManifest(dstDir string, manifest BackupManifest) error {
+	paths := make([]string, 0, len(manifest))
+	for path := range manifest {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var content strings.Builder
+	for _, path := range paths {
+		fmt.Fprintf(&content, "%s  %s\n", manifest[path], path)
+	}
+
+	manifestPath := filepath.Join(dstDir, BACKUP_MANIFEST_NAME)
+	tempPath := manifestPath + ".tmp"
+	if err := os.WriteFile(tempPath, []byte(content.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, manifestPath)
+}
+
+// updateBackupManifest records the hashes of the notes just copied to a mirror and of any note
+// in it the manifest doesn't know yet, and forgets the notes no longer in it
+// Unknown notes are hashed from the vault when they are still there, from the mirror otherwise
+func updateBackupManifest(srcDir, dstDir string, backupFiles map[string]bool, copiedHashes map[string]string) error {
+	manifest, err := ReadBackupManifest(dstDir)
+	changed := false
+	if err != nil {
+		manifest = BackupManifest{}
+		changed = true
+	}
+
+	for relPath, hash := range copiedHashes {
+		key := filepath.ToSlash(relPath)
+		if manifest[key] != hash {
+			manifest[key] = hash
+			changed = true
+		}
+	}
+
+	for relPath := range backupFiles {
+		key := filepath.ToSlash(relPath)
+		if _, known := manifest[key]; known {
+			continue
+		}
+		hash, err := hashFile(filepath.Join(srcDir, relPath))
+		if err != nil {
+			hash, err = hashFile(filepath.Join(dstDir, relPath))
+		}
+		if err != nil {
+			return err
+		}
+		manifest[key] = hash
+		changed = true
+	}
+
+	for key := range manifest {
+		if !backupFiles[filepath.FromSlash(key)] {
+			delete(manifest, key)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return writeBackupManifest(dstDir, manifest)
+}
+
+// VerifyBackup hashes every note of the vault and of a mirror and compares them with each other
+// and with the mirror's manifest
+func VerifyBackup(srcDir, dstDir string) (BackupVerification, error) {
+	verification := BackupVerification{Dir: dstDir}
+
+	sourceHashes, err := hashNotes(srcDir)
+	if err != nil {
+		return verification, fmt.Errorf("failed to read notes: %w", err)
+	}
+	backupHashes, err := hashNotes(dstDir)
+	if err != nil {
+		return verification, fmt.Errorf("failed to read backup: %w", err)
+	}
+	manifest, err := ReadBackupManifest(dstDir)
+	if err != nil {
+		verification.NoManifest = true
+		manifest = BackupManifest{}
+	}
+
+	verification.Checked = len(backupHashes)
+	for path, sourceHash := range sourceHashes {
+		backupHash, inBackup := backupHashes[path]
+		if !inBackup {
+			verification.Missing = append(verification.Missing, path)
+		} else if backupHash != sourceHash {
+			verification.Differing = append(verification.Differing, path)
+		}
+	}
+	for path, backupHash := range backupHashes {
+		if _, inSource := sourceHashes[path]; !inSource {
+			verification.Extra = append(verification.Extra, path)
+		}
+		if recorded, inManifest := manifest[path]; inManifest && recorded != backupHash {
+			verification.Corrupt = append(verification.Corrupt, path)
+		}
+	}
+	for path := range manifest {
+		_, inSource := sourceHashes[path]
+		if _, inBackup := backupHashes[path]; !inBackup && !inSource {
+			verification.Missing = append(verification.Missing, path)
+		}
+	}
+
+	for _, paths := range [][]string{verification.Missing, verification.Extra, verification.Differing, verification.Corrupt} {
+		sort.Strings(paths)
+	}
+	return verification, nil
+}
+
+// RepairBackup copies the damaged notes of a verification from the vault to the mirror again
+// and rewrites the manifest, returning how many notes were copied
+// Damaged notes no longer in the vault can't be repaired and are left as they are
+func RepairBackup(srcDir string, verification BackupVerification) (int, error) {
+	dstDir := verification.Dir
+	copiedHashes := make(map[string]string)
+	for _, path := range verification.Damaged() {
+		srcPath := filepath.Join(srcDir, filepath.FromSlash(path))
+		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
+			continue
+		}
+
+		dstPath := filepath.Join(dstDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			return len(copiedHashes), err
+		}
+		hash, err := copyFile(srcPath, dstPath)
+		if err != nil {
+			return len(copiedHashes), fmt.Errorf("failed to copy %s: %w", path, err)
+		}
+		copiedHashes[filepath.FromSlash(path)] = hash
+	}
+
+	backupHashes, err := hashNotes(dstDir)
+	if err != nil {
+		return len(copiedHashes), fmt.Errorf("failed to read backup: %w", err)
+	}
+	backupFiles := make(map[string]bool, len(backupHashes))
+	for path := range backupHashes {
+		backupFiles[filepath.FromSlash(path)] = true
+	}
+	if err := updateBackupManifest(srcDir, dstDir, backupFiles, copiedHashes); err != nil {
+		return len(copiedHashes), fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	return len(copiedHashes), nil
+}
+
+// hashNotes returns the SHA-256 of every note under dir, by slash-separated path
+func hashNotes(dir string) (map[string]string, error) {
+	hashes := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		hash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		hashes[filepath.ToSlash(relPath)] = hash
+		return nil
+	})
+	return hashes, err
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
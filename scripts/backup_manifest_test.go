@@ -0,0 +1,145 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncWritesBackupManifest(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	os.WriteFile(filepath.Join(srcDir, "note1.md"), []byte("# Note 1"), 0644)
+	os.WriteFile(filepath.Join(srcDir, "note2.md"), []byte("# Note 2"), 0644)
+
+	if _, err := SyncNotesToBackup(srcDir, dstDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manifest, err := ReadBackupManifest(dstDir)
+	if err != nil {
+		t.Fatalf("ReadBackupManifest failed: %v", err)
+	}
+	hash, _ := hashFile(filepath.Join(srcDir, "note1.md"))
+	if len(manifest) != 2 || manifest["note1.md"] != hash {
+		t.Errorf("Expected both notes in the manifest, got %v", manifest)
+	}
+
+	os.Remove(filepath.Join(srcDir, "note2.md"))
+	if _, err := SyncNotesToBackup(srcDir, dstDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manifest, _ = ReadBackupManifest(dstDir)
+	if _, exists := manifest["note2.md"]; exists || len(manifest) != 1 {
+		t.Errorf("Expected the deleted note to leave the manifest, got %v", manifest)
+	}
+}
+
+func TestVerifyBackup_FindsMissingExtraDifferingAndCorrupt(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	for _, name := range []string{"kept.md", "lost.md", "edited.md", "rotten.md"} {
+		os.WriteFile(filepath.Join(srcDir, name), []byte("# "+name), 0644)
+	}
+	if _, err := SyncNotesToBackup(srcDir, dstDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	verification, err := VerifyBackup(srcDir, dstDir)
+	if err != nil {
+		t.Fatalf("VerifyBackup failed: %v", err)
+	}
+	if !verification.OK() || verification.Checked != 4 {
+		t.Fatalf("Expected a fresh backup to verify, got %+v", verification)
+	}
+
+	// Lost from the backup, only in the backup, a same-size edit with the time kept, and silent corruption
+	os.Remove(filepath.Join(dstDir, "lost.md"))
+	os.WriteFile(filepath.Join(dstDir, "stray.md"), []byte("# stray"), 0644)
+	edited := filepath.Join(srcDir, "edited.md")
+	info, _ := os.Stat(edited)
+	os.WriteFile(edited, []byte("# EDITED.md"), 0644)
+	os.Chtimes(edited, info.ModTime(), info.ModTime())
+	os.WriteFile(filepath.Join(dstDir, "rotten.md"), []byte("# rotten.mX"), 0644)
+
+	verification, err = VerifyBackup(srcDir, dstDir)
+	if err != nil {
+		t.Fatalf("VerifyBackup failed: %v", err)
+	}
+	if !reflect.DeepEqual(verification.Missing, []string{"lost.md"}) {
+		t.Errorf("Missing = %v", verification.Missing)
+	}
+	if !reflect.DeepEqual(verification.Extra, []string{"stray.md"}) {
+		t.Errorf("Extra = %v", verification.Extra)
+	}
+	if !reflect.DeepEqual(verification.Differing, []string{"edited.md", "rotten.md"}) {
+		t.Errorf("Differing = %v", verification.Differing)
+	}
+	if !reflect.DeepEqual(verification.Corrupt, []string{"rotten.md"}) {
+		t.Errorf("Corrupt = %v", verification.Corrupt)
+	}
+	if verification.OK() {
+		t.Error("Expected the damaged backup not to verify")
+	}
+}
+
+func TestRepairBackup_CopiesDamagedNotesAgain(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	os.WriteFile(filepath.Join(srcDir, "plan.md"), []byte("# Plan"), 0644)
+	os.WriteFile(filepath.Join(srcDir, "ideas.md"), []byte("# Ideas"), 0644)
+	if _, err := SyncNotesToBackup(srcDir, dstDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	corrupted := filepath.Join(dstDir, "plan.md")
+	os.WriteFile(corrupted, []byte("# Plxn"), 0644)
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(corrupted, future, future)
+	os.Remove(filepath.Join(dstDir, "ideas.md"))
+	os.Remove(filepath.Join(dstDir, BACKUP_MANIFEST_NAME))
+
+	verification, _ := VerifyBackup(srcDir, dstDir)
+	if !verification.NoManifest || !reflect.DeepEqual(verification.Damaged(), []string{"ideas.md", "plan.md"}) {
+		t.Fatalf("Expected both notes damaged and no manifest, got %+v", verification)
+	}
+
+	repaired, err := RepairBackup(srcDir, verification)
+	if err != nil {
+		t.Fatalf("RepairBackup failed: %v", err)
+	}
+	if repaired != 2 {
+		t.Errorf("Expected 2 notes repaired, got %d", repaired)
+	}
+	if verification, _ := VerifyBackup(srcDir, dstDir); !verification.OK() {
+		t.Errorf("Expected the repaired backup to verify, got %+v", verification)
+	}
+}
+
+func TestReadBackupManifest_RejectsMalformedLines(t *testing.T) {
+	dstDir := t.TempDir()
+	os.WriteFile(filepath.Join(dstDir, BACKUP_MANIFEST_NAME), []byte("not a hash\n"), 0644)
+
+	if _, err := ReadBackupManifest(dstDir); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Expected a malformed manifest error, got %v", err)
+	}
+	if _, err := ReadBackupManifest(t.TempDir()); !os.IsNotExist(err) {
+		t.Errorf("Expected a not exist error without a manifest, got %v", err)
+	}
+}
+
+func TestBackupMirrorDirs_ExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dirs, err := BackupMirrorDirs([]string{"~/Dropbox/notes", "/mnt/usb/notes"})
+	if err != nil {
+		t.Fatalf("BackupMirrorDirs failed: %v", err)
+	}
+	expected := []string{filepath.Join(home, "Documents", "notes"), filepath.Join(home, "Dropbox", "notes"), "/mnt/usb/notes"}
+	if !reflect.DeepEqual(dirs, expected) {
+		t.Errorf("BackupMirrorDirs() = %v, want %v", dirs, expected)
+	}
+}
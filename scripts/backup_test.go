@@ -0,0 +1,172 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCreateBackupSnapshot_RoundTrips(t *testing.T) {
+	srcDir := t.TempDir()
+	backupDir := t.TempDir()
+	os.WriteFile(filepath.Join(srcDir, "plan.md"), []byte("# Plan"), 0644)
+	os.WriteFile(filepath.Join(srcDir, "ignored.txt"), []byte("not a note"), 0644)
+	os.Mkdir(filepath.Join(srcDir, ".git"), 0755)
+	os.WriteFile(filepath.Join(srcDir, ".git", "HEAD.md"), []byte("git internals"), 0644)
+
+	taken := time.Date(2025, 11, 28, 15, 4, 5, 0, time.Local)
+	snapshot, err := CreateBackupSnapshot(srcDir, backupDir, taken)
+	if err != nil {
+		t.Fatalf("CreateBackupSnapshot failed: %v", err)
+	}
+	if filepath.Base(snapshot.Path) != "notes-2025-11-28T150405.tar.gz" || !snapshot.Time.Equal(taken) {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+
+	// A second snapshot in the same second must not replace the first
+	second, err := CreateBackupSnapshot(srcDir, backupDir, taken)
+	if err != nil {
+		t.Fatalf("Second CreateBackupSnapshot failed: %v", err)
+	}
+	if second.Path == snapshot.Path {
+		t.Errorf("Expected a new snapshot, got the same path %s", second.Path)
+	}
+
+	snapshots, err := ListBackupSnapshots(backupDir)
+	if err != nil {
+		t.Fatalf("ListBackupSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Path != second.Path {
+		t.Fatalf("Expected 2 snapshots newest first, got %+v", snapshots)
+	}
+
+	notes, err := ReadBackupSnapshot(snapshot)
+	if err != nil {
+		t.Fatalf("ReadBackupSnapshot failed: %v", err)
+	}
+	if len(notes) != 1 || notes["plan.md"] != "# Plan" {
+		t.Errorf("Expected only plan.md in the snapshot, got %q", notes)
+	}
+	if count, err := CountBackupNotes(snapshot); err != nil || count != 1 {
+		t.Errorf("CountBackupNotes() = %d, %v, want 1", count, err)
+	}
+}
+
+func TestListBackupSnapshots_MissingDirectory(t *testing.T) {
+	snapshots, err := ListBackupSnapshots(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || len(snapshots) != 0 {
+		t.Errorf("Expected no snapshots, got %+v (%v)", snapshots, err)
+	}
+}
+
+func TestBackupSnapshotDue(t *testing.T) {
+	srcDir := t.TempDir()
+	os.WriteFile(filepath.Join(srcDir, "plan.md"), []byte("# Plan"), 0644)
+	now := time.Now()
+
+	if !BackupSnapshotDue(srcDir, nil, now, time.Hour) {
+		t.Error("Expected the first snapshot to be due")
+	}
+
+	recent := []BackupSnapshot{{Time: now.Add(-10 * time.Minute)}}
+	if BackupSnapshotDue(srcDir, recent, now, time.Hour) {
+		t.Error("Expected no snapshot within the interval")
+	}
+
+	old := []BackupSnapshot{{Time: now.Add(-2 * time.Hour)}}
+	if !BackupSnapshotDue(srcDir, old, now, time.Hour) {
+		t.Error("Expected a snapshot once the notes changed after an old one")
+	}
+
+	unchanged := []BackupSnapshot{{Time: now.Add(time.Minute)}}
+	if BackupSnapshotDue(srcDir, unchanged, now.Add(2*time.Hour), time.Hour) {
+		t.Error("Expected no snapshot when nothing changed since the latest one")
+	}
+}
+
+func TestBackupsToKeep_HourlyDailyWeekly(t *testing.T) {
+	now := time.Date(2025, 11, 28, 15, 30, 0, 0, time.Local)
+	snapshot := func(age time.Duration) BackupSnapshot {
+		taken := now.Add(-age)
+		return BackupSnapshot{Path: taken.Format(backupTimeLayout), Time: taken}
+	}
+
+	snapshots := []BackupSnapshot{
+		snapshot(0),                   // Newest, always kept
+		snapshot(10 * time.Minute),    // Same hour as the newest
+		snapshot(time.Hour),           // Newest of the previous hour
+		snapshot(26 * time.Hour),      // Newest of yesterday
+		snapshot(27 * time.Hour),      // Older snapshot of yesterday
+		snapshot(10 * 24 * time.Hour), // Newest of an earlier week
+		snapshot(60 * 24 * time.Hour), // Beyond every period
+	}
+	keep := backupsToKeep(snapshots, BackupPolicy{KeepHourly: 2, KeepDaily: 2, KeepWeekly: 2})
+
+	expected := []bool{true, false, true, true, false, true, false}
+	for i, snapshot := range snapshots {
+		if keep[snapshot.Path] != expected[i] {
+			t.Errorf("Snapshot %d (%s): kept = %v, want %v", i, snapshot.Path, keep[snapshot.Path], expected[i])
+		}
+	}
+}
+
+func TestPruneBackupSnapshots_DeletesUnkeptSnapshots(t *testing.T) {
+	srcDir := t.TempDir()
+	backupDir := t.TempDir()
+	os.WriteFile(filepath.Join(srcDir, "plan.md"), []byte("# Plan"), 0644)
+
+	now := time.Now()
+	for _, age := range []time.Duration{0, time.Minute, 2 * time.Minute} {
+		if _, err := CreateBackupSnapshot(srcDir, backupDir, now.Add(-age)); err != nil {
+			t.Fatalf("CreateBackupSnapshot failed: %v", err)
+		}
+	}
+
+	pruned, err := PruneBackupSnapshots(backupDir, BackupPolicy{KeepHourly: 1})
+	if err != nil {
+		t.Fatalf("PruneBackupSnapshots failed: %v", err)
+	}
+	snapshots, _ := ListBackupSnapshots(backupDir)
+	if len(pruned) != 2 || len(snapshots) != 1 || !snapshots[0].Time.Equal(now.Truncate(time.Second)) {
+		t.Errorf("Expected only the newest snapshot left, pruned %+v, left %+v", pruned, snapshots)
+	}
+}
+
+func TestRestoreBackup_NoteAndVault(t *testing.T) {
+	notesDir := t.TempDir()
+	os.WriteFile(filepath.Join(notesDir, "plan.md"), []byte("# Plan edited"), 0644)
+	os.WriteFile(filepath.Join(notesDir, "new.md"), []byte("# New"), 0644)
+	os.WriteFile(filepath.Join(notesDir, ".gitignore"), []byte("*\n"), 0644)
+	notes := map[string]string{"plan.md": "# Plan", "gone.md": "# Gone"}
+
+	if err := RestoreBackupNote(notes, notesDir, "gone.md"); err != nil {
+		t.Fatalf("RestoreBackupNote failed: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(notesDir, "gone.md")); string(content) != "# Gone" {
+		t.Errorf("Expected gone.md restored, got %q", content)
+	}
+	if err := RestoreBackupNote(notes, notesDir, "missing.md"); err == nil {
+		t.Error("Expected an error for a note not in the backup")
+	}
+	if err := RestoreBackupNote(map[string]string{"../escape.md": "x"}, notesDir, "../escape.md"); err == nil {
+		t.Error("Expected an error for a path outside the notes")
+	}
+
+	restored, removed, err := RestoreBackupVault(notes, notesDir)
+	if err != nil {
+		t.Fatalf("RestoreBackupVault failed: %v", err)
+	}
+	if restored != 2 || removed != 1 {
+		t.Errorf("Expected 2 notes restored and 1 removed, got %d and %d", restored, removed)
+	}
+	if content, _ := os.ReadFile(filepath.Join(notesDir, "plan.md")); string(content) != "# Plan" {
+		t.Errorf("Expected plan.md restored, got %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(notesDir, "new.md")); !os.IsNotExist(err) {
+		t.Error("Expected new.md, which is not in the backup, to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(notesDir, ".gitignore")); err != nil {
+		t.Error("Expected files other than notes to be left alone")
+	}
+}
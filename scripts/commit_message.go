@@ -0,0 +1,149 @@
+package scripts
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// NoteChange is a note added, modified, renamed or deleted by a commit, with its content before and after
+type NoteChange struct {
+	Status  byte   // 'A', 'M', 'R' or 'D', as in git diff --name-status
+	Path    string // Path after the change
+	OldPath string // Path before a rename, otherwise the same as Path
+	Before  string // Content before the change, empty when added
+	After   string // Content after the change, empty when deleted
+}
+
+// Kinds of note changes described in commit messages
+const (
+	changeDone     = "done"
+	changeReopened = "reopened"
+	changeDueMoved = "due moved"
+	changePriority = "priority"
+	changeCreated  = "created"
+	changeRenamed  = "renamed"
+	changeDeleted  = "deleted"
+	changeEdited   = "edited"
+)
+
+// maxChangeNames is how many notes are named per kind before the rest are only counted
+const maxChangeNames = 3
+
+// changeKinds lists the kinds of note changes in message order
+var changeKinds = []string{changeDone, changeReopened, changeDueMoved, changePriority, changeCreated, changeRenamed, changeDeleted, changeEdited}
+
+// DescribeNoteChanges summarises note changes for a commit message, grouped by kind, e.g.
+// "done: Fix login bug; due moved: API review → Fri; created: standup-2025-11-28"
+// Due dates within the coming week are written as weekdays. Returns "" when no notes changed
+func DescribeNoteChanges(changes []NoteChange, now time.Time) string {
+	described := make(map[string][]string)
+	for _, change := range changes {
+		if !strings.HasSuffix(change.Path, ".md") {
+			continue
+		}
+		for kind, names := range describeNoteChange(change, now) {
+			described[kind] = append(described[kind], names...)
+		}
+	}
+
+	parts := make([]string, 0, len(described))
+	for _, kind := range changeKinds {
+		names := described[kind]
+		if len(names) == 0 {
+			continue
+		}
+		if len(names) > maxChangeNames {
+			names = append(names[:maxChangeNames:maxChangeNames], fmt.Sprintf("+%d more", len(names)-maxChangeNames))
+		}
+		parts = append(parts, kind+": "+strings.Join(names, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// describeNoteChange describes a single note change by kind
+// A modified note is only described as edited when none of its tracked fields changed
+func describeNoteChange(change NoteChange, now time.Time) map[string][]string {
+	name := strings.TrimSuffix(filepath.Base(change.Path), ".md")
+
+	switch change.Status {
+	case 'A':
+		return map[string][]string{changeCreated: {name}}
+	case 'D':
+		return map[string][]string{changeDeleted: {noteTitle(change.Before, name)}}
+	}
+
+	described := make(map[string][]string)
+	before := commitFrontmatter(change.Before)
+	after := commitFrontmatter(change.After)
+	title := noteTitle(change.After, name)
+
+	if change.Status == 'R' && change.OldPath != change.Path {
+		oldName := strings.TrimSuffix(filepath.Base(change.OldPath), ".md")
+		described[changeRenamed] = []string{oldName + " → " + name}
+	}
+
+	if before["done"] != "true" && after["done"] == "true" {
+		described[changeDone] = []string{title}
+	} else if before["done"] == "true" && after["done"] != "true" {
+		described[changeReopened] = []string{title}
+	}
+
+	if before["date-due"] != after["date-due"] {
+		described[changeDueMoved] = []string{title + " → " + describeDueDate(after["date-due"], now)}
+	}
+
+	if before["priority"] != after["priority"] && after["priority"] != "" {
+		described[changePriority] = []string{title + " → P" + after["priority"]}
+	}
+
+	if len(described) == 0 && change.Before != change.After {
+		described[changeEdited] = []string{title}
+	}
+	return described
+}
+
+// describeDueDate writes a due date as a weekday when it falls within the coming week
+func describeDueDate(value string, now time.Time) string {
+	if value == "" {
+		return "none"
+	}
+	due, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return value
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if days := int(due.Sub(today).Hours() / 24); days >= 0 && days < 7 {
+		return due.Format("Mon")
+	}
+	return value
+}
+
+// noteTitle returns the title in a note's frontmatter, or the fallback when it has none
+func noteTitle(content, fallback string) string {
+	if title := commitFrontmatter(content)["title"]; title != "" {
+		return title
+	}
+	return fallback
+}
+
+// commitFrontmatter reads the key: value lines between the leading --- markers of a note
+func commitFrontmatter(content string) map[string]string {
+	values := make(map[string]string)
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return values
+	}
+
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "---" {
+			break
+		}
+		if parts := strings.SplitN(line, ":", 2); len(parts) == 2 {
+			values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return values
+}
@@ -0,0 +1,81 @@
+package scripts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDescribeNoteChanges(t *testing.T) {
+	// Friday 2025-11-28
+	now := time.Date(2025, 11, 28, 10, 0, 0, 0, time.Local)
+	todo := func(title, done, due, priority string) string {
+		return "---\ntitle: " + title + "\ndone: " + done + "\ndate-due: " + due + "\npriority: " + priority + "\n---\n\nBody\n"
+	}
+
+	tests := []struct {
+		name     string
+		changes  []NoteChange
+		expected string
+	}{
+		{
+			name: "done, due moved and created",
+			changes: []NoteChange{
+				{Status: 'M', Path: "fix-login.md", OldPath: "fix-login.md", Before: todo("Fix login bug", "false", "2025-11-27", "1"), After: todo("Fix login bug", "true", "2025-11-27", "1")},
+				{Status: 'M', Path: "api-review.md", OldPath: "api-review.md", Before: todo("API review", "false", "2025-11-27", "2"), After: todo("API review", "false", "2025-11-28", "2")},
+				{Status: 'A', Path: "standup-2025-11-28.md", OldPath: "standup-2025-11-28.md", After: "---\ntitle: Standup\n---\n"},
+			},
+			expected: "done: Fix login bug; due moved: API review → Fri; created: standup-2025-11-28",
+		},
+		{
+			name: "due date beyond the coming week",
+			changes: []NoteChange{
+				{Status: 'M', Path: "a.md", OldPath: "a.md", Before: todo("A", "false", "2025-11-27", "2"), After: todo("A", "false", "2025-12-05", "2")},
+			},
+			expected: "due moved: A → 2025-12-05",
+		},
+		{
+			name: "reopened, priority and body edit",
+			changes: []NoteChange{
+				{Status: 'M', Path: "a.md", OldPath: "a.md", Before: todo("A", "true", "", "2"), After: todo("A", "false", "", "2")},
+				{Status: 'M', Path: "b.md", OldPath: "b.md", Before: todo("B", "false", "", "3"), After: todo("B", "false", "", "1")},
+				{Status: 'M', Path: "c.md", OldPath: "c.md", Before: todo("C", "false", "", "2"), After: todo("C", "false", "", "2") + "More\n"},
+			},
+			expected: "reopened: A; priority: B → P1; edited: C",
+		},
+		{
+			name: "renamed and deleted",
+			changes: []NoteChange{
+				{Status: 'R', Path: "new.md", OldPath: "old.md", Before: "x", After: "x"},
+				{Status: 'D', Path: "gone.md", OldPath: "gone.md", Before: todo("Gone", "false", "", "2")},
+			},
+			expected: "renamed: old → new; deleted: Gone",
+		},
+		{
+			name: "more notes than named",
+			changes: []NoteChange{
+				{Status: 'A', Path: "a.md", OldPath: "a.md"},
+				{Status: 'A', Path: "b.md", OldPath: "b.md"},
+				{Status: 'A', Path: "c.md", OldPath: "c.md"},
+				{Status: 'A', Path: "d.md", OldPath: "d.md"},
+				{Status: 'A', Path: "e.md", OldPath: "e.md"},
+			},
+			expected: "created: a, b, c, +2 more",
+		},
+		{
+			name: "non-note files are ignored",
+			changes: []NoteChange{
+				{Status: 'M', Path: ".gitignore", OldPath: ".gitignore", Before: "*\n", After: "*\n!*.md\n"},
+			},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DescribeNoteChanges(tt.changes, now)
+			if got != tt.expected {
+				t.Errorf("DescribeNoteChanges() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
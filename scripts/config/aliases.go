@@ -0,0 +1,13 @@
+package config
+
+// COMMAND_ALIASES maps a shortcut to the command text it stands for
+// Any arguments given to the alias are appended to the expansion
+var COMMAND_ALIASES = map[string]string{
+	"wk": "wp",
+}
+
+// COMMAND_MACROS maps a name to a sequence of commands run in order
+// $1, $2, ... are replaced by the macro arguments and $* by all of them
+var COMMAND_MACROS = map[string][]string{
+	"prep": {"cm 1-1-$1", "tt $1"},
+}
@@ -0,0 +1,25 @@
+package config
+
+// BACKUP_SNAPSHOT_INTERVAL_MINUTES is how often a compressed snapshot of the notes is taken, when they changed
+// A snapshot is also taken on exit
+const BACKUP_SNAPSHOT_INTERVAL_MINUTES = 60
+
+// The newest snapshot of each of the last BACKUP_KEEP_HOURLY hours, BACKUP_KEEP_DAILY days
+// and BACKUP_KEEP_WEEKLY weeks is kept, older snapshots are deleted
+const (
+	BACKUP_KEEP_HOURLY = 24
+	BACKUP_KEEP_DAILY  = 7
+	BACKUP_KEEP_WEEKLY = 8
+)
+
+// A mirror refuses to delete more than MAX_MIRRORED_DELETE_PERCENT of its notes at once,
+// so an accidental mass delete doesn't reach the backup. Deleting up to
+// ALWAYS_MIRRORED_DELETES notes is always mirrored, so small vaults can still delete notes
+const (
+	MAX_MIRRORED_DELETE_PERCENT = 20
+	ALWAYS_MIRRORED_DELETES     = 3
+)
+
+// BACKUP_EXTRA_MIRRORS are directories the notes are mirrored to besides ~/Documents/notes,
+// such as a mounted drive or a synced folder; a leading ~ stands for the home directory
+var BACKUP_EXTRA_MIRRORS = []string{}
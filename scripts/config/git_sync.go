@@ -0,0 +1,11 @@
+package config
+
+// GIT_SYNC_REMOTE is the remote of the notes repository to sync with
+// Sync stays off until the remote is added, e.g. git -C notes remote add origin <url>
+const GIT_SYNC_REMOTE = "origin"
+
+// GIT_SYNC_INTERVAL_SECONDS is how often the notes are synced with the remote, 0 only syncs on exit
+const GIT_SYNC_INTERVAL_SECONDS = 300
+
+// GIT_SYNC_REBASE rebases local commits onto the remote ones; false merges the remote instead
+const GIT_SYNC_REBASE = true
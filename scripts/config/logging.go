@@ -0,0 +1,4 @@
+package config
+
+// LOG_LEVEL is the lowest level written to the log in notes/.cli-notes: debug, info, warn or error
+const LOG_LEVEL = "info"
@@ -0,0 +1,9 @@
+package config
+
+// SHUTDOWN_TIMEOUT_SECONDS is how long the final backup, commit and remote sync may take on exit
+// before the program gives up on them and exits anyway
+const SHUTDOWN_TIMEOUT_SECONDS = 20
+
+// SHUTDOWN_ABORT_SECONDS is how long a final remote sync stopped by the timeout or another signal
+// may take to abort the rebase or merge it is in the middle of
+const SHUTDOWN_ABORT_SECONDS = 5
@@ -0,0 +1,138 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Draft files in the state directory, one per view with unsaved work
+const (
+	WeekPlanDraftName   = "week-plan-draft.json"
+	TalkToDraftName     = "talk-to-draft.json"
+	ObjectivesDraftName = "objectives-draft.json"
+)
+
+// DraftNames lists every view draft, in the order a launch offers to resume them
+var DraftNames = []string{WeekPlanDraftName, TalkToDraftName, ObjectivesDraftName}
+
+// DraftJournal keeps the draft of an open view in the state directory in step with the view,
+// so a crash or a closed terminal doesn't lose its unsaved work
+type DraftJournal struct {
+	name    string
+	written []byte // Draft last written, nil when none is on disk
+}
+
+// NewDraftJournal returns the journal of one of the DraftNames
+func NewDraftJournal(name string) *DraftJournal {
+	return &DraftJournal{name: name}
+}
+
+// Record writes the draft, unless it is the same as the one last written
+func (j *DraftJournal) Record(draft any) error {
+	content, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %w", j.name, err)
+	}
+	if j.written != nil && bytes.Equal(content, j.written) {
+		return nil
+	}
+
+	if err := writeDraft(j.name, content); err != nil {
+		return err
+	}
+	j.written = content
+	return nil
+}
+
+// Discard removes the draft once the view has nothing unsaved
+func (j *DraftJournal) Discard() error {
+	if j.written == nil {
+		return nil
+	}
+	if err := DiscardDraft(j.name); err != nil {
+		return err
+	}
+	j.written = nil
+	return nil
+}
+
+// DraftPath returns the path of a draft in the state directory
+func DraftPath(name string) (string, error) {
+	stateDir, err := StateDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, name), nil
+}
+
+// PendingDrafts returns the paths of the drafts left in the state directory
+func PendingDrafts() []string {
+	pending := make([]string, 0)
+	for _, name := range DraftNames {
+		path, err := DraftPath(name)
+		if err != nil {
+			return pending
+		}
+		if _, err := os.Stat(path); err == nil {
+			pending = append(pending, path)
+		}
+	}
+	return pending
+}
+
+// DiscardDraft removes a draft, which may not exist
+func DiscardDraft(name string) error {
+	path, err := DraftPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeDraft replaces a draft through a temporary file, so a crash mid-write leaves the previous one
+func writeDraft(name string, content []byte) error {
+	path, err := DraftPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating state directory: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, content, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// readDraft decodes a draft and returns when it was last written, an os.IsNotExist error when there is none
+func readDraft(name string, draft any) (time.Time, error) {
+	path, err := DraftPath(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := json.Unmarshal(content, draft); err != nil {
+		return time.Time{}, fmt.Errorf("error reading %s: %w", name, err)
+	}
+	return info.ModTime(), nil
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDraftJournal_RecordsChangesAndDiscards(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	if _, err := LoadWeekPlanDraft(); !os.IsNotExist(err) {
+		t.Fatalf("Expected no draft yet, got %v", err)
+	}
+
+	journal := NewDraftJournal(WeekPlanDraftName)
+	draft := &WeekPlanDraft{
+		WeekStart: time.Date(2025, 11, 24, 0, 0, 0, 0, time.Local),
+		Moves:     []WeekPlanDraftMove{{Name: "moved.md", Title: "Moved", From: "2025-11-24", To: "2025-11-26"}},
+	}
+	if err := journal.Record(draft); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	loaded, err := LoadWeekPlanDraft()
+	if err != nil {
+		t.Fatalf("LoadWeekPlanDraft failed: %v", err)
+	}
+	if !loaded.WeekStart.Equal(draft.WeekStart) || len(loaded.Moves) != 1 || loaded.Moves[0] != draft.Moves[0] {
+		t.Errorf("Expected %+v, got %+v", draft, loaded)
+	}
+	if loaded.SavedAt.IsZero() {
+		t.Error("Expected the draft to know when it was written")
+	}
+
+	// An unchanged draft isn't written again
+	path, _ := DraftPath(WeekPlanDraftName)
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(path, past, past)
+	if err := journal.Record(&WeekPlanDraft{WeekStart: draft.WeekStart, Moves: draft.Moves}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if info, _ := os.Stat(path); !info.ModTime().Equal(past) {
+		t.Error("Expected an unchanged draft not to be written again")
+	}
+
+	if pending := PendingDrafts(); len(pending) != 1 || pending[0] != path {
+		t.Errorf("Expected the draft to be pending, got %v", pending)
+	}
+	if err := journal.Discard(); err != nil {
+		t.Fatalf("Discard failed: %v", err)
+	}
+	if pending := PendingDrafts(); len(pending) != 0 {
+		t.Errorf("Expected no pending drafts after discarding, got %v", pending)
+	}
+}
+
+func TestDraftJournal_DiscardLeavesDraftsItDidNotWrite(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	// A draft kept from an earlier session
+	if err := NewDraftJournal(ObjectivesDraftName).Record(&ObjectivesDraft{}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	if err := NewDraftJournal(ObjectivesDraftName).Discard(); err != nil {
+		t.Fatalf("Discard failed: %v", err)
+	}
+	if _, err := LoadObjectivesDraft(); err != nil {
+		t.Errorf("Expected the earlier draft to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package data
+
+import (
+	"cli-notes/scripts"
+)
+
+// NewEmbedResolver resolves embeds against the given notes, the same way links resolve
+// An embed matching several notes with the same title uses the first by filename
+func NewEmbedResolver(files []scripts.File) scripts.EmbedResolver {
+	index := newLinkIndex()
+	for _, file := range files {
+		index.addFile(file)
+	}
+	return index.resolveEmbed
+}
+
+// ExpandEmbedsInFiles returns the notes with their embeds expanded, for exports
+func ExpandEmbedsInFiles(files []scripts.File) ([]scripts.File, error) {
+	var index *LinkIndex
+	expanded := make([]scripts.File, 0, len(files))
+
+	for _, file := range files {
+		if scripts.HasEmbeds(file.Content) {
+			if index == nil {
+				var err error
+				if index, err = loadLinkIndexFiles(); err != nil {
+					return nil, err
+				}
+			}
+			file.Content = scripts.ExpandEmbeds(file, index.resolveEmbed)
+		}
+		expanded = append(expanded, file)
+	}
+
+	return expanded, nil
+}
+
+// resolveEmbed returns the note an embed points at
+func (idx *LinkIndex) resolveEmbed(linkText string) (scripts.File, bool) {
+	fileName, exists := idx.Resolve(linkText)
+	if !exists {
+		return scripts.File{}, false
+	}
+	return idx.FilesByName[fileName], true
+}
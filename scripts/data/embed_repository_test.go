@@ -0,0 +1,79 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandEmbedsInFiles_ExpandsFromNotesOnDisk(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "payments.md", Title: "Payments", CreatedAt: time.Now(), Content: "Payments work"})
+	createTestFile(t, scripts.File{Name: "report.md", Title: "Report", CreatedAt: time.Now(), Content: "![[Payments]]"})
+
+	report, err := LoadFileByName("report.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+	plain := scripts.File{Name: "plain.md", Content: "No embeds"}
+
+	files, err := ExpandEmbedsInFiles([]scripts.File{report, plain})
+	if err != nil {
+		t.Fatalf("ExpandEmbedsInFiles failed: %v", err)
+	}
+
+	if !strings.Contains(files[0].Content, "Payments work") || strings.Contains(files[0].Content, "![[") {
+		t.Errorf("Expected the embed to be expanded, got '%s'", files[0].Content)
+	}
+	if files[1].Content != "No embeds" {
+		t.Errorf("Expected a note without embeds to be unchanged, got '%s'", files[1].Content)
+	}
+}
+
+func TestNewEmbedResolver_ResolvesByTitleAndID(t *testing.T) {
+	resolve := NewEmbedResolver([]scripts.File{
+		{Name: "meeting-2025-01-01.md", Title: "Meeting", ID: "1a2b3c4d"},
+	})
+
+	if file, found := resolve("meeting#Plan"); !found || file.Name != "meeting-2025-01-01.md" {
+		t.Errorf("Expected to resolve by title, got %v %v", file.Name, found)
+	}
+	if file, found := resolve("id:1A2B3C4D|Old title"); !found || file.Name != "meeting-2025-01-01.md" {
+		t.Errorf("Expected to resolve by ID, got %v %v", file.Name, found)
+	}
+	if _, found := resolve("Missing"); found {
+		t.Error("Expected a missing note not to resolve")
+	}
+}
+
+func TestObjectivesViewState_ExpandEmbedsLoadsNotesUntilRefresh(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "payments.md", Title: "Payments", CreatedAt: time.Now(), Content: "Payments work"})
+	report := scripts.File{Name: "report.md", Content: "![[Payments]]"}
+
+	state, err := NewObjectivesViewState()
+	if err != nil {
+		t.Fatalf("NewObjectivesViewState failed: %v", err)
+	}
+	if content := state.ExpandEmbeds(report); !strings.Contains(content, "Payments work") {
+		t.Fatalf("Expected the embed to be expanded, got '%s'", content)
+	}
+
+	// Renders between refreshes reuse the notes already loaded
+	createTestFile(t, scripts.File{Name: "payments.md", Title: "Payments", CreatedAt: time.Now(), Content: "Payments done"})
+	if content := state.ExpandEmbeds(report); !strings.Contains(content, "Payments work") {
+		t.Errorf("Expected the loaded notes to be reused, got '%s'", content)
+	}
+
+	if err := state.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if content := state.ExpandEmbeds(report); !strings.Contains(content, "Payments done") {
+		t.Errorf("Expected Refresh to load the notes again, got '%s'", content)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"cli-notes/scripts"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -20,6 +21,140 @@ type metaData struct {
 }
 
 func WriteFile(newFile scripts.File) error {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	notesPath := filepath.Join(currentDir, DirectoryPath)
+	filePath := filepath.Join(notesPath, newFile.Name)
+
+	newFile, err = assignNoteID(newFile, filePath, newNoteIDSet(notesPath))
+	if err != nil {
+		return fmt.Errorf("error assigning note ID: %w", err)
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(formatFile(newFile))
+	if err != nil {
+		return fmt.Errorf("error writing file content: %w", err)
+	}
+
+	return nil
+}
+
+// WriteFilesAtomically writes several files so that either all or none of them change
+// Every file is first written to a temporary file next to it, and only when all
+// temporary files are complete are they renamed over the originals. If replacing one
+// fails, the files already replaced get their original content back
+func WriteFilesAtomically(files []scripts.File) error {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	notesPath := filepath.Join(currentDir, DirectoryPath)
+	tempPaths := make([]string, 0, len(files))
+	ids := newNoteIDSet(notesPath)
+
+	removeTempFiles := func() {
+		for _, tempPath := range tempPaths {
+			os.Remove(tempPath)
+		}
+	}
+
+	for _, file := range files {
+		file, err := assignNoteID(file, filepath.Join(notesPath, file.Name), ids)
+		if err != nil {
+			removeTempFiles()
+			return err
+		}
+
+		temp, err := os.CreateTemp(notesPath, "."+file.Name+".tmp-*")
+		if err != nil {
+			removeTempFiles()
+			return fmt.Errorf("failed to create temporary file for %s: %w", file.Name, err)
+		}
+		tempPaths = append(tempPaths, temp.Name())
+
+		// Temporary files are private by default, match the mode of os.Create
+		err = temp.Chmod(0644)
+		if err == nil {
+			_, err = temp.WriteString(formatFile(file))
+		}
+		if closeErr := temp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			removeTempFiles()
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+	}
+
+	originals := make([]originalFile, 0, len(files))
+	for i, file := range files {
+		path := filepath.Join(notesPath, file.Name)
+		original, err := readOriginalFile(path)
+		if err == nil {
+			err = os.Rename(tempPaths[i], path)
+		}
+		if err != nil {
+			removeTempFiles()
+			if unrestored := restoreOriginalFiles(originals); len(unrestored) > 0 {
+				return fmt.Errorf("failed to replace %s: %w; %s could not be restored", file.Name, err, strings.Join(unrestored, ", "))
+			}
+			return fmt.Errorf("failed to replace %s: %w", file.Name, err)
+		}
+		originals = append(originals, original)
+	}
+
+	return nil
+}
+
+// originalFile is the content a file had before WriteFilesAtomically replaced it
+type originalFile struct {
+	path    string
+	content []byte
+	existed bool
+}
+
+func readOriginalFile(path string) (originalFile, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return originalFile{path: path}, nil
+	}
+	if err != nil {
+		return originalFile{}, err
+	}
+	return originalFile{path: path, content: content, existed: true}, nil
+}
+
+// restoreOriginalFiles puts back the files replaced so far, removing the ones that were created
+// Returns the names of the files that couldn't be restored
+func restoreOriginalFiles(originals []originalFile) []string {
+	unrestored := make([]string, 0)
+	for i := len(originals) - 1; i >= 0; i-- {
+		original := originals[i]
+		var err error
+		if original.existed {
+			err = os.WriteFile(original.path, original.content, 0644)
+		} else {
+			err = os.Remove(original.path)
+		}
+		if err != nil {
+			unrestored = append(unrestored, filepath.Base(original.path))
+		}
+	}
+	return unrestored
+}
+
+// formatFile renders the frontmatter and content of a note
+func formatFile(newFile scripts.File) string {
 	meta := []metaData{
 		{
 			Key:   "title",
@@ -31,7 +166,7 @@ func WriteFile(newFile scripts.File) error {
 		},
 		{
 			Key:   "tags",
-			Value: fmt.Sprintf("%v", newFile.Tags),
+			Value: fmt.Sprintf("%v", scripts.NormalizeTags(newFile.Tags)),
 		},
 		{
 			Key:   "priority",
@@ -60,54 +195,25 @@ func WriteFile(newFile scripts.File) error {
 			Value: newFile.ObjectiveID,
 		})
 	}
-
-	currentDir, err := os.Getwd()
-	if err != nil {
-		fmt.Println("Error getting current directory path:", err)
-		return err
-	}
-
-	notesPath := filepath.Join(currentDir, DirectoryPath)
-	filePath := filepath.Join(notesPath, newFile.Name)
-
-	file, err := os.Create(filePath)
-	if err != nil {
-		fmt.Println("Error creating file:", err)
-		return err
-	}
-	defer file.Close()
-
-	_, err = file.WriteString("---\n")
-	if err != nil {
-		fmt.Println("Error writing top of meta tags:", err)
-		return err
+	if newFile.ID != "" {
+		meta = append(meta, metaData{
+			Key:   "id",
+			Value: newFile.ID,
+		})
 	}
 
+	var sb strings.Builder
+	sb.WriteString("---\n")
 	for _, m := range meta {
-		_, err = fmt.Fprintf(file, "%s: %s\n", m.Key, m.Value)
-		if err != nil {
-			fmt.Println("Error writing meta data:", err)
-			return err
-		}
-	}
-
-	_, err = file.WriteString("---\n\n")
-	if err != nil {
-		fmt.Println("Error writing bottom of meta tags:", err)
-		return err
+		sb.WriteString(fmt.Sprintf("%s: %s\n", m.Key, m.Value))
 	}
+	sb.WriteString("---\n\n")
 
 	// Trim leading newlines to prevent accumulating extra lines
 	// when files are read and written multiple times
-	content := strings.TrimLeft(newFile.Content, "\n")
-
-	_, err = file.WriteString(content)
-	if err != nil {
-		fmt.Println("Error writing file content:", err)
-		return err
-	}
+	sb.WriteString(strings.TrimLeft(newFile.Content, "\n"))
 
-	return nil
+	return sb.String()
 }
 
 func QueryFilesByDone(isDone bool) ([]scripts.File, error) {
@@ -121,6 +227,22 @@ func QueryFiles(query string) ([]scripts.File, error) {
 	return queryAllFiles(query)
 }
 
+// QueryConflictedNotes returns the notes a remote sync left with conflict markers
+func QueryConflictedNotes() ([]scripts.File, error) {
+	files, err := queryAllFiles(scripts.ConflictMarkerLocal)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicted := make([]scripts.File, 0, len(files))
+	for _, file := range files {
+		if scripts.HasConflictMarkers(file.Content) {
+			conflicted = append(conflicted, file)
+		}
+	}
+	return conflicted, nil
+}
+
 // LoadFileByName loads a single file by its filename
 func LoadFileByName(fileName string) (scripts.File, error) {
 	currentDir, err := os.Getwd()
@@ -175,15 +297,7 @@ func LoadFileByName(fileName string) (scripts.File, error) {
 			case "title":
 				result.Title = value
 			case "tags":
-				// Remove brackets and split by comma
-				value = strings.Trim(value, "[]")
-				if value != "" {
-					tags := strings.Split(value, ",")
-					for i, tag := range tags {
-						tags[i] = strings.TrimSpace(tag)
-					}
-					result.Tags = tags
-				}
+				result.Tags = parseTagsLine(value)
 			case "date-created":
 				result.CreatedAt, _ = time.Parse(dateFormat, value)
 			case "date-due":
@@ -208,6 +322,8 @@ func LoadFileByName(fileName string) (scripts.File, error) {
 				result.ObjectiveRole = value
 			case "objective-id":
 				result.ObjectiveID = value
+			case "id":
+				result.ID = value
 			}
 		} else {
 			// Append to content
@@ -227,8 +343,7 @@ func LoadFileByName(fileName string) (scripts.File, error) {
 func QueryTodosWithDateCriteria(dateCheck func(dueDate string, dueDateParsed time.Time) bool) ([]scripts.File, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
-		fmt.Println("Error getting current directory path:", err)
-		return nil, err
+		return nil, fmt.Errorf("error getting current directory: %w", err)
 	}
 
 	notesPath := filepath.Join(currentDir, DirectoryPath)
@@ -249,51 +364,17 @@ func QueryTodosWithDateCriteria(dateCheck func(dueDate string, dueDateParsed tim
 		}
 		defer file.Close()
 
-		scanner := bufio.NewScanner(file)
-		inMetadata := false
-		isATodo := false
-		dueDate := ""
-
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			if line == "---" {
-				if !inMetadata {
-					inMetadata = true
-					continue
-				} else {
-					break // End of metadata - stop reading the file
-				}
-			}
-
-			if inMetadata {
-				if strings.HasPrefix(line, "done:") {
-					value := strings.TrimSpace(strings.TrimPrefix(line, "done:"))
-					isATodo = (value == "false")
-				}
-				if strings.HasPrefix(line, "date-due:") {
-					dueDate = strings.TrimSpace(strings.TrimPrefix(line, "date-due:"))
-				}
-			}
-		}
-
-		if err := scanner.Err(); err != nil {
+		matches, err := todoMatchesDateCriteria(file, dateCheck)
+		if err != nil {
 			return err
 		}
 
-		if isATodo && dueDate != "" {
-			dueDateParsed, err := time.Parse(dateFormat, dueDate)
+		if matches {
+			matchingFile, err := getFileIfQueryMatches(path, "date-due:")
 			if err != nil {
 				return err
 			}
-
-			if dateCheck(dueDate, dueDateParsed) {
-				matchingFile, err := getFileIfQueryMatches(path, "date-due:")
-				if err != nil {
-					return err
-				}
-				matchingFiles = append(matchingFiles, *matchingFile)
-			}
+			matchingFiles = append(matchingFiles, *matchingFile)
 		}
 		return nil
 	})
@@ -305,6 +386,51 @@ func QueryTodosWithDateCriteria(dateCheck func(dueDate string, dueDateParsed tim
 	return matchingFiles, err
 }
 
+// todoMatchesDateCriteria reads the frontmatter of a note and checks it is an open todo whose due date matches
+func todoMatchesDateCriteria(reader io.Reader, dateCheck func(dueDate string, dueDateParsed time.Time) bool) (bool, error) {
+	scanner := bufio.NewScanner(reader)
+	inMetadata := false
+	isATodo := false
+	dueDate := ""
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "---" {
+			if !inMetadata {
+				inMetadata = true
+				continue
+			} else {
+				break // End of metadata - stop reading the file
+			}
+		}
+
+		if inMetadata {
+			if strings.HasPrefix(line, "done:") {
+				value := strings.TrimSpace(strings.TrimPrefix(line, "done:"))
+				isATodo = (value == "false")
+			}
+			if strings.HasPrefix(line, "date-due:") {
+				dueDate = strings.TrimSpace(strings.TrimPrefix(line, "date-due:"))
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+
+	if !isATodo || dueDate == "" {
+		return false, nil
+	}
+
+	dueDateParsed, err := time.Parse(dateFormat, dueDate)
+	if err != nil {
+		return false, err
+	}
+	return dateCheck(dueDate, dueDateParsed), nil
+}
+
 func QueryNotesByTags(tags []string) ([]scripts.File, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
@@ -336,27 +462,12 @@ func QueryNotesByTags(tags []string) ([]scripts.File, error) {
 			line := scanner.Text()
 
 			if strings.HasPrefix(line, "tags:") {
-				tagsLine := strings.TrimPrefix(line, "tags:")
-				tagsLine = strings.TrimSpace(tagsLine)
-				tagsLine = strings.Trim(tagsLine, "[]")
-
-				// Changed: Split by spaces instead of commas, and handle both formats
-				var fileTags []string
-				if strings.Contains(tagsLine, ",") {
-					// Handle comma-separated format
-					parts := strings.Split(tagsLine, ",")
-					for _, p := range parts {
-						fileTags = append(fileTags, strings.TrimSpace(p))
-					}
-				} else {
-					// Handle space-separated format
-					fileTags = strings.Fields(tagsLine)
-				}
+				fileTags := parseTagsLine(strings.TrimPrefix(line, "tags:"))
 
-				// Check if all query tags are in the file tags
+				// Check if all query tags (or tags nested under them) are in the file tags
 				allTagsFound = true
 				for _, tag := range tags {
-					if !contains(fileTags, tag) {
+					if !scripts.HasMatchingTag(fileTags, tag) {
 						allTagsFound = false
 						break
 					}
@@ -393,8 +504,7 @@ func timeToString(time time.Time) string {
 func queryAllFiles(lineQuery string) ([]scripts.File, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
-		fmt.Println("Error getting current directory path:", err)
-		return nil, err
+		return nil, fmt.Errorf("error getting current directory: %w", err)
 	}
 
 	notesPath := filepath.Join(currentDir, DirectoryPath)
@@ -425,8 +535,7 @@ func queryAllFiles(lineQuery string) ([]scripts.File, error) {
 
 	// Check for any errors during directory traversal
 	if err != nil {
-		fmt.Println("Error walking through files:", err)
-		return nil, err
+		return nil, fmt.Errorf("error walking through files: %w", err)
 	}
 
 	return matchingFiles, err
@@ -439,11 +548,16 @@ func getFileIfQueryMatches(path, lineQuery string) (*scripts.File, error) {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return parseFileIfQueryMatches(filepath.Base(path), file, lineQuery)
+}
+
+// parseFileIfQueryMatches reads a note, returning nil when none of its lines contain the query
+func parseFileIfQueryMatches(name string, reader io.Reader, lineQuery string) (*scripts.File, error) {
+	scanner := bufio.NewScanner(reader)
 
 	// Initialize file struct
 	result := &scripts.File{
-		Name: filepath.Base(path),
+		Name: name,
 	}
 
 	// State tracking
@@ -484,15 +598,7 @@ func getFileIfQueryMatches(path, lineQuery string) (*scripts.File, error) {
 			case "title":
 				result.Title = value
 			case "tags":
-				// Remove brackets and split by comma
-				value = strings.Trim(value, "[]")
-				if value != "" {
-					tags := strings.Split(value, ",")
-					for i, tag := range tags {
-						tags[i] = strings.TrimSpace(tag)
-					}
-					result.Tags = tags
-				}
+				result.Tags = parseTagsLine(value)
 			case "date-created":
 				result.CreatedAt, _ = time.Parse(dateFormat, value)
 			case "date-due":
@@ -517,6 +623,8 @@ func getFileIfQueryMatches(path, lineQuery string) (*scripts.File, error) {
 				result.ObjectiveRole = value
 			case "objective-id":
 				result.ObjectiveID = value
+			case "id":
+				result.ID = value
 			}
 		} else {
 			// Append to content
@@ -572,8 +680,7 @@ func isDateRangeQueryNote(file *scripts.File) bool {
 func QueryCompletedTodosByDateRange(dateCheck func(dueDate string, dueDateParsed time.Time) bool) ([]scripts.File, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
-		fmt.Println("Error getting current directory path:", err)
-		return nil, err
+		return nil, fmt.Errorf("error getting current directory: %w", err)
 	}
 
 	notesPath := filepath.Join(currentDir, DirectoryPath)
@@ -137,6 +137,141 @@ func TestWriteFile(t *testing.T) {
 	}
 }
 
+func TestWriteFile_NormalizesTagsAndRoundTrips(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{
+		Name:      "tags.md",
+		Title:     "Tags",
+		CreatedAt: time.Now(),
+		Tags:      []string{" work ", "team sync", "work", ""},
+	})
+
+	loaded, err := LoadFileByName("tags.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+
+	expected := []string{"work", "team-sync"}
+	if len(loaded.Tags) != len(expected) {
+		t.Fatalf("Expected tags %v, got %v", expected, loaded.Tags)
+	}
+	for i := range expected {
+		if loaded.Tags[i] != expected[i] {
+			t.Errorf("Expected tags %v, got %v", expected, loaded.Tags)
+		}
+	}
+}
+
+func TestWriteFilesAtomically(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{
+		Name:      "existing.md",
+		Title:     "Existing",
+		CreatedAt: time.Now(),
+		Content:   "old content",
+	})
+
+	files := []scripts.File{
+		{Name: "existing.md", Title: "Existing", CreatedAt: time.Now(), Content: "new content"},
+		{Name: "created.md", Title: "Created", CreatedAt: time.Now(), Content: "created content"},
+	}
+
+	if err := WriteFilesAtomically(files); err != nil {
+		t.Fatalf("WriteFilesAtomically failed: %v", err)
+	}
+
+	for _, file := range files {
+		loaded, err := LoadFileByName(file.Name)
+		if err != nil {
+			t.Fatalf("LoadFileByName failed: %v", err)
+		}
+		if strings.TrimSpace(loaded.Content) != file.Content {
+			t.Errorf("Expected '%s', got '%s'", file.Content, loaded.Content)
+		}
+	}
+
+	// No temporary files are left behind
+	entries, _ := os.ReadDir(DirectoryPath)
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 files in notes, got %d", len(entries))
+	}
+}
+
+func TestWriteFilesAtomically_LeavesFilesUnchangedOnError(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{
+		Name:      "existing.md",
+		Title:     "Existing",
+		CreatedAt: time.Now(),
+		Content:   "old content",
+	})
+
+	files := []scripts.File{
+		{Name: "existing.md", Title: "Existing", CreatedAt: time.Now(), Content: "new content"},
+		{Name: "missing-dir/created.md", Title: "Created", CreatedAt: time.Now()},
+	}
+
+	if err := WriteFilesAtomically(files); err == nil {
+		t.Fatal("Expected an error for an unwritable file")
+	}
+
+	loaded, err := LoadFileByName("existing.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+	if strings.TrimSpace(loaded.Content) != "old content" {
+		t.Errorf("Expected existing file to be unchanged, got '%s'", loaded.Content)
+	}
+}
+
+func TestWriteFilesAtomically_RestoresReplacedFilesWhenAReplaceFails(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{
+		Name:      "existing.md",
+		Title:     "Existing",
+		CreatedAt: time.Now(),
+		Content:   "old content",
+	})
+	// A directory in the way of the last note makes replacing it fail after the others were replaced
+	if err := os.MkdirAll(filepath.Join(DirectoryPath, "blocked.md", "inside"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	files := []scripts.File{
+		{Name: "existing.md", Title: "Existing", CreatedAt: time.Now(), Content: "new content"},
+		{Name: "created.md", Title: "Created", CreatedAt: time.Now(), Content: "created content"},
+		{Name: "blocked.md", Title: "Blocked", CreatedAt: time.Now(), Content: "blocked content"},
+	}
+
+	if err := WriteFilesAtomically(files); err == nil {
+		t.Fatal("Expected an error for a note that can't be replaced")
+	}
+
+	loaded, err := LoadFileByName("existing.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+	if strings.TrimSpace(loaded.Content) != "old content" {
+		t.Errorf("Expected existing file to be restored, got '%s'", loaded.Content)
+	}
+	if _, err := os.Stat(filepath.Join(DirectoryPath, "created.md")); !os.IsNotExist(err) {
+		t.Errorf("Expected the created file to be removed, got err %v", err)
+	}
+
+	entries, _ := os.ReadDir(DirectoryPath)
+	if len(entries) != 2 {
+		t.Errorf("Expected only existing.md and the directory in notes, got %d entries", len(entries))
+	}
+}
+
 func TestQueryFilesByDone(t *testing.T) {
 	th := setupTest(t)
 	defer th.cleanup(t)
@@ -350,6 +485,50 @@ func TestQueryNotesByTags(t *testing.T) {
 	}
 }
 
+func TestQueryNotesByTags_MatchesNestedTags(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	now := time.Now()
+
+	createTestFile(t, scripts.File{
+		Name:      "android.md",
+		Title:     "Android",
+		CreatedAt: now,
+		Tags:      []string{"work/payments/android"},
+	})
+
+	createTestFile(t, scripts.File{
+		Name:      "hiring.md",
+		Title:     "Hiring",
+		CreatedAt: now,
+		Tags:      []string{"work/hiring"},
+	})
+
+	createTestFile(t, scripts.File{
+		Name:      "workshop.md",
+		Title:     "Workshop",
+		CreatedAt: now,
+		Tags:      []string{"workshop"},
+	})
+
+	workNotes, err := QueryNotesByTags([]string{"work"})
+	if err != nil {
+		t.Fatalf("QueryNotesByTags failed: %v", err)
+	}
+	if len(workNotes) != 2 {
+		t.Errorf("Expected 2 notes nested under work, got %d", len(workNotes))
+	}
+
+	paymentsNotes, err := QueryNotesByTags([]string{"work/payments"})
+	if err != nil {
+		t.Fatalf("QueryNotesByTags failed: %v", err)
+	}
+	if len(paymentsNotes) != 1 || paymentsNotes[0].Name != "android.md" {
+		t.Errorf("Expected only android.md under work/payments, got %v", paymentsNotes)
+	}
+}
+
 func TestContains(t *testing.T) {
 	testCases := []struct {
 		slice    []string
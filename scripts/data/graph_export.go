@@ -0,0 +1,167 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"sort"
+)
+
+// GraphExportOptions chooses which part of the note graph to export
+type GraphExportOptions struct {
+	Center string // Filename the export is centred on, empty for the whole vault
+	Depth  int    // Link and objective hops from the center to include, at least 1
+	Tags   bool   // Add a node for every tag, linked to the notes carrying it
+}
+
+// BuildGraphExport builds the note graph from the link index and objective parent/child pairs
+// Nodes and edges are in filename order so exports diff cleanly
+func BuildGraphExport(options GraphExportOptions) (scripts.GraphExport, error) {
+	index, err := BuildLinkIndex()
+	if err != nil {
+		return scripts.GraphExport{}, err
+	}
+
+	fileNames := make([]string, 0, len(index.FilesByName))
+	for fileName := range index.FilesByName {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	edges := append(linkEdges(index, fileNames), objectiveEdges(index, fileNames)...)
+
+	included := make(map[string]bool, len(fileNames))
+	if options.Center == "" {
+		for _, fileName := range fileNames {
+			included[fileName] = true
+		}
+	} else {
+		if _, exists := index.FilesByName[options.Center]; !exists {
+			if _, err := LoadFileByName(options.Center); err != nil {
+				return scripts.GraphExport{}, err
+			}
+		}
+		included = nearbyNodes(options.Center, options.Depth, edges)
+	}
+
+	graph := scripts.GraphExport{
+		Nodes: make([]scripts.GraphExportNode, 0),
+		Edges: make([]scripts.GraphExportEdge, 0),
+	}
+	for _, fileName := range fileNames {
+		if included[fileName] {
+			graph.Nodes = append(graph.Nodes, scripts.NewGraphExportNode(index.FilesByName[fileName]))
+		}
+	}
+	for _, edge := range edges {
+		if included[edge.From] && included[edge.To] {
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	if options.Tags {
+		addTagNodes(&graph, index)
+	}
+
+	return graph, nil
+}
+
+// linkEdges returns one edge per linked pair of notes, ignoring links from a note to itself
+func linkEdges(index *LinkIndex, fileNames []string) []scripts.GraphExportEdge {
+	edges := make([]scripts.GraphExportEdge, 0)
+	for _, fileName := range fileNames {
+		seen := make(map[string]bool)
+		for _, target := range index.OutLinks[fileName] {
+			if target == fileName || seen[target] {
+				continue
+			}
+			seen[target] = true
+			edges = append(edges, scripts.GraphExportEdge{From: fileName, To: target, Kind: scripts.GraphEdgeLink})
+		}
+	}
+	return edges
+}
+
+// objectiveEdges returns an edge from every parent objective to each of its children
+func objectiveEdges(index *LinkIndex, fileNames []string) []scripts.GraphExportEdge {
+	parents := make(map[string]string)
+	for _, fileName := range fileNames {
+		file := index.FilesByName[fileName]
+		if file.ObjectiveRole == "parent" && file.ObjectiveID != "" {
+			parents[file.ObjectiveID] = fileName
+		}
+	}
+
+	edges := make([]scripts.GraphExportEdge, 0)
+	for _, fileName := range fileNames {
+		file := index.FilesByName[fileName]
+		if file.ObjectiveRole == "parent" || file.ObjectiveID == "" {
+			continue
+		}
+		if parent, exists := parents[file.ObjectiveID]; exists {
+			edges = append(edges, scripts.GraphExportEdge{From: parent, To: fileName, Kind: scripts.GraphEdgeObjective})
+		}
+	}
+	return edges
+}
+
+// nearbyNodes returns the notes within depth hops of the center, following edges either way
+func nearbyNodes(center string, depth int, edges []scripts.GraphExportEdge) map[string]bool {
+	neighbours := make(map[string][]string)
+	for _, edge := range edges {
+		neighbours[edge.From] = append(neighbours[edge.From], edge.To)
+		neighbours[edge.To] = append(neighbours[edge.To], edge.From)
+	}
+
+	if depth < 1 {
+		depth = 1
+	}
+
+	visited := map[string]bool{center: true}
+	frontier := []string{center}
+	for distance := 1; distance <= depth && len(frontier) > 0; distance++ {
+		next := make([]string, 0)
+		for _, fileName := range frontier {
+			for _, neighbour := range neighbours[fileName] {
+				if !visited[neighbour] {
+					visited[neighbour] = true
+					next = append(next, neighbour)
+				}
+			}
+		}
+		frontier = next
+	}
+	return visited
+}
+
+// addTagNodes adds a node per tag on the exported notes and an edge from each note to its tags
+// Note type tags like "todo" are left out, the type is already a node attribute
+func addTagNodes(graph *scripts.GraphExport, index *LinkIndex) {
+	noteTypes := make(map[string]bool, len(scripts.NoteTypes))
+	for _, noteType := range scripts.NoteTypes {
+		noteTypes[noteType] = true
+	}
+
+	tags := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		for _, tag := range index.FilesByName[node.ID].Tags {
+			tag = scripts.NormalizeTag(tag)
+			if tag == "" || noteTypes[tag] {
+				continue
+			}
+			tags[tag] = true
+			graph.Edges = append(graph.Edges, scripts.GraphExportEdge{From: node.ID, To: scripts.TagNodeID(tag), Kind: scripts.GraphEdgeTag})
+		}
+	}
+
+	sortedTags := make([]string, 0, len(tags))
+	for tag := range tags {
+		sortedTags = append(sortedTags, tag)
+	}
+	sort.Strings(sortedTags)
+	for _, tag := range sortedTags {
+		graph.Nodes = append(graph.Nodes, scripts.GraphExportNode{
+			ID:    scripts.TagNodeID(tag),
+			Title: tag,
+			Type:  scripts.GraphTagType,
+		})
+	}
+}
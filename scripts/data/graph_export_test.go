@@ -0,0 +1,104 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"testing"
+	"time"
+)
+
+func createGraphExportNotes(t *testing.T) {
+	createTestFile(t, scripts.File{Name: "launch.md", Title: "Launch", CreatedAt: time.Now(), ObjectiveRole: "parent", ObjectiveID: "abcd1234", Content: "[[Spec]]"})
+	createTestFile(t, scripts.File{Name: "ship.md", Title: "Ship", Tags: []string{"todo", "work"}, CreatedAt: time.Now(), ObjectiveID: "abcd1234", Content: "ship it"})
+	createTestFile(t, scripts.File{Name: "spec.md", Title: "Spec", CreatedAt: time.Now(), Content: "[[Research]] [[Research]] [[Spec]]"})
+	createTestFile(t, scripts.File{Name: "research.md", Title: "Research", CreatedAt: time.Now(), Content: "[[Papers]]"})
+	createTestFile(t, scripts.File{Name: "papers.md", Title: "Papers", CreatedAt: time.Now(), Content: "reading list"})
+}
+
+func graphNodeIDs(graph scripts.GraphExport) map[string]bool {
+	ids := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		ids[node.ID] = true
+	}
+	return ids
+}
+
+func TestBuildGraphExport_WholeVault(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+	createGraphExportNotes(t)
+
+	graph, err := BuildGraphExport(GraphExportOptions{})
+	if err != nil {
+		t.Fatalf("BuildGraphExport failed: %v", err)
+	}
+
+	if len(graph.Nodes) != 5 {
+		t.Errorf("Expected 5 nodes, got %+v", graph.Nodes)
+	}
+
+	// Duplicate links and links to the note itself are dropped
+	expected := []scripts.GraphExportEdge{
+		{From: "launch.md", To: "spec.md", Kind: scripts.GraphEdgeLink},
+		{From: "research.md", To: "papers.md", Kind: scripts.GraphEdgeLink},
+		{From: "spec.md", To: "research.md", Kind: scripts.GraphEdgeLink},
+		{From: "launch.md", To: "ship.md", Kind: scripts.GraphEdgeObjective},
+	}
+	if len(graph.Edges) != len(expected) {
+		t.Fatalf("Expected %d edges, got %+v", len(expected), graph.Edges)
+	}
+	for i, edge := range expected {
+		if graph.Edges[i] != edge {
+			t.Errorf("Edge %d: expected %+v, got %+v", i, edge, graph.Edges[i])
+		}
+	}
+}
+
+func TestBuildGraphExport_CentredSubset(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+	createGraphExportNotes(t)
+
+	graph, err := BuildGraphExport(GraphExportOptions{Center: "ship.md", Depth: 2})
+	if err != nil {
+		t.Fatalf("BuildGraphExport failed: %v", err)
+	}
+
+	// ship → launch (objective) → spec (link), research and papers are further away
+	ids := graphNodeIDs(graph)
+	if len(ids) != 3 || !ids["ship.md"] || !ids["launch.md"] || !ids["spec.md"] {
+		t.Errorf("Expected ship, launch and spec, got %v", ids)
+	}
+	if len(graph.Edges) != 2 {
+		t.Errorf("Expected 2 edges inside the subset, got %+v", graph.Edges)
+	}
+}
+
+func TestBuildGraphExport_TagNodes(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+	createGraphExportNotes(t)
+
+	graph, err := BuildGraphExport(GraphExportOptions{Center: "ship.md", Depth: 1, Tags: true})
+	if err != nil {
+		t.Fatalf("BuildGraphExport failed: %v", err)
+	}
+
+	ids := graphNodeIDs(graph)
+	if !ids["tag:work"] || ids["tag:todo"] {
+		t.Errorf("Expected a work tag node and no todo type tag node, got %v", ids)
+	}
+	last := graph.Edges[len(graph.Edges)-1]
+	if last != (scripts.GraphExportEdge{From: "ship.md", To: "tag:work", Kind: scripts.GraphEdgeTag}) {
+		t.Errorf("Expected a tag edge, got %+v", last)
+	}
+}
+
+func TestBuildGraphExport_UnknownCenter(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+	createGraphExportNotes(t)
+
+	if _, err := BuildGraphExport(GraphExportOptions{Center: "missing.md", Depth: 1}); err == nil {
+		t.Error("Expected an error for a missing center note")
+	}
+}
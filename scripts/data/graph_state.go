@@ -2,13 +2,60 @@ package data
 
 import (
 	"cli-notes/scripts"
+	"sort"
+	"strings"
 )
 
+// MaxGraphDepth is the furthest number of link hops the graph view shows
+const MaxGraphDepth = 3
+
+// GraphStatusFilter limits the graph to open or done notes
+type GraphStatusFilter int
+
+const (
+	GraphShowAll GraphStatusFilter = iota
+	GraphShowOpen
+	GraphShowDone
+)
+
+// GraphFilter limits which notes the graph shows; the center note is always shown
+// Hidden notes still connect the notes behind them
+type GraphFilter struct {
+	Tag    string            // Hierarchical tag the notes must have, empty for any
+	Status GraphStatusFilter // Open or done notes only
+	Type   string            // Note type from scripts.NoteType, empty for any
+}
+
+// IsZero reports whether the filter shows every note
+func (f GraphFilter) IsZero() bool {
+	return f.Tag == "" && f.Status == GraphShowAll && f.Type == ""
+}
+
+// Matches reports whether a note passes the filter
+func (f GraphFilter) Matches(file scripts.File) bool {
+	if f.Tag != "" && !scripts.HasMatchingTag(file.Tags, f.Tag) {
+		return false
+	}
+	if f.Status == GraphShowOpen && file.Done || f.Status == GraphShowDone && !file.Done {
+		return false
+	}
+	return f.Type == "" || scripts.NoteType(file) == f.Type
+}
+
+// GraphPathStep is a note on a link path, with the direction of the link that reached it
+type GraphPathStep struct {
+	File    scripts.File
+	Forward bool // The previous note links to this one, otherwise this one links back
+}
+
 // GraphNode represents a node in the link graph
 type GraphNode struct {
 	File      scripts.File
 	IsCenter  bool
-	Direction string // "in" for backlinks, "out" for outgoing links, "center" for the selected node
+	Direction string // "in" for backlinks, "out" for outgoing links, "center" for the selected node, "hop" for further notes
+	Ambiguous bool   // The link matches several notes sharing a title
+	Distance  int    // Link hops from the center, 0 for the center
+	Via       string // Title of the note one hop closer to the center, for notes beyond the first hop
 }
 
 // GraphViewState holds the state for the graph view
@@ -16,14 +63,25 @@ type GraphViewState struct {
 	CenterNode  scripts.File   // The node at the center of the graph
 	OutLinks    []scripts.File // Notes this file links to
 	BackLinks   []scripts.File // Notes that link to this file
+	HopNodes    []GraphNode    // Notes 2 or more hops away, nearest first
 	SelectedIdx int            // Currently selected node in the list
 	Nodes       []GraphNode    // All nodes for navigation
+	Depth       int            // Link hops shown, 1 to MaxGraphDepth
+	Filter      GraphFilter    // Notes shown besides the center
+
+	PathTarget *scripts.File   // Note the path was searched to, nil when no path was asked for
+	Path       []GraphPathStep // Shortest link path from the center to PathTarget, empty when unconnected
+
+	ambiguousOut map[string]bool // Outgoing links that match several notes, by filename
+	ambiguousIn  map[string]bool // Backlinks that match several notes, by filename
+	index        *LinkIndex      // Links as of the last refresh
 }
 
 // NewGraphViewState creates a new graph view state centered on the given file
 func NewGraphViewState(centerFile scripts.File) (*GraphViewState, error) {
 	state := &GraphViewState{
 		CenterNode: centerFile,
+		Depth:      1,
 	}
 
 	err := state.Refresh()
@@ -35,20 +93,50 @@ func NewGraphViewState(centerFile scripts.File) (*GraphViewState, error) {
 }
 
 // Refresh reloads the links from disk
+// Ambiguous links show every note they could point at
 func (s *GraphViewState) Refresh() error {
-	// Get outgoing links
-	outLinks, err := GetLinksFrom(s.CenterNode.Name)
-	if err != nil {
+	if _, err := LoadFileByName(s.CenterNode.Name); err != nil {
 		return err
 	}
-	s.OutLinks = outLinks
 
-	// Get backlinks
-	backLinks, err := GetBacklinks(s.CenterNode.Name)
+	index, err := BuildLinkIndex()
 	if err != nil {
 		return err
 	}
-	s.BackLinks = backLinks
+	s.index = index
+
+	// Get outgoing links
+	s.OutLinks = make([]scripts.File, 0)
+	s.ambiguousOut = make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, fileName := range index.OutLinks[s.CenterNode.Name] {
+		if seen[fileName] {
+			continue
+		}
+		seen[fileName] = true
+		if !s.Filter.Matches(index.FilesByName[fileName]) {
+			continue
+		}
+		s.OutLinks = append(s.OutLinks, index.FilesByName[fileName])
+		s.ambiguousOut[fileName] = index.IsAmbiguousEdge(s.CenterNode.Name, fileName)
+	}
+
+	// Get backlinks
+	s.BackLinks = make([]scripts.File, 0)
+	s.ambiguousIn = make(map[string]bool)
+	for _, file := range index.Backlinks(s.CenterNode.Name) {
+		if !s.Filter.Matches(file) {
+			continue
+		}
+		s.BackLinks = append(s.BackLinks, file)
+		s.ambiguousIn[file.Name] = index.IsAmbiguousEdge(file.Name, s.CenterNode.Name)
+	}
+
+	s.HopNodes = s.findHopNodes(index)
+
+	if s.PathTarget != nil {
+		s.Path = findPath(index, s.CenterNode.Name, s.PathTarget.Name)
+	}
 
 	// Build nodes list for navigation
 	s.buildNodesList()
@@ -56,6 +144,137 @@ func (s *GraphViewState) Refresh() error {
 	return nil
 }
 
+// findHopNodes returns the notes 2 to Depth hops from the center, following links either way
+// Notes at the same distance are sorted by title
+func (s *GraphViewState) findHopNodes(index *LinkIndex) []GraphNode {
+	hopNodes := make([]GraphNode, 0)
+	visited := map[string]bool{s.CenterNode.Name: true}
+	frontier := []string{s.CenterNode.Name}
+
+	for distance := 1; distance <= s.depth() && len(frontier) > 0; distance++ {
+		next := make([]string, 0)
+		ring := make([]GraphNode, 0)
+
+		for _, fileName := range frontier {
+			for _, neighbour := range index.Neighbours(fileName) {
+				if visited[neighbour] {
+					continue
+				}
+				visited[neighbour] = true
+				next = append(next, neighbour)
+
+				file := index.FilesByName[neighbour]
+				if distance == 1 || !s.Filter.Matches(file) {
+					continue
+				}
+				ring = append(ring, GraphNode{
+					File:      file,
+					Direction: "hop",
+					Distance:  distance,
+					Via:       index.FilesByName[fileName].Title,
+				})
+			}
+		}
+
+		sort.SliceStable(ring, func(i, j int) bool {
+			return strings.ToLower(ring[i].File.Title) < strings.ToLower(ring[j].File.Title)
+		})
+		hopNodes = append(hopNodes, ring...)
+		frontier = next
+	}
+
+	return hopNodes
+}
+
+// findPath returns the shortest link path between two notes with the direction of each link
+func findPath(index *LinkIndex, fromFileName, toFileName string) []GraphPathStep {
+	fileNames := index.ShortestPath(fromFileName, toFileName)
+	path := make([]GraphPathStep, 0, len(fileNames))
+	for i, fileName := range fileNames {
+		path = append(path, GraphPathStep{
+			File:    index.FilesByName[fileName],
+			Forward: i == 0 || index.LinksTo(fileNames[i-1], fileName),
+		})
+	}
+	return path
+}
+
+// depth returns the number of hops shown, treating an unset depth as 1
+func (s *GraphViewState) depth() int {
+	if s.Depth < 1 {
+		return 1
+	}
+	if s.Depth > MaxGraphDepth {
+		return MaxGraphDepth
+	}
+	return s.Depth
+}
+
+// IncreaseDepth shows one more hop, up to MaxGraphDepth
+func (s *GraphViewState) IncreaseDepth() error {
+	if s.depth() == MaxGraphDepth {
+		return nil
+	}
+	s.Depth = s.depth() + 1
+	return s.Refresh()
+}
+
+// DecreaseDepth shows one hop less, down to the direct links
+func (s *GraphViewState) DecreaseDepth() error {
+	if s.depth() == 1 {
+		return nil
+	}
+	s.Depth = s.depth() - 1
+	return s.Refresh()
+}
+
+// SetTagFilter shows only notes with the tag or a tag nested under it, empty to show all tags
+func (s *GraphViewState) SetTagFilter(tag string) error {
+	s.Filter.Tag = scripts.NormalizeTag(tag)
+	return s.Refresh()
+}
+
+// CycleStatusFilter cycles through all, open and done notes
+func (s *GraphViewState) CycleStatusFilter() error {
+	s.Filter.Status = (s.Filter.Status + 1) % 3
+	return s.Refresh()
+}
+
+// CycleTypeFilter cycles through every note type and back to all types
+func (s *GraphViewState) CycleTypeFilter() error {
+	next := ""
+	for i, noteType := range scripts.NoteTypes {
+		if noteType == s.Filter.Type && i+1 < len(scripts.NoteTypes) {
+			next = scripts.NoteTypes[i+1]
+		}
+	}
+	if s.Filter.Type == "" {
+		next = scripts.NoteTypes[0]
+	}
+	s.Filter.Type = next
+	return s.Refresh()
+}
+
+// ClearFilters shows every note again
+func (s *GraphViewState) ClearFilters() error {
+	s.Filter = GraphFilter{}
+	return s.Refresh()
+}
+
+// ResolveLink returns the note a link or title points at, against the links of the last refresh
+func (s *GraphViewState) ResolveLink(linkText string) *scripts.File {
+	if s.index == nil {
+		return nil
+	}
+	return s.index.ResolveFile(linkText)
+}
+
+// FindPathTo finds the shortest link path from the center to the target note
+func (s *GraphViewState) FindPathTo(target scripts.File) error {
+	s.PathTarget = &target
+	return s.Refresh()
+}
+
 // buildNodesList builds the list of navigable nodes
 func (s *GraphViewState) buildNodesList() {
 	s.Nodes = make([]GraphNode, 0)
@@ -65,6 +284,8 @@ func (s *GraphViewState) buildNodesList() {
 		s.Nodes = append(s.Nodes, GraphNode{
 			File:      file,
 			Direction: "in",
+			Ambiguous: s.ambiguousIn[file.Name],
+			Distance:  1,
 		})
 	}
 
@@ -80,9 +301,14 @@ func (s *GraphViewState) buildNodesList() {
 		s.Nodes = append(s.Nodes, GraphNode{
 			File:      file,
 			Direction: "out",
+			Ambiguous: s.ambiguousOut[file.Name],
+			Distance:  1,
 		})
 	}
 
+	// Add notes further away, nearest first
+	s.Nodes = append(s.Nodes, s.HopNodes...)
+
 	// Clamp selected index
 	if s.SelectedIdx >= len(s.Nodes) {
 		s.SelectedIdx = len(s.Nodes) - 1
@@ -132,6 +358,8 @@ func (s *GraphViewState) NavigateToSelected() error {
 
 	s.CenterNode = file
 	s.SelectedIdx = 0 // Reset selection
+	s.PathTarget = nil
+	s.Path = nil
 
 	return s.Refresh()
 }
@@ -64,6 +64,43 @@ func TestNewGraphViewState_LoadsLinksAndBacklinks(t *testing.T) {
 	}
 }
 
+func TestNewGraphViewState_MarksAmbiguousLinks(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "center.md", Title: "Center", CreatedAt: time.Now(), Content: "[[standup]] [[Target]]"})
+	createTestFile(t, scripts.File{Name: "standup-1.md", Title: "standup", CreatedAt: time.Now()})
+	createTestFile(t, scripts.File{Name: "standup-2.md", Title: "standup", CreatedAt: time.Now()})
+	createTestFile(t, scripts.File{Name: "target.md", Title: "Target", CreatedAt: time.Now()})
+
+	centerFile, err := LoadFileByName("center.md")
+	if err != nil {
+		t.Fatalf("Failed to load center file: %v", err)
+	}
+
+	state, err := NewGraphViewState(centerFile)
+	if err != nil {
+		t.Fatalf("NewGraphViewState failed: %v", err)
+	}
+
+	ambiguous := make(map[string]bool)
+	for _, node := range state.Nodes {
+		if node.Direction == "out" {
+			ambiguous[node.File.Name] = node.Ambiguous
+		}
+	}
+
+	expected := map[string]bool{"standup-1.md": true, "standup-2.md": true, "target.md": false}
+	for name, want := range expected {
+		got, exists := ambiguous[name]
+		if !exists {
+			t.Errorf("Expected %s as an outlink", name)
+		} else if got != want {
+			t.Errorf("Expected %s ambiguous=%v, got %v", name, want, got)
+		}
+	}
+}
+
 func TestGraphViewState_BuildsNodesList(t *testing.T) {
 	th := setupTest(t)
 	defer th.cleanup(t)
@@ -418,3 +455,154 @@ func TestGraphViewState_EmptyGraph(t *testing.T) {
 		t.Error("Only node should be the center node")
 	}
 }
+
+// ============================================
+// Depth, Filter and Path Tests
+// ============================================
+
+// createGraphChain creates center -> a -> b -> c, with d linking to a
+func createGraphChain(t *testing.T) scripts.File {
+	createTestFile(t, scripts.File{Name: "center.md", Title: "Center", CreatedAt: time.Now(), Content: "[[A]]"})
+	createTestFile(t, scripts.File{Name: "a.md", Title: "A", Tags: []string{"todo", "work/payments"}, CreatedAt: time.Now(), Content: "[[B]]"})
+	createTestFile(t, scripts.File{Name: "b.md", Title: "B", Tags: []string{"meeting"}, CreatedAt: time.Now(), Done: true, Content: "[[C]]"})
+	createTestFile(t, scripts.File{Name: "c.md", Title: "C", Tags: []string{"todo", "work"}, CreatedAt: time.Now()})
+	createTestFile(t, scripts.File{Name: "d.md", Title: "D", Tags: []string{"todo"}, CreatedAt: time.Now(), Content: "[[A]]"})
+
+	centerFile, err := LoadFileByName("center.md")
+	if err != nil {
+		t.Fatalf("Failed to load center file: %v", err)
+	}
+	return centerFile
+}
+
+func TestGraphViewState_DepthShowsFurtherNotesByDistance(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	state, err := NewGraphViewState(createGraphChain(t))
+	if err != nil {
+		t.Fatalf("NewGraphViewState failed: %v", err)
+	}
+	if len(state.HopNodes) != 0 {
+		t.Errorf("Expected no further notes at depth 1, got %d", len(state.HopNodes))
+	}
+
+	if err := state.IncreaseDepth(); err != nil {
+		t.Fatalf("IncreaseDepth failed: %v", err)
+	}
+	if err := state.IncreaseDepth(); err != nil {
+		t.Fatalf("IncreaseDepth failed: %v", err)
+	}
+	if err := state.IncreaseDepth(); err != nil {
+		t.Fatalf("IncreaseDepth failed: %v", err)
+	}
+	if state.Depth != MaxGraphDepth {
+		t.Errorf("Expected depth to stop at %d, got %d", MaxGraphDepth, state.Depth)
+	}
+
+	expected := []struct {
+		name     string
+		distance int
+		via      string
+	}{
+		{name: "b.md", distance: 2, via: "A"},
+		{name: "d.md", distance: 2, via: "A"},
+		{name: "c.md", distance: 3, via: "B"},
+	}
+	if len(state.HopNodes) != len(expected) {
+		t.Fatalf("Expected %d further notes, got %+v", len(expected), state.HopNodes)
+	}
+	for i, node := range expected {
+		got := state.HopNodes[i]
+		if got.File.Name != node.name || got.Distance != node.distance || got.Via != node.via {
+			t.Errorf("Expected %s at %d hops via %s, got %s at %d via %s", node.name, node.distance, node.via, got.File.Name, got.Distance, got.Via)
+		}
+	}
+
+	// Further notes come after the direct links in the navigation list
+	if last := state.Nodes[len(state.Nodes)-1]; last.File.Name != "c.md" {
+		t.Errorf("Expected the furthest note last, got %s", last.File.Name)
+	}
+}
+
+func TestGraphViewState_FiltersByTagStatusAndType(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	state, err := NewGraphViewState(createGraphChain(t))
+	if err != nil {
+		t.Fatalf("NewGraphViewState failed: %v", err)
+	}
+	state.Depth = MaxGraphDepth
+
+	if err := state.SetTagFilter("work"); err != nil {
+		t.Fatalf("SetTagFilter failed: %v", err)
+	}
+	if len(state.OutLinks) != 1 || len(state.HopNodes) != 1 || state.HopNodes[0].File.Name != "c.md" {
+		t.Errorf("Expected A and C through the hidden B, got %v and %+v", state.OutLinks, state.HopNodes)
+	}
+
+	if err := state.ClearFilters(); err != nil {
+		t.Fatalf("ClearFilters failed: %v", err)
+	}
+	if err := state.CycleStatusFilter(); err != nil {
+		t.Fatalf("CycleStatusFilter failed: %v", err)
+	}
+	for _, node := range state.Nodes {
+		if node.File.Done {
+			t.Errorf("Expected only open notes, got %s", node.File.Name)
+		}
+	}
+
+	if err := state.ClearFilters(); err != nil {
+		t.Fatalf("ClearFilters failed: %v", err)
+	}
+	if err := state.CycleTypeFilter(); err != nil { // objective
+		t.Fatalf("CycleTypeFilter failed: %v", err)
+	}
+	if err := state.CycleTypeFilter(); err != nil { // todo
+		t.Fatalf("CycleTypeFilter failed: %v", err)
+	}
+	if state.Filter.Type != scripts.NoteTypeTodo {
+		t.Fatalf("Expected the todo type filter, got '%s'", state.Filter.Type)
+	}
+	for _, node := range state.Nodes {
+		if !node.IsCenter && scripts.NoteType(node.File) != scripts.NoteTypeTodo {
+			t.Errorf("Expected only todos, got %s", node.File.Name)
+		}
+	}
+}
+
+func TestGraphViewState_FindPathTo(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	state, err := NewGraphViewState(createGraphChain(t))
+	if err != nil {
+		t.Fatalf("NewGraphViewState failed: %v", err)
+	}
+	createTestFile(t, scripts.File{Name: "island.md", Title: "Island", CreatedAt: time.Now()})
+
+	d, _ := LoadFileByName("d.md")
+	if err := state.FindPathTo(d); err != nil {
+		t.Fatalf("FindPathTo failed: %v", err)
+	}
+
+	if len(state.Path) != 3 {
+		t.Fatalf("Expected Center -> A <- D, got %+v", state.Path)
+	}
+	if state.Path[1].File.Name != "a.md" || !state.Path[1].Forward {
+		t.Errorf("Expected Center to link to A, got %+v", state.Path[1])
+	}
+	if state.Path[2].File.Name != "d.md" || state.Path[2].Forward {
+		t.Errorf("Expected D to link back to A, got %+v", state.Path[2])
+	}
+
+	island, _ := LoadFileByName("island.md")
+	if err := state.FindPathTo(island); err != nil {
+		t.Fatalf("FindPathTo failed: %v", err)
+	}
+	if len(state.Path) != 0 {
+		t.Errorf("Expected no path to an unlinked note, got %+v", state.Path)
+	}
+}
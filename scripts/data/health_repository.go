@@ -0,0 +1,92 @@
+package data
+
+import (
+	"bufio"
+	"cli-notes/scripts"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoadFrontmatter returns the raw frontmatter values of a note, before bad values are replaced with defaults
+func LoadFrontmatter(fileName string) (map[string]string, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	file, err := os.Open(filepath.Join(currentDir, DirectoryPath, fileName))
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	frontmatter := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	inMetadata := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "---" {
+			if inMetadata {
+				break
+			}
+			inMetadata = true
+			continue
+		}
+		if !inMetadata {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 {
+			frontmatter[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	return frontmatter, nil
+}
+
+// LoadVaultNotes loads every note with its raw frontmatter, dead links and whether it is linked at all
+func LoadVaultNotes() ([]scripts.VaultNote, error) {
+	index, err := BuildLinkIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	fileNames := make([]string, 0, len(index.FilesByName))
+	for fileName := range index.FilesByName {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	notes := make([]scripts.VaultNote, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		file := index.FilesByName[fileName]
+
+		frontmatter, err := LoadFrontmatter(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		deadLinks := make([]string, 0)
+		for _, linkText := range ParseLinks(file.Content) {
+			if len(index.Candidates(linkText)) == 0 {
+				deadLinks = append(deadLinks, linkText)
+			}
+		}
+
+		notes = append(notes, scripts.VaultNote{
+			File:        file,
+			Frontmatter: frontmatter,
+			DeadLinks:   deadLinks,
+			Linked:      len(index.Neighbours(fileName)) > 0,
+		})
+	}
+
+	return notes, nil
+}
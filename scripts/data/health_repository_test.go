@@ -0,0 +1,58 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadFrontmatter_KeepsRawValues(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	content := "---\ntitle: Bad\ndate-due: 2024/3/5\npriority: 9\n---\n\npriority: not frontmatter\n"
+	if err := os.WriteFile(filepath.Join("notes", "bad.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	frontmatter, err := LoadFrontmatter("bad.md")
+	if err != nil {
+		t.Fatalf("LoadFrontmatter failed: %v", err)
+	}
+	if frontmatter["date-due"] != "2024/3/5" || frontmatter["priority"] != "9" || frontmatter["title"] != "Bad" {
+		t.Errorf("Unexpected frontmatter %v", frontmatter)
+	}
+}
+
+func TestLoadVaultNotes_FindsDeadLinksAndOrphans(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "a.md", Title: "A", CreatedAt: time.Now(), Content: "[[B]] and [[Nowhere]]"})
+	createTestFile(t, scripts.File{Name: "b.md", Title: "B", CreatedAt: time.Now(), Content: "b"})
+	createTestFile(t, scripts.File{Name: "c.md", Title: "C", CreatedAt: time.Now(), Content: "[[C]]"})
+
+	notes, err := LoadVaultNotes()
+	if err != nil {
+		t.Fatalf("LoadVaultNotes failed: %v", err)
+	}
+	if len(notes) != 3 {
+		t.Fatalf("Expected 3 notes, got %d", len(notes))
+	}
+
+	if !notes[0].Linked || len(notes[0].DeadLinks) != 1 || notes[0].DeadLinks[0] != "Nowhere" {
+		t.Errorf("Expected a.md to be linked with one dead link, got %+v", notes[0])
+	}
+	if !notes[1].Linked {
+		t.Errorf("Expected b.md to be linked from a.md")
+	}
+	// A link to itself does not connect a note
+	if notes[2].Linked {
+		t.Errorf("Expected c.md to be an orphan")
+	}
+	if notes[0].Frontmatter["priority"] != "2" {
+		t.Errorf("Expected raw frontmatter, got %v", notes[0].Frontmatter)
+	}
+}
@@ -0,0 +1,189 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// HistoryViewState holds the state of the history view: the commits of a note, or the deleted notes
+type HistoryViewState struct {
+	FileName    string                // Note whose history is shown, empty when listing deleted notes
+	Title       string                // Title of the note, or its filename once deleted
+	Versions    []scripts.NoteVersion // Commits of the note, or the commit deleting each deleted note, newest first
+	SelectedIdx int
+
+	notesPath string
+	current   string            // Current content of the note, empty when it is deleted
+	contents  map[string]string // Content of each version, by revision and path, loaded when first shown
+}
+
+// NewHistoryViewState loads the commits of a note, which may since have been deleted
+func NewHistoryViewState(fileName string) (*HistoryViewState, error) {
+	state, err := newHistoryViewState()
+	if err != nil {
+		return nil, err
+	}
+	state.FileName = fileName
+	state.Title = fileName
+
+	if err := state.Refresh(); err != nil {
+		return nil, err
+	}
+	if len(state.Versions) == 0 {
+		return nil, fmt.Errorf("no history for %s", fileName)
+	}
+	return state, nil
+}
+
+// NewDeletedNotesViewState lists the deleted notes that can be restored from history
+func NewDeletedNotesViewState() (*HistoryViewState, error) {
+	state, err := newHistoryViewState()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := state.Refresh(); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func newHistoryViewState() (*HistoryViewState, error) {
+	notesPath, err := gitNotesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistoryViewState{
+		notesPath: notesPath,
+		contents:  make(map[string]string),
+	}, nil
+}
+
+// IsDeletedNotes reports whether the view lists deleted notes rather than the history of one note
+func (s *HistoryViewState) IsDeletedNotes() bool {
+	return s.FileName == ""
+}
+
+// Refresh reloads the commits and the current content from disk
+func (s *HistoryViewState) Refresh() error {
+	var err error
+	if s.IsDeletedNotes() {
+		s.Versions, err = scripts.DeletedNotes(s.notesPath)
+	} else {
+		s.Versions, err = scripts.NoteHistory(s.notesPath, s.FileName)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.current = ""
+	if !s.IsDeletedNotes() {
+		if content, err := os.ReadFile(filepath.Join(s.notesPath, s.FileName)); err == nil {
+			s.current = string(content)
+		}
+		if file, err := LoadFileByName(s.FileName); err == nil && file.Title != "" {
+			s.Title = file.Title
+		}
+	}
+
+	if s.SelectedIdx >= len(s.Versions) {
+		s.SelectedIdx = 0
+	}
+	return nil
+}
+
+// IsCurrentDeleted reports whether the note of the history no longer exists
+func (s *HistoryViewState) IsCurrentDeleted() bool {
+	if s.IsDeletedNotes() {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(s.notesPath, s.FileName))
+	return err != nil
+}
+
+// SelectNext moves the selection down
+func (s *HistoryViewState) SelectNext() {
+	if len(s.Versions) > 0 {
+		s.SelectedIdx = (s.SelectedIdx + 1) % len(s.Versions)
+	}
+}
+
+// SelectPrevious moves the selection up
+func (s *HistoryViewState) SelectPrevious() {
+	if len(s.Versions) > 0 {
+		s.SelectedIdx = (s.SelectedIdx - 1 + len(s.Versions)) % len(s.Versions)
+	}
+}
+
+// SelectedVersion returns the selected commit, nil when there are none
+func (s *HistoryViewState) SelectedVersion() *scripts.NoteVersion {
+	if s.SelectedIdx < 0 || s.SelectedIdx >= len(s.Versions) {
+		return nil
+	}
+	return &s.Versions[s.SelectedIdx]
+}
+
+// SelectedContent returns the note's content at the selected commit
+func (s *HistoryViewState) SelectedContent() (string, error) {
+	version := s.SelectedVersion()
+	if version == nil {
+		return "", fmt.Errorf("no version selected")
+	}
+
+	key := version.Revision() + ":" + version.Path
+	if content, cached := s.contents[key]; cached {
+		return content, nil
+	}
+	content, err := scripts.NoteVersionContent(s.notesPath, *version)
+	if err != nil {
+		return "", err
+	}
+	s.contents[key] = content
+	return content, nil
+}
+
+// SelectedDiff compares the selected version with the current note
+// Removed lines are only in the selected version, added lines only in the current note
+func (s *HistoryViewState) SelectedDiff() ([]scripts.DiffLine, error) {
+	content, err := s.SelectedContent()
+	if err != nil {
+		return nil, err
+	}
+	return scripts.DiffLines(content, s.current), nil
+}
+
+// RestoreTarget returns the filename the selected version is restored to
+func (s *HistoryViewState) RestoreTarget() string {
+	if !s.IsDeletedNotes() {
+		return s.FileName
+	}
+	if version := s.SelectedVersion(); version != nil {
+		return filepath.Base(version.Path)
+	}
+	return ""
+}
+
+// RestoreSelected writes the selected version back as the current note and returns its filename
+// The caller commits it, so the restore is a new commit and the history stays intact, then refreshes
+func (s *HistoryViewState) RestoreSelected() (string, error) {
+	content, err := s.SelectedContent()
+	if err != nil {
+		return "", err
+	}
+
+	fileName := s.RestoreTarget()
+	path := filepath.Join(s.notesPath, fileName)
+	if s.IsDeletedNotes() {
+		if _, err := os.Stat(path); err == nil {
+			return "", fmt.Errorf("%s already exists", fileName)
+		}
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to restore %s: %w", fileName, err)
+	}
+
+	return fileName, nil
+}
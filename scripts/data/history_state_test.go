@@ -0,0 +1,99 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryViewState_DiffsAndRestoresVersion(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "plan.md", Title: "Plan", CreatedAt: time.Now(), Content: "first draft"})
+	if err := scripts.InitGitRepo(DirectoryPath); err != nil {
+		t.Fatalf("InitGitRepo failed: %v", err)
+	}
+	createTestFile(t, scripts.File{Name: "plan.md", Title: "Plan", CreatedAt: time.Now(), Content: "second draft"})
+	if err := scripts.CommitChangesWithMessage(DirectoryPath, "edit plan", []string{"."}); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	state, err := NewHistoryViewState("plan.md")
+	if err != nil {
+		t.Fatalf("NewHistoryViewState failed: %v", err)
+	}
+	if len(state.Versions) != 2 || state.Title != "Plan" {
+		t.Fatalf("Expected 2 versions of Plan, got %+v", state.Versions)
+	}
+
+	state.SelectNext()
+	diff, err := state.SelectedDiff()
+	if err != nil {
+		t.Fatalf("SelectedDiff failed: %v", err)
+	}
+	if !containsDiffLine(diff, scripts.DiffRemoved, "first draft") || !containsDiffLine(diff, scripts.DiffAdded, "second draft") {
+		t.Errorf("Expected the first draft replaced by the second, got %+v", diff)
+	}
+
+	fileName, err := state.RestoreSelected()
+	if err != nil || fileName != "plan.md" {
+		t.Fatalf("RestoreSelected failed: %v", err)
+	}
+	file, _ := LoadFileByName("plan.md")
+	if !strings.Contains(file.Content, "first draft") {
+		t.Errorf("Expected the first draft restored, got %q", file.Content)
+	}
+}
+
+func TestDeletedNotesViewState_RestoresDeletedNote(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "objective.md", Title: "Objective", CreatedAt: time.Now(), Content: "goals"})
+	if err := scripts.InitGitRepo(DirectoryPath); err != nil {
+		t.Fatalf("InitGitRepo failed: %v", err)
+	}
+	os.Remove(filepath.Join(DirectoryPath, "objective.md"))
+	if err := scripts.CommitChangesWithMessage(DirectoryPath, "delete objective", []string{"."}); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	state, err := NewDeletedNotesViewState()
+	if err != nil {
+		t.Fatalf("NewDeletedNotesViewState failed: %v", err)
+	}
+	if len(state.Versions) != 1 || state.RestoreTarget() != "objective.md" {
+		t.Fatalf("Expected objective.md to be listed, got %+v", state.Versions)
+	}
+
+	if _, err := state.RestoreSelected(); err != nil {
+		t.Fatalf("RestoreSelected failed: %v", err)
+	}
+	file, err := LoadFileByName("objective.md")
+	if err != nil || file.Title != "Objective" {
+		t.Errorf("Expected the objective restored, got %+v (%v)", file, err)
+	}
+}
+
+func TestNewHistoryViewState_RequiresGit(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "plan.md", Title: "Plan", CreatedAt: time.Now()})
+	if _, err := NewHistoryViewState("plan.md"); err == nil {
+		t.Error("Expected an error when the notes are not under git")
+	}
+}
+
+func containsDiffLine(diff []scripts.DiffLine, kind rune, text string) bool {
+	for _, line := range diff {
+		if line.Kind == kind && line.Text == text {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -21,7 +22,8 @@ func ParseLinks(content string) []string {
 
 	for _, match := range matches {
 		if len(match) > 1 {
-			linkText := strings.TrimSpace(match[1])
+			// Links inside markdown tables escape the alias separator as \|
+			linkText := strings.TrimSpace(strings.ReplaceAll(match[1], "\\|", "|"))
 			if linkText != "" && !seen[linkText] {
 				links = append(links, linkText)
 				seen[linkText] = true
@@ -32,64 +34,48 @@ func ParseLinks(content string) []string {
 	return links
 }
 
-// ResolveLink finds a file matching the link text (by title or filename)
-func ResolveLink(linkText string) (*scripts.File, error) {
-	currentDir, err := os.Getwd()
+// LoadLinkTargets loads every note to resolve links against, without building the link graph
+// Load it once per command and resolve every link of the command with ResolveFile
+func LoadLinkTargets() (*LinkIndex, error) {
+	return loadLinkIndexFiles()
+}
+
+// GetLinksFrom returns all files that the given file links to
+func GetLinksFrom(fileName string) ([]scripts.File, error) {
+	resolutions, err := GetLinkResolutions(fileName)
 	if err != nil {
 		return nil, err
 	}
 
-	notesPath := filepath.Join(currentDir, DirectoryPath)
-	linkTextLower := strings.ToLower(linkText)
-
-	var matchedFile *scripts.File
-
-	err = filepath.Walk(notesPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if info.IsDir() {
-			return nil
-		}
-
-		fileName := info.Name()
-		fileNameWithoutExt := strings.TrimSuffix(fileName, filepath.Ext(fileName))
-
-		// Match by filename (without extension)
-		if strings.ToLower(fileNameWithoutExt) == linkTextLower {
-			file, err := LoadFileByName(fileName)
-			if err != nil {
-				return err
-			}
-			matchedFile = &file
-			return filepath.SkipAll
+	linkedFiles := make([]scripts.File, 0, len(resolutions))
+	for _, resolution := range resolutions {
+		if len(resolution.Candidates) > 0 {
+			linkedFiles = append(linkedFiles, resolution.Candidates[0])
 		}
+	}
 
-		// Match by title
-		file, err := LoadFileByName(fileName)
-		if err != nil {
-			return nil // Skip files that can't be loaded
-		}
+	return linkedFiles, nil
+}
 
-		if strings.ToLower(file.Title) == linkTextLower {
-			matchedFile = &file
-			return filepath.SkipAll
-		}
+// LinkResolution is a link in a note with every file it could point at
+type LinkResolution struct {
+	LinkText   string
+	Candidates []scripts.File
+}
 
-		return nil
-	})
+// IsAmbiguous reports whether several notes match the link
+func (r LinkResolution) IsAmbiguous() bool {
+	return len(r.Candidates) > 1
+}
 
-	if err != nil && err != filepath.SkipAll {
+// GetLinkResolutions resolves every link in a file, keeping all candidates of ambiguous links
+func GetLinkResolutions(fileName string) ([]LinkResolution, error) {
+	file, err := LoadFileByName(fileName)
+	if err != nil {
 		return nil, err
 	}
 
-	return matchedFile, nil
-}
-
-// GetLinksFrom returns all files that the given file links to
-func GetLinksFrom(fileName string) ([]scripts.File, error) {
-	file, err := LoadFileByName(fileName)
+	index, err := loadLinkIndexFiles()
 	if err != nil {
 		return nil, err
 	}
@@ -97,45 +83,67 @@ func GetLinksFrom(fileName string) ([]scripts.File, error) {
 	// Parse links from content (includes the area after frontmatter)
 	links := ParseLinks(file.Content)
 
-	linkedFiles := make([]scripts.File, 0, len(links))
+	resolutions := make([]LinkResolution, 0, len(links))
 	for _, linkText := range links {
-		linkedFile, err := ResolveLink(linkText)
-		if err != nil {
-			continue // Skip unresolvable links
-		}
-		if linkedFile != nil {
-			linkedFiles = append(linkedFiles, *linkedFile)
-		}
+		resolutions = append(resolutions, LinkResolution{
+			LinkText:   linkText,
+			Candidates: index.candidateFiles(linkText),
+		})
 	}
 
-	return linkedFiles, nil
+	return resolutions, nil
 }
 
 // GetBacklinks returns all files that link TO the given file
 func GetBacklinks(fileName string) ([]scripts.File, error) {
-	targetFile, err := LoadFileByName(fileName)
+	if _, err := LoadFileByName(fileName); err != nil {
+		return nil, err
+	}
+
+	index, err := BuildLinkIndex()
 	if err != nil {
 		return nil, err
 	}
 
+	return index.Backlinks(fileName), nil
+}
+
+// LinkIndex represents the full graph of note connections
+type LinkIndex struct {
+	// OutLinks maps filename to list of filenames it links to
+	OutLinks map[string][]string
+	// InLinks maps filename to list of filenames that link to it
+	InLinks map[string][]string
+	// FilesByName maps filename to File struct
+	FilesByName map[string]scripts.File
+	// FilesByTitle maps lowercase title to filename for resolution
+	FilesByTitle map[string]string
+	// FilesByID maps note ID to filename, ID-backed links resolve here first
+	FilesByID map[string]string
+	// TitleCandidates maps lowercase title to every filename with that title
+	TitleCandidates map[string][]string
+	// ambiguousEdges marks links between two files that only come from ambiguous links
+	ambiguousEdges map[string]map[string]bool
+}
+
+// loadLinkIndexFiles loads every note into an index without building the link graph
+func loadLinkIndexFiles() (*LinkIndex, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		return nil, err
 	}
 
 	notesPath := filepath.Join(currentDir, DirectoryPath)
-	targetNameWithoutExt := strings.TrimSuffix(fileName, filepath.Ext(fileName))
-	targetTitleLower := strings.ToLower(targetFile.Title)
-	targetNameLower := strings.ToLower(targetNameWithoutExt)
 
-	backlinks := make([]scripts.File, 0)
+	index := newLinkIndex()
 
+	// Load all files and build title index, in filename order
 	err = filepath.Walk(notesPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if info.IsDir() || info.Name() == fileName {
+		if info.IsDir() {
 			return nil
 		}
 
@@ -144,16 +152,7 @@ func GetBacklinks(fileName string) ([]scripts.File, error) {
 			return nil // Skip files that can't be loaded
 		}
 
-		// Check if this file links to our target
-		links := ParseLinks(file.Content)
-		for _, linkText := range links {
-			linkTextLower := strings.ToLower(linkText)
-			if linkTextLower == targetTitleLower || linkTextLower == targetNameLower {
-				backlinks = append(backlinks, file)
-				break
-			}
-		}
-
+		index.addFile(file)
 		return nil
 	})
 
@@ -161,88 +160,241 @@ func GetBacklinks(fileName string) ([]scripts.File, error) {
 		return nil, err
 	}
 
-	return backlinks, nil
+	return index, nil
 }
 
-// LinkIndex represents the full graph of note connections
-type LinkIndex struct {
-	// OutLinks maps filename to list of filenames it links to
-	OutLinks map[string][]string
-	// InLinks maps filename to list of filenames that link to it
-	InLinks map[string][]string
-	// FilesByName maps filename to File struct
-	FilesByName map[string]scripts.File
-	// FilesByTitle maps lowercase title to filename for resolution
-	FilesByTitle map[string]string
+// newLinkIndex returns an empty link index
+func newLinkIndex() *LinkIndex {
+	return &LinkIndex{
+		OutLinks:        make(map[string][]string),
+		InLinks:         make(map[string][]string),
+		FilesByName:     make(map[string]scripts.File),
+		FilesByTitle:    make(map[string]string),
+		FilesByID:       make(map[string]string),
+		TitleCandidates: make(map[string][]string),
+		ambiguousEdges:  make(map[string]map[string]bool),
+	}
+}
+
+// addFile indexes a note by name, ID, title and filename
+func (idx *LinkIndex) addFile(file scripts.File) {
+	idx.FilesByName[file.Name] = file
+	if file.ID != "" {
+		idx.FilesByID[strings.ToLower(file.ID)] = file.Name
+	}
+	if file.Title != "" {
+		titleLower := strings.ToLower(file.Title)
+		idx.FilesByTitle[titleLower] = file.Name
+		idx.TitleCandidates[titleLower] = append(idx.TitleCandidates[titleLower], file.Name)
+	}
+	// Also index by filename without extension
+	nameWithoutExt := strings.TrimSuffix(file.Name, filepath.Ext(file.Name))
+	idx.FilesByTitle[strings.ToLower(nameWithoutExt)] = file.Name
 }
 
 // BuildLinkIndex builds a complete index of all links in the notes directory
+// Ambiguous links add an edge to every note they could point at
 func BuildLinkIndex() (*LinkIndex, error) {
-	currentDir, err := os.Getwd()
+	index, err := loadLinkIndexFiles()
 	if err != nil {
 		return nil, err
 	}
 
-	notesPath := filepath.Join(currentDir, DirectoryPath)
+	index.linkFiles()
+	return index, nil
+}
 
-	index := &LinkIndex{
-		OutLinks:     make(map[string][]string),
-		InLinks:      make(map[string][]string),
-		FilesByName:  make(map[string]scripts.File),
-		FilesByTitle: make(map[string]string),
+// newLinkIndexFromFiles builds the link index of notes already loaded
+func newLinkIndexFromFiles(files []scripts.File) *LinkIndex {
+	index := newLinkIndex()
+	for _, file := range files {
+		index.addFile(file)
 	}
+	index.linkFiles()
+	return index
+}
 
-	// First pass: load all files and build title index
-	err = filepath.Walk(notesPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+// linkFiles resolves the links of every indexed note into the link graph
+func (idx *LinkIndex) linkFiles() {
+	// Build link graph, in filename order so link lists are stable
+	fileNames := make([]string, 0, len(idx.FilesByName))
+	for fileName := range idx.FilesByName {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
 
-		if info.IsDir() {
-			return nil
+	for _, fileName := range fileNames {
+		links := ParseLinks(idx.FilesByName[fileName].Content)
+
+		for _, linkText := range links {
+			candidates := idx.Candidates(linkText)
+
+			for _, targetFileName := range candidates {
+				// Add outgoing link
+				idx.OutLinks[fileName] = append(idx.OutLinks[fileName], targetFileName)
+
+				// Add incoming link (backlink)
+				idx.InLinks[targetFileName] = append(idx.InLinks[targetFileName], fileName)
+
+				// An edge stays ambiguous only while every link behind it is
+				if idx.ambiguousEdges[fileName] == nil {
+					idx.ambiguousEdges[fileName] = make(map[string]bool)
+				}
+				ambiguous, seen := idx.ambiguousEdges[fileName][targetFileName]
+				idx.ambiguousEdges[fileName][targetFileName] = len(candidates) > 1 && (!seen || ambiguous)
+			}
 		}
+	}
+}
 
-		file, err := LoadFileByName(info.Name())
-		if err != nil {
-			return nil // Skip files that can't be loaded
+// Candidates returns every filename a link could point at: the note with its ID,
+// the note with its filename, or else all notes with its title
+// Headings, block references and alias text are ignored
+func (idx *LinkIndex) Candidates(linkText string) []string {
+	id, name := scripts.ParseLinkTarget(linkText)
+
+	if id != "" {
+		if fileName, exists := idx.FilesByID[id]; exists {
+			return []string{fileName}
 		}
+	}
+
+	if name == "" {
+		return nil
+	}
 
-		index.FilesByName[info.Name()] = file
-		if file.Title != "" {
-			index.FilesByTitle[strings.ToLower(file.Title)] = info.Name()
+	nameLower := strings.ToLower(name)
+	if fileName, exists := idx.FilesByTitle[nameLower]; exists {
+		if strings.ToLower(strings.TrimSuffix(fileName, filepath.Ext(fileName))) == nameLower {
+			return []string{fileName}
 		}
-		// Also index by filename without extension
-		nameWithoutExt := strings.TrimSuffix(info.Name(), filepath.Ext(info.Name()))
-		index.FilesByTitle[strings.ToLower(nameWithoutExt)] = info.Name()
+	}
+
+	return idx.TitleCandidates[nameLower]
+}
+
+// candidateFiles returns the files a link could point at
+func (idx *LinkIndex) candidateFiles(linkText string) []scripts.File {
+	candidates := idx.Candidates(linkText)
+	files := make([]scripts.File, 0, len(candidates))
+	for _, fileName := range candidates {
+		files = append(files, idx.FilesByName[fileName])
+	}
+	return files
+}
 
+// ResolveFile returns the file a link points at, nil when there is none
+// ID-backed links resolve by ID first, other links by filename or title.
+// When several notes share the title the first by filename is returned,
+// use Candidates to detect the ambiguity
+func (idx *LinkIndex) ResolveFile(linkText string) *scripts.File {
+	fileName, found := idx.Resolve(linkText)
+	if !found {
 		return nil
-	})
+	}
+	file := idx.FilesByName[fileName]
+	return &file
+}
 
-	if err != nil {
-		return nil, err
+// Resolve returns the filename a link points at, by note ID first, then by filename or title
+// When several notes share the title the first by filename is returned
+func (idx *LinkIndex) Resolve(linkText string) (string, bool) {
+	candidates := idx.Candidates(linkText)
+	if len(candidates) == 0 {
+		return "", false
 	}
+	return candidates[0], true
+}
 
-	// Second pass: build link graph
-	for fileName, file := range index.FilesByName {
-		links := ParseLinks(file.Content)
+// IsAmbiguousEdge reports whether every link from one file to another matches several notes
+func (idx *LinkIndex) IsAmbiguousEdge(fromFileName, toFileName string) bool {
+	return idx.ambiguousEdges[fromFileName][toFileName]
+}
 
-		for _, linkText := range links {
-			// Resolve the link to a filename
-			linkTextLower := strings.ToLower(linkText)
-			targetFileName, exists := index.FilesByTitle[linkTextLower]
-			if !exists {
-				continue // Skip unresolvable links
+// Neighbours returns the files a file links to or is linked from, in filename order
+func (idx *LinkIndex) Neighbours(fileName string) []string {
+	seen := map[string]bool{fileName: true}
+	neighbours := make([]string, 0)
+	for _, linked := range append(append([]string{}, idx.OutLinks[fileName]...), idx.InLinks[fileName]...) {
+		if !seen[linked] {
+			seen[linked] = true
+			neighbours = append(neighbours, linked)
+		}
+	}
+	sort.Strings(neighbours)
+	return neighbours
+}
+
+// ShortestPath returns the filenames on the shortest link path between two files, both included
+// Links are followed in either direction. Returns nil when the files are not connected
+func (idx *LinkIndex) ShortestPath(fromFileName, toFileName string) []string {
+	if fromFileName == toFileName {
+		return []string{fromFileName}
+	}
+
+	previous := map[string]string{fromFileName: ""}
+	queue := []string{fromFileName}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, neighbour := range idx.Neighbours(current) {
+			if _, visited := previous[neighbour]; visited {
+				continue
 			}
+			previous[neighbour] = current
+
+			if neighbour == toFileName {
+				path := []string{toFileName}
+				for step := current; step != ""; step = previous[step] {
+					path = append([]string{step}, path...)
+				}
+				return path
+			}
+			queue = append(queue, neighbour)
+		}
+	}
 
-			// Add outgoing link
-			index.OutLinks[fileName] = append(index.OutLinks[fileName], targetFileName)
+	return nil
+}
 
-			// Add incoming link (backlink)
-			index.InLinks[targetFileName] = append(index.InLinks[targetFileName], fileName)
+// LinksTo reports whether one file links to another
+func (idx *LinkIndex) LinksTo(fromFileName, toFileName string) bool {
+	for _, linked := range idx.OutLinks[fromFileName] {
+		if linked == toFileName {
+			return true
 		}
 	}
+	return false
+}
 
-	return index, nil
+// Backlinks returns every other file that links to the given file, including ambiguous links
+func (idx *LinkIndex) Backlinks(fileName string) []scripts.File {
+	return idx.referencingFiles(fileName, true)
+}
+
+// ReferencingFiles returns every other file that links to the given file unambiguously
+// These are the links that follow the file when it is renamed
+func (idx *LinkIndex) ReferencingFiles(fileName string) []scripts.File {
+	return idx.referencingFiles(fileName, false)
+}
+
+func (idx *LinkIndex) referencingFiles(fileName string, includeAmbiguous bool) []scripts.File {
+	seen := make(map[string]bool)
+	files := make([]scripts.File, 0)
+
+	for _, sourceName := range idx.InLinks[fileName] {
+		if sourceName == fileName || seen[sourceName] {
+			continue
+		}
+		if !includeAmbiguous && idx.IsAmbiguousEdge(sourceName, fileName) {
+			continue
+		}
+		seen[sourceName] = true
+		files = append(files, idx.FilesByName[sourceName])
+	}
+
+	return files
 }
 
 // GetUnresolvedLinks finds all [[...]] links in a file that don't resolve to existing notes
@@ -252,12 +404,16 @@ func GetUnresolvedLinks(fileName string) ([]string, error) {
 		return nil, err
 	}
 
+	index, err := loadLinkIndexFiles()
+	if err != nil {
+		return nil, err
+	}
+
 	links := ParseLinks(file.Content)
 	unresolved := make([]string, 0)
 
 	for _, linkText := range links {
-		resolved, err := ResolveLink(linkText)
-		if err != nil || resolved == nil {
+		if len(index.Candidates(linkText)) == 0 {
 			unresolved = append(unresolved, linkText)
 		}
 	}
@@ -266,7 +422,12 @@ func GetUnresolvedLinks(fileName string) ([]string, error) {
 }
 
 // CreateNoteFromDeadLink creates a new note from an unresolved link text
+// Headings, block references and alias text are dropped from the title
 func CreateNoteFromDeadLink(linkText string) (*scripts.File, error) {
+	if _, name := scripts.ParseLinkTarget(linkText); name != "" {
+		linkText = name
+	}
+
 	// Create a new todo note with the link text as title
 	now := time.Now()
 
@@ -295,7 +456,45 @@ func CreateNoteFromDeadLink(linkText string) (*scripts.File, error) {
 	return &newFile, nil
 }
 
+// FindLinkLine returns the line of a note's file that a heading or block link points at
+// Returns 0 when the link points at the whole note or the heading or block is missing
+func FindLinkLine(fileName string, linkText string) (int, error) {
+	anchor, _ := scripts.ParseLinkAnchor(linkText)
+	if anchor.IsZero() {
+		return 0, nil
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return 0, err
+	}
+
+	raw, err := os.ReadFile(filepath.Join(currentDir, DirectoryPath, fileName))
+	if err != nil {
+		return 0, err
+	}
+
+	// Frontmatter is skipped so a YAML comment is never taken for a heading
+	lines := strings.Split(string(raw), "\n")
+	contentStart := 0
+	if len(lines) > 0 && lines[0] == "---" {
+		for i := 1; i < len(lines); i++ {
+			if lines[i] == "---" {
+				contentStart = i + 1
+				break
+			}
+		}
+	}
+
+	line := scripts.FindAnchorLine(strings.Join(lines[contentStart:], "\n"), anchor)
+	if line == 0 {
+		return 0, nil
+	}
+	return contentStart + line, nil
+}
+
 // InsertLinkAtTop adds a [[link]] reference at the top of a note (after frontmatter, before content)
+// The link text may carry a heading, block and alias, e.g. "Note#Plan|the plan"
 func InsertLinkAtTop(fileName string, linkText string) error {
 	file, err := LoadFileByName(fileName)
 	if err != nil {
@@ -304,10 +503,13 @@ func InsertLinkAtTop(fileName string, linkText string) error {
 
 	linkRef := "[[" + linkText + "]]"
 
-	// Check if link already exists
-	if strings.Contains(file.Content, linkRef) {
+	// Check if link already exists, possibly with other alias text
+	if strings.Contains(file.Content, linkRef) || containsLink(file.Content, linkText) {
 		return nil // Link already exists, nothing to do
 	}
+	if id, _ := scripts.ParseLinkTarget(linkText); id != "" && containsIDLink(file.Content, id) {
+		return nil // Already linked by ID, possibly with another label
+	}
 
 	// Insert link at the beginning of content
 	// If content starts with links already, append to that line
@@ -334,3 +536,86 @@ func InsertLinkAtTop(fileName string, linkText string) error {
 
 	return WriteFile(file)
 }
+
+// InsertIDLinkAtTop adds an ID-backed [[id:xxxx|Title]] link to the target note at the top of a note
+// The target is given an ID first if it has none; notes without frontmatter get a title link
+func InsertIDLinkAtTop(fileName string, target scripts.File) error {
+	id, err := EnsureNoteID(target.Name)
+	if err != nil {
+		return InsertLinkAtTop(fileName, target.Title)
+	}
+
+	target.ID = id
+	return InsertLinkAtTop(fileName, scripts.FormatIDLink(target))
+}
+
+// containsIDLink checks whether content already links to the note with the given ID
+func containsIDLink(content string, id string) bool {
+	for _, linkText := range ParseLinks(content) {
+		if linkID, _ := scripts.ParseLinkTarget(linkText); linkID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// containsLink checks whether content already links to the same note, heading and block
+// Alias text is ignored, so an aliased link is not added next to a plain one
+func containsLink(content string, linkText string) bool {
+	id, name := scripts.ParseLinkTarget(linkText)
+	if id != "" {
+		return false
+	}
+	anchor, _ := scripts.ParseLinkAnchor(linkText)
+
+	for _, existing := range ParseLinks(content) {
+		existingID, existingName := scripts.ParseLinkTarget(existing)
+		existingAnchor, _ := scripts.ParseLinkAnchor(existing)
+		if existingID == "" && strings.EqualFold(existingName, name) && strings.EqualFold(existingAnchor.Heading, anchor.Heading) && existingAnchor.BlockID == anchor.BlockID {
+			return true
+		}
+	}
+	return false
+}
+
+// FindUnlinkedMentions returns the plain-text mentions of a note's title and link aliases in other notes
+// Aliases are the display texts of links that point at the note, e.g. "the plan" in [[Plan|the plan]]
+func FindUnlinkedMentions(fileName string) ([]scripts.Mention, error) {
+	index, err := loadLinkIndexFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	target, exists := index.FilesByName[fileName]
+	if !exists {
+		return nil, fmt.Errorf("note not found: %s", fileName)
+	}
+
+	fileNames := make([]string, 0, len(index.FilesByName))
+	for name := range index.FilesByName {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	terms := []string{target.Title}
+	for _, name := range fileNames {
+		for _, linkText := range ParseLinks(index.FilesByName[name].Content) {
+			if resolved, ok := index.Resolve(linkText); !ok || resolved != fileName {
+				continue
+			}
+			if _, alias := scripts.ParseLinkAnchor(linkText); alias != "" {
+				terms = append(terms, alias)
+			}
+		}
+	}
+
+	mentions := make([]scripts.Mention, 0)
+	for _, name := range fileNames {
+		if name == fileName {
+			continue
+		}
+		mentions = append(mentions, scripts.FindMentions(index.FilesByName[name], terms)...)
+	}
+
+	return mentions, nil
+}
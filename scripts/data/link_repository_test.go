@@ -50,6 +50,21 @@ func TestParseLinks_DeduplicatesLinks(t *testing.T) {
 	}
 }
 
+func TestParseLinks_KeepsHeadingBlockAndAlias(t *testing.T) {
+	content := "See [[Note#Plan]], [[Note^abc]] and [[Note|the note]]\n| [[Note\\|in a table]] |"
+	links := ParseLinks(content)
+
+	expected := []string{"Note#Plan", "Note^abc", "Note|the note", "Note|in a table"}
+	if len(links) != len(expected) {
+		t.Fatalf("Expected %d links, got %v", len(expected), links)
+	}
+	for i, link := range expected {
+		if links[i] != link {
+			t.Errorf("Expected link %d to be '%s', got '%s'", i, link, links[i])
+		}
+	}
+}
+
 func TestParseLinks_HandlesEmptyContent(t *testing.T) {
 	links := ParseLinks("")
 
@@ -89,7 +104,7 @@ func TestParseLinks_HandlesWhitespaceInLinks(t *testing.T) {
 }
 
 // ============================================
-// ResolveLink Tests
+// ResolveFile Tests
 // ============================================
 
 func TestResolveLink_ByTitle(t *testing.T) {
@@ -103,10 +118,11 @@ func TestResolveLink_ByTitle(t *testing.T) {
 		Content:   "# My Important Note\n\nContent here",
 	})
 
-	resolved, err := ResolveLink("My Important Note")
+	index, err := LoadLinkTargets()
 	if err != nil {
-		t.Fatalf("ResolveLink failed: %v", err)
+		t.Fatalf("LoadLinkTargets failed: %v", err)
 	}
+	resolved := index.ResolveFile("My Important Note")
 
 	if resolved == nil {
 		t.Fatal("Expected to resolve link, got nil")
@@ -128,10 +144,11 @@ func TestResolveLink_ByFilename(t *testing.T) {
 		Content:   "Content",
 	})
 
-	resolved, err := ResolveLink("project-notes")
+	index, err := LoadLinkTargets()
 	if err != nil {
-		t.Fatalf("ResolveLink failed: %v", err)
+		t.Fatalf("LoadLinkTargets failed: %v", err)
 	}
+	resolved := index.ResolveFile("project-notes")
 
 	if resolved == nil {
 		t.Fatal("Expected to resolve link by filename, got nil")
@@ -154,10 +171,11 @@ func TestResolveLink_CaseInsensitive(t *testing.T) {
 	})
 
 	// Test lowercase
-	resolved, err := ResolveLink("my test note")
+	index, err := LoadLinkTargets()
 	if err != nil {
-		t.Fatalf("ResolveLink failed: %v", err)
+		t.Fatalf("LoadLinkTargets failed: %v", err)
 	}
+	resolved := index.ResolveFile("my test note")
 
 	if resolved == nil {
 		t.Error("Expected case-insensitive match, got nil")
@@ -168,10 +186,11 @@ func TestResolveLink_NotFound(t *testing.T) {
 	th := setupTest(t)
 	defer th.cleanup(t)
 
-	resolved, err := ResolveLink("Non Existent Note")
+	index, err := LoadLinkTargets()
 	if err != nil {
-		t.Fatalf("ResolveLink failed with error: %v", err)
+		t.Fatalf("LoadLinkTargets failed: %v", err)
 	}
+	resolved := index.ResolveFile("Non Existent Note")
 
 	if resolved != nil {
 		t.Error("Expected nil for non-existent note")
@@ -443,6 +462,154 @@ func TestBuildLinkIndex_HandlesCircularLinks(t *testing.T) {
 	}
 }
 
+func TestLinkIndex_ReferencingFilesIncludesAliasedLinks(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{
+		Name:      "a.md",
+		Title:     "A",
+		CreatedAt: time.Now(),
+		Content:   "[[C|see C]] and [[C]]",
+	})
+
+	createTestFile(t, scripts.File{
+		Name:      "b.md",
+		Title:     "B",
+		CreatedAt: time.Now(),
+		Content:   "[[c]]",
+	})
+
+	createTestFile(t, scripts.File{
+		Name:      "c.md",
+		Title:     "C",
+		CreatedAt: time.Now(),
+		Content:   "End node",
+	})
+
+	index, err := BuildLinkIndex()
+	if err != nil {
+		t.Fatalf("BuildLinkIndex failed: %v", err)
+	}
+
+	referencing := index.ReferencingFiles("c.md")
+	if len(referencing) != 2 {
+		t.Errorf("Expected 2 referencing files, got %d", len(referencing))
+	}
+}
+
+func TestBuildLinkIndex_ResolvesIDLinksFirst(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	// Two notes share a title, the ID link must pick the second one
+	createTestFile(t, scripts.File{Name: "meeting.md", Title: "Meeting", ID: "aaaa1111", CreatedAt: time.Now()})
+	createTestFile(t, scripts.File{Name: "meeting-1.md", Title: "Meeting", ID: "bbbb2222", CreatedAt: time.Now()})
+	createTestFile(t, scripts.File{
+		Name:      "source.md",
+		Title:     "Source",
+		CreatedAt: time.Now(),
+		Content:   "[[id:bbbb2222|Meeting]]",
+	})
+
+	index, err := BuildLinkIndex()
+	if err != nil {
+		t.Fatalf("BuildLinkIndex failed: %v", err)
+	}
+
+	outLinks := index.OutLinks["source.md"]
+	if len(outLinks) != 1 || outLinks[0] != "meeting-1.md" {
+		t.Errorf("Expected source to link to meeting-1.md, got %v", outLinks)
+	}
+
+	backlinks, err := GetBacklinks("meeting-1.md")
+	if err != nil {
+		t.Fatalf("GetBacklinks failed: %v", err)
+	}
+	if len(backlinks) != 1 || backlinks[0].Name != "source.md" {
+		t.Errorf("Expected source.md as the only backlink, got %v", backlinks)
+	}
+}
+
+func TestLinkTargetCandidates_ReportsAmbiguousTitles(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "standup-2025-01-03.md", Title: "standup", CreatedAt: time.Now()})
+	createTestFile(t, scripts.File{Name: "standup-2025-01-10.md", Title: "standup", CreatedAt: time.Now()})
+
+	index, err := LoadLinkTargets()
+	if err != nil {
+		t.Fatalf("LoadLinkTargets failed: %v", err)
+	}
+
+	candidates := index.Candidates("standup")
+	if len(candidates) != 2 || candidates[0] != "standup-2025-01-03.md" {
+		t.Errorf("Expected both standups in filename order, got %v", candidates)
+	}
+
+	// A filename link is never ambiguous
+	candidates = index.Candidates("standup-2025-01-10")
+	if len(candidates) != 1 || candidates[0] != "standup-2025-01-10.md" {
+		t.Errorf("Expected only standup-2025-01-10.md, got %v", candidates)
+	}
+}
+
+func TestBuildLinkIndex_MarksAmbiguousEdges(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "standup-2025-01-03.md", Title: "standup", CreatedAt: time.Now()})
+	createTestFile(t, scripts.File{Name: "standup-2025-01-10.md", Title: "standup", CreatedAt: time.Now()})
+	createTestFile(t, scripts.File{Name: "meeting.md", Title: "meeting", CreatedAt: time.Now(), Content: "[[standup]]"})
+	createTestFile(t, scripts.File{Name: "review.md", Title: "review", CreatedAt: time.Now(), Content: "[[standup]] [[standup-2025-01-10]]"})
+
+	index, err := BuildLinkIndex()
+	if err != nil {
+		t.Fatalf("BuildLinkIndex failed: %v", err)
+	}
+
+	if !index.IsAmbiguousEdge("meeting.md", "standup-2025-01-03.md") {
+		t.Error("Expected meeting -> standup-2025-01-03 to be ambiguous")
+	}
+	if index.IsAmbiguousEdge("review.md", "standup-2025-01-10.md") {
+		t.Error("Expected review -> standup-2025-01-10 to be unambiguous because of the filename link")
+	}
+
+	if backlinks := index.Backlinks("standup-2025-01-03.md"); len(backlinks) != 2 {
+		t.Errorf("Expected 2 backlinks including ambiguous ones, got %d", len(backlinks))
+	}
+	referencing := index.ReferencingFiles("standup-2025-01-10.md")
+	if len(referencing) != 1 || referencing[0].Name != "review.md" {
+		t.Errorf("Expected only review.md to follow a rename, got %v", referencing)
+	}
+}
+
+func TestResolveLink_ByID(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "renamed.md", Title: "New Title", ID: "cccc3333", CreatedAt: time.Now()})
+	index, err := LoadLinkTargets()
+	if err != nil {
+		t.Fatalf("LoadLinkTargets failed: %v", err)
+	}
+
+	t.Run("ID wins over stale label", func(t *testing.T) {
+		file := index.ResolveFile("id:cccc3333|Old Title")
+		if file == nil || file.Name != "renamed.md" {
+			t.Errorf("Expected renamed.md, got %v", file)
+		}
+	})
+
+	t.Run("Unknown ID falls back to label", func(t *testing.T) {
+		file := index.ResolveFile("id:dddd4444|New Title")
+		if file == nil || file.Name != "renamed.md" {
+			t.Errorf("Expected renamed.md, got %v", file)
+		}
+	})
+}
+
 // ============================================
 // GetUnresolvedLinks Tests
 // ============================================
@@ -593,6 +760,64 @@ func TestInsertLinkAtTop_SkipsDuplicate(t *testing.T) {
 	}
 }
 
+func TestInsertLinkAtTop_AddsAliasedLinkOnce(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "note.md", Title: "My Note", CreatedAt: time.Now(), Content: "# My Note"})
+
+	linkText := scripts.FormatLink("Other Note", scripts.LinkAnchor{Heading: "Plan"}, "the plan")
+	for i := 0; i < 2; i++ {
+		if err := InsertLinkAtTop("note.md", linkText); err != nil {
+			t.Fatalf("InsertLinkAtTop failed: %v", err)
+		}
+	}
+	if err := InsertLinkAtTop("note.md", "other note#plan|another alias"); err != nil {
+		t.Fatalf("InsertLinkAtTop failed: %v", err)
+	}
+
+	file, err := LoadFileByName("note.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+
+	if count := strings.Count(file.Content, "[["); count != 1 {
+		t.Errorf("Expected exactly 1 link, got %d in '%s'", count, file.Content)
+	}
+	if !strings.Contains(file.Content, "[[Other Note#Plan|the plan]]") {
+		t.Errorf("Expected the aliased link, got '%s'", file.Content)
+	}
+}
+
+func TestInsertIDLinkAtTop_AddsIDLinkOnce(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "note.md", Title: "My Note", CreatedAt: time.Now(), Content: "# My Note"})
+	createTestFile(t, scripts.File{Name: "target.md", Title: "Target", CreatedAt: time.Now()})
+
+	target, err := LoadFileByName("target.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := InsertIDLinkAtTop("note.md", target); err != nil {
+			t.Fatalf("InsertIDLinkAtTop failed: %v", err)
+		}
+	}
+
+	file, err := LoadFileByName("note.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+
+	expected := "[[id:" + target.ID + "|Target]]"
+	if count := strings.Count(file.Content, expected); count != 1 {
+		t.Errorf("Expected exactly 1 occurrence of %s, got %d in '%s'", expected, count, file.Content)
+	}
+}
+
 // ============================================
 // CreateNoteFromDeadLink Tests
 // ============================================
@@ -620,3 +845,95 @@ func TestCreateNoteFromDeadLink_CreatesNewNote(t *testing.T) {
 		t.Error("Expected file to exist on disk")
 	}
 }
+
+func TestCreateNoteFromDeadLink_DropsHeadingAndAlias(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	newFile, err := CreateNoteFromDeadLink("My New Topic#Plan|the plan")
+	if err != nil {
+		t.Fatalf("CreateNoteFromDeadLink failed: %v", err)
+	}
+
+	if newFile.Title != "My New Topic" {
+		t.Errorf("Expected title 'My New Topic', got '%s'", newFile.Title)
+	}
+}
+
+// ============================================
+// FindLinkLine Tests
+// ============================================
+
+func TestFindLinkLine_FindsHeadingAndBlockInFile(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{
+		Name:      "meeting.md",
+		Title:     "Meeting",
+		CreatedAt: time.Now(),
+		Content:   "# Meeting\n\n## Plan\nShip it ^ship\n",
+	})
+
+	raw, err := os.ReadFile("notes/meeting.md")
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	lines := strings.Split(string(raw), "\n")
+	lineOf := func(text string) int {
+		for i, line := range lines {
+			if line == text {
+				return i + 1
+			}
+		}
+		return -1
+	}
+
+	tests := []struct {
+		linkText string
+		want     int
+	}{
+		{linkText: "Meeting#Plan", want: lineOf("## Plan")},
+		{linkText: "Meeting#^ship|quote", want: lineOf("Ship it ^ship")},
+		{linkText: "Meeting#Missing", want: 0},
+		{linkText: "Meeting|alias", want: 0},
+	}
+
+	for _, tt := range tests {
+		line, err := FindLinkLine("meeting.md", tt.linkText)
+		if err != nil {
+			t.Fatalf("FindLinkLine failed: %v", err)
+		}
+		if line != tt.want {
+			t.Errorf("%s: expected line %d, got %d", tt.linkText, tt.want, line)
+		}
+	}
+}
+
+// ============================================
+// FindUnlinkedMentions Tests
+// ============================================
+
+func TestFindUnlinkedMentions_FindsTitleAndAliases(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "plan.md", Title: "Payments Plan", CreatedAt: time.Now(), Content: "Payments Plan is mentioned here too"})
+	createTestFile(t, scripts.File{Name: "meeting.md", Title: "Meeting", CreatedAt: time.Now(), Content: "See [[Payments Plan|the roadmap]]\nThe payments plan slipped"})
+	createTestFile(t, scripts.File{Name: "retro.md", Title: "Retro", CreatedAt: time.Now(), Content: "Check the roadmap"})
+
+	mentions, err := FindUnlinkedMentions("plan.md")
+	if err != nil {
+		t.Fatalf("FindUnlinkedMentions failed: %v", err)
+	}
+
+	if len(mentions) != 2 {
+		t.Fatalf("Expected 2 mentions, got %d: %+v", len(mentions), mentions)
+	}
+	if mentions[0].File.Name != "meeting.md" || mentions[0].Text != "payments plan" {
+		t.Errorf("Expected the title mention in meeting.md, got %+v", mentions[0])
+	}
+	if mentions[1].File.Name != "retro.md" || mentions[1].Text != "the roadmap" {
+		t.Errorf("Expected the alias mention in retro.md, got %+v", mentions[1])
+	}
+}
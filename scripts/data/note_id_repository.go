@@ -0,0 +1,201 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// frontmatterIDLine returns the index of the closing frontmatter delimiter and the
+// note ID found before it, or -1 when the content has no frontmatter
+func frontmatterIDLine(lines []string) (int, string) {
+	if len(lines) == 0 || lines[0] != "---" {
+		return -1, ""
+	}
+
+	id := ""
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "---" {
+			return i, id
+		}
+
+		parts := strings.SplitN(lines[i], ":", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == "id" {
+			id = strings.TrimSpace(parts[1])
+		}
+	}
+
+	return -1, ""
+}
+
+// readNoteID returns the ID stored in the frontmatter of a note on disk, if any
+func readNoteID(filePath string) string {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return ""
+	}
+
+	_, id := frontmatterIDLine(strings.Split(string(content), "\n"))
+	return id
+}
+
+// noteIDSet is the note IDs in use in the vault, read the first time a new ID is needed
+type noteIDSet struct {
+	notesPath string
+	ids       map[string]bool
+}
+
+func newNoteIDSet(notesPath string) *noteIDSet {
+	return &noteIDSet{notesPath: notesPath}
+}
+
+// generate returns an ID no note has yet and counts it as taken
+func (s *noteIDSet) generate() (string, error) {
+	if s.ids == nil {
+		ids, err := readNoteIDs(s.notesPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read note IDs: %w", err)
+		}
+		s.ids = ids
+	}
+
+	id, err := scripts.GenerateNoteID(func(id string) bool { return s.ids[id] })
+	if err != nil {
+		return "", fmt.Errorf("failed to generate note ID: %w", err)
+	}
+	s.ids[id] = true
+	return id, nil
+}
+
+// readNoteIDs returns the IDs of the notes under a directory
+func readNoteIDs(notesPath string) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	if _, err := os.Stat(notesPath); os.IsNotExist(err) {
+		return ids, nil
+	}
+
+	err := filepath.Walk(notesPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+		if id := readNoteID(path); id != "" {
+			ids[id] = true
+		}
+		return nil
+	})
+	return ids, err
+}
+
+// assignNoteID keeps the ID a note already has on disk, or generates a new one
+func assignNoteID(file scripts.File, filePath string, ids *noteIDSet) (scripts.File, error) {
+	if file.ID != "" {
+		return file, nil
+	}
+
+	if id := readNoteID(filePath); id != "" {
+		file.ID = id
+		return file, nil
+	}
+
+	id, err := ids.generate()
+	if err != nil {
+		return file, err
+	}
+	file.ID = id
+	return file, nil
+}
+
+// ensureNoteIDAtPath adds an id line to the frontmatter of a note that has none
+// The rest of the file is left untouched
+// Returns the ID of the note and whether it was added
+func ensureNoteIDAtPath(filePath string, ids *noteIDSet) (string, bool, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", false, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	closing, id := frontmatterIDLine(lines)
+	if closing < 0 {
+		return "", false, fmt.Errorf("%s has no frontmatter", filepath.Base(filePath))
+	}
+	if id != "" {
+		return id, false, nil
+	}
+
+	id, err = ids.generate()
+	if err != nil {
+		return "", false, err
+	}
+
+	updated := make([]string, 0, len(lines)+1)
+	updated = append(updated, lines[:closing]...)
+	updated = append(updated, "id: "+id)
+	updated = append(updated, lines[closing:]...)
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", false, err
+	}
+	if err := os.WriteFile(filePath, []byte(strings.Join(updated, "\n")), info.Mode().Perm()); err != nil {
+		return "", false, err
+	}
+
+	return id, true, nil
+}
+
+// EnsureNoteID returns the ID of a note, adding one to its frontmatter if it has none
+func EnsureNoteID(fileName string) (string, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	notesPath := filepath.Join(currentDir, DirectoryPath)
+	id, _, err := ensureNoteIDAtPath(filepath.Join(notesPath, fileName), newNoteIDSet(notesPath))
+	return id, err
+}
+
+// MigrateNoteIDs adds an ID to every note that does not have one yet
+// Notes without frontmatter are skipped
+// Returns the number of notes that were given an ID
+func MigrateNoteIDs() (int, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return 0, err
+	}
+
+	notesPath := filepath.Join(currentDir, DirectoryPath)
+	if _, err := os.Stat(notesPath); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	ids := newNoteIDSet(notesPath)
+	migrated := 0
+	err = filepath.Walk(notesPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+
+		_, added, err := ensureNoteIDAtPath(path, ids)
+		if err != nil {
+			return nil // Skip notes without frontmatter
+		}
+		if added {
+			migrated++
+		}
+
+		return nil
+	})
+
+	return migrated, err
+}
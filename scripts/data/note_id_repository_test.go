@@ -0,0 +1,86 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteFile_AssignsAndKeepsNoteID(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "note.md", Title: "Note", CreatedAt: time.Now()})
+
+	file, err := LoadFileByName("note.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+	if !scripts.ValidateNoteID(file.ID) {
+		t.Fatalf("Expected a valid note ID, got '%s'", file.ID)
+	}
+
+	// Writing a struct without an ID keeps the one already on disk
+	if err := WriteFile(scripts.File{Name: "note.md", Title: "Note", Content: "Updated"}); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	updated, err := LoadFileByName("note.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+	if updated.ID != file.ID {
+		t.Errorf("Expected ID %s to be kept, got %s", file.ID, updated.ID)
+	}
+}
+
+func TestMigrateNoteIDs(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	legacy := "---\ntitle: Legacy\ntags: [todo]\ncustom: kept\n---\n\nBody\n"
+	files := map[string]string{
+		"legacy.md":   legacy,
+		"has-id.md":   "---\ntitle: Has ID\nid: abcd1234\n---\n\nBody\n",
+		"no-front.md": "Just text\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(DirectoryPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrated, err := MigrateNoteIDs()
+	if err != nil {
+		t.Fatalf("MigrateNoteIDs failed: %v", err)
+	}
+	if migrated != 1 {
+		t.Errorf("Expected 1 migrated note, got %d", migrated)
+	}
+
+	file, err := LoadFileByName("legacy.md")
+	if err != nil {
+		t.Fatalf("LoadFileByName failed: %v", err)
+	}
+	if !scripts.ValidateNoteID(file.ID) {
+		t.Fatalf("Expected a valid note ID, got '%s'", file.ID)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(DirectoryPath, "legacy.md"))
+	expected := "---\ntitle: Legacy\ntags: [todo]\ncustom: kept\nid: " + file.ID + "\n---\n\nBody\n"
+	if string(content) != expected {
+		t.Errorf("Expected only an id line to be added, got '%s'", content)
+	}
+
+	unchanged, _ := os.ReadFile(filepath.Join(DirectoryPath, "has-id.md"))
+	if string(unchanged) != files["has-id.md"] {
+		t.Errorf("Expected note with an ID to be unchanged, got '%s'", unchanged)
+	}
+
+	// Running again changes nothing
+	if migrated, _ := MigrateNoteIDs(); migrated != 0 {
+		t.Errorf("Expected second migration to be a no-op, got %d", migrated)
+	}
+}
@@ -0,0 +1,90 @@
+package data
+
+import (
+	"time"
+)
+
+// ObjectivesDraft is where the objectives view was: the open objective, the selection and how children
+// were sorted and filtered. Edits made in the view are written to the notes at once, so this is all it loses
+type ObjectivesDraft struct {
+	ViewMode           ObjectivesViewMode `json:"view_mode"`
+	ListFilterMode     ListFilterMode     `json:"list_filter_mode"`
+	SelectedIndex      int                `json:"selected_index"`
+	Objective          string             `json:"objective,omitempty"` // Note name of the open objective
+	ObjectiveID        string             `json:"objective_id,omitempty"`
+	ChildSelectedIndex int                `json:"child_selected_index"`
+	OnParent           bool               `json:"on_parent"`
+	SortOrder          SortOrder          `json:"sort_order"`
+	FilterMode         FilterMode         `json:"filter_mode"`
+	SavedAt            time.Time          `json:"-"` // When the draft was last written, set when it is loaded
+}
+
+// Draft returns where the view is
+func (ovs *ObjectivesViewState) Draft() *ObjectivesDraft {
+	draft := &ObjectivesDraft{
+		ViewMode:           ovs.ViewMode,
+		ListFilterMode:     ovs.ListFilterMode,
+		SelectedIndex:      ovs.SelectedIndex,
+		ChildSelectedIndex: ovs.ChildSelectedIndex,
+		OnParent:           ovs.OnParent,
+		SortOrder:          ovs.SortOrder,
+		FilterMode:         ovs.FilterMode,
+	}
+	if ovs.CurrentObjective != nil {
+		draft.Objective = ovs.CurrentObjective.Name
+		draft.ObjectiveID = ovs.CurrentObjective.ObjectiveID
+	}
+	return draft
+}
+
+// LoadObjectivesDraft reads the draft left by an earlier session, an os.IsNotExist error when there is none
+func LoadObjectivesDraft() (*ObjectivesDraft, error) {
+	var draft ObjectivesDraft
+	savedAt, err := readDraft(ObjectivesDraftName, &draft)
+	if err != nil {
+		return nil, err
+	}
+	draft.SavedAt = savedAt
+	return &draft, nil
+}
+
+// ResumeObjectivesViewState opens the objectives view where the draft left it
+// The draft is stale when its objective is gone or no longer the same objective; the list opens
+// instead and true is returned
+func ResumeObjectivesViewState(draft *ObjectivesDraft) (*ObjectivesViewState, bool, error) {
+	if draft.ViewMode == SingleObjectiveView {
+		objective, err := LoadFileByName(draft.Objective)
+		if err == nil && objective.ObjectiveRole == "parent" && objective.ObjectiveID == draft.ObjectiveID {
+			state, err := NewSingleObjectiveViewStateForObjective(objective)
+			if err != nil {
+				return nil, false, err
+			}
+			state.SortOrder = draft.SortOrder
+			state.FilterMode = draft.FilterMode
+			state.applySortAndFilter()
+			state.OnParent = draft.OnParent || len(state.Children) == 0
+			state.ChildSelectedIndex = clampIndex(draft.ChildSelectedIndex, len(state.Children))
+			return state, false, nil
+		}
+	}
+
+	state, err := NewObjectivesViewState()
+	if err != nil {
+		return nil, false, err
+	}
+	state.ListFilterMode = draft.ListFilterMode
+	state.applyListFilter()
+	state.SelectedIndex = clampIndex(draft.SelectedIndex, len(state.Objectives))
+	return state, draft.ViewMode == SingleObjectiveView, nil
+}
+
+// clampIndex keeps a selection within a list of length items
+func clampIndex(index, length int) int {
+	if index >= length {
+		index = length - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	return index
+}
@@ -0,0 +1,50 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"testing"
+	"time"
+)
+
+func TestResumeObjectivesViewState_ReopensTheObjective(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	objective := scripts.File{Name: "launch.md", Title: "Launch", Tags: []string{"objective"}, CreatedAt: time.Now(), ObjectiveRole: "parent", ObjectiveID: "abcd1234"}
+	createTestFile(t, objective)
+	createTestFile(t, scripts.File{Name: "spec.md", Title: "Spec", Tags: []string{"todo"}, CreatedAt: time.Now(), DueAt: time.Now(), ObjectiveID: "abcd1234"})
+	createTestFile(t, scripts.File{Name: "review.md", Title: "Review", Tags: []string{"todo"}, CreatedAt: time.Now(), DueAt: time.Now(), ObjectiveID: "abcd1234", Done: true})
+
+	state, stale, err := ResumeObjectivesViewState(&ObjectivesDraft{
+		ViewMode:           SingleObjectiveView,
+		Objective:          "launch.md",
+		ObjectiveID:        "abcd1234",
+		ChildSelectedIndex: 5,
+		FilterMode:         ShowIncompleteOnly,
+	})
+	if err != nil {
+		t.Fatalf("ResumeObjectivesViewState failed: %v", err)
+	}
+	if stale || state.ViewMode != SingleObjectiveView || state.CurrentObjective.Name != "launch.md" {
+		t.Fatalf("Expected launch.md to be open again, got %+v (stale %v)", state, stale)
+	}
+	if len(state.Children) != 1 || state.Children[0].Name != "spec.md" {
+		t.Errorf("Expected the filter to be kept, got %v", state.Children)
+	}
+	if state.ChildSelectedIndex != 0 {
+		t.Errorf("Expected the selection to be kept within the children, got %d", state.ChildSelectedIndex)
+	}
+}
+
+func TestResumeObjectivesViewState_OpensTheListForAGoneObjective(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	state, stale, err := ResumeObjectivesViewState(&ObjectivesDraft{ViewMode: SingleObjectiveView, Objective: "launch.md", ObjectiveID: "abcd1234"})
+	if err != nil {
+		t.Fatalf("ResumeObjectivesViewState failed: %v", err)
+	}
+	if !stale || state.ViewMode != ObjectivesListView {
+		t.Errorf("Expected the list for a gone objective, got mode %v (stale %v)", state.ViewMode, stale)
+	}
+}
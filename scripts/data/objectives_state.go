@@ -50,6 +50,8 @@ type ObjectivesViewState struct {
 	OnParent           bool // True if parent is selected, false if child
 	SortOrder          SortOrder
 	FilterMode         FilterMode
+
+	embedResolver scripts.EmbedResolver // Resolves ![[note]] embeds against the notes on disk, built on first use
 }
 
 // NewObjectivesViewState initializes the objectives list view
@@ -215,6 +217,7 @@ func (ovs *ObjectivesViewState) BackToList() error {
 
 // Refresh reloads current view
 func (ovs *ObjectivesViewState) Refresh() error {
+	ovs.embedResolver = nil
 	if ovs.ViewMode == ObjectivesListView {
 		objectives, err := QueryAllObjectives()
 		if err != nil {
@@ -241,6 +244,22 @@ func (ovs *ObjectivesViewState) Refresh() error {
 	return nil
 }
 
+// ExpandEmbeds returns the content of a note with its ![[note]] embeds expanded
+// The notes are loaded once until the next Refresh; the raw content is returned when they can't be
+func (ovs *ObjectivesViewState) ExpandEmbeds(file scripts.File) string {
+	if !scripts.HasEmbeds(file.Content) {
+		return file.Content
+	}
+	if ovs.embedResolver == nil {
+		index, err := loadLinkIndexFiles()
+		if err != nil {
+			return file.Content
+		}
+		ovs.embedResolver = index.resolveEmbed
+	}
+	return scripts.ExpandEmbeds(file, ovs.embedResolver)
+}
+
 // applySortAndFilter applies current sort and filter settings to children
 func (ovs *ObjectivesViewState) applySortAndFilter() {
 	// Separate into incomplete and complete first
@@ -0,0 +1,30 @@
+package data
+
+import (
+	"cli-notes/scripts"
+)
+
+// NewRelatedIndex ranks the given notes against each other, resolving links among them
+func NewRelatedIndex(files []scripts.File) *scripts.RelatedIndex {
+	index := newLinkIndexFromFiles(files)
+	return scripts.NewRelatedIndex(files, index.OutLinks)
+}
+
+// FindRelatedNotes returns up to limit notes most related to a note, from every note on disk
+func FindRelatedNotes(fileName string, limit int) ([]scripts.RelatedNote, error) {
+	if _, err := LoadFileByName(fileName); err != nil {
+		return nil, err
+	}
+
+	index, err := BuildLinkIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]scripts.File, 0, len(index.FilesByName))
+	for _, file := range index.FilesByName {
+		files = append(files, file)
+	}
+
+	return scripts.NewRelatedIndex(files, index.OutLinks).Related(fileName, limit), nil
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"testing"
+	"time"
+)
+
+func TestFindRelatedNotes_UsesLinksOnDisk(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "payments.md", Title: "Payments", CreatedAt: time.Now(), Content: "See [[Roadmap]]"})
+	createTestFile(t, scripts.File{Name: "android.md", Title: "Android", CreatedAt: time.Now(), Content: "Also [[Roadmap]]"})
+	createTestFile(t, scripts.File{Name: "roadmap.md", Title: "Roadmap", CreatedAt: time.Now(), Content: "quarters"})
+
+	related, err := FindRelatedNotes("payments.md", 5)
+	if err != nil {
+		t.Fatalf("FindRelatedNotes failed: %v", err)
+	}
+	if len(related) != 1 || related[0].File.Name != "android.md" || related[0].SharedLinks != 1 {
+		t.Errorf("Expected android.md through the shared roadmap link, got %+v", related)
+	}
+
+	if _, err := FindRelatedNotes("missing.md", 5); err == nil {
+		t.Error("Expected an error for a missing note")
+	}
+}
+
+func TestSearchState_RelatedNotesAction(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "payments.md", Title: "Payments rollout", CreatedAt: time.Now(), Content: "payments android"})
+	createTestFile(t, scripts.File{Name: "android.md", Title: "Android payments", CreatedAt: time.Now(), Content: "payments screen"})
+
+	state, err := NewSearchState("rollout")
+	if err != nil {
+		t.Fatalf("NewSearchState failed: %v", err)
+	}
+
+	related := state.RelatedNotes(state.GetSelectedResult().File)
+	if len(related) != 1 || related[0].File.Name != "android.md" {
+		t.Fatalf("Expected android.md to be related, got %+v", related)
+	}
+
+	found := false
+	for _, action := range state.GetAvailableActions() {
+		if action.Key == 'r' && action.Description == "Link to Android payments" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a link related note action, got %+v", state.GetAvailableActions())
+	}
+}
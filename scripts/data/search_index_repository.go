@@ -0,0 +1,147 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileStamp identifies a version of a note file, so unchanged notes are not indexed again
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// searchIndexCache is the search index of a notes directory with the file versions it holds
+type searchIndexCache struct {
+	notesPath string
+	index     *scripts.SearchIndex
+	stamps    map[string]fileStamp
+}
+
+var (
+	searchCacheMu sync.Mutex
+	searchCache   *searchIndexCache
+)
+
+// LoadSearchIndex returns the search index of every note, updated for the notes that were
+// added, changed or deleted since it was last loaded. Only those notes are read again
+func LoadSearchIndex() (*scripts.SearchIndex, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+	notesPath := filepath.Join(currentDir, DirectoryPath)
+
+	searchCacheMu.Lock()
+	defer searchCacheMu.Unlock()
+
+	if searchCache == nil || searchCache.notesPath != notesPath {
+		searchCache = &searchIndexCache{
+			notesPath: notesPath,
+			index:     scripts.NewSearchIndex(),
+			stamps:    make(map[string]fileStamp),
+		}
+	}
+
+	seen := make(map[string]bool, len(searchCache.stamps))
+	err = filepath.Walk(notesPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+
+		name := info.Name()
+		seen[name] = true
+		stamp := fileStamp{modTime: info.ModTime(), size: info.Size()}
+		if cached, exists := searchCache.stamps[name]; exists && cached == stamp {
+			return nil
+		}
+
+		file, err := LoadFileByName(name)
+		if err != nil {
+			return nil // Skip files that can't be loaded
+		}
+		searchCache.index.Add(file)
+		searchCache.stamps[name] = stamp
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking through files: %w", err)
+	}
+
+	for name := range searchCache.stamps {
+		if !seen[name] {
+			searchCache.index.Remove(name)
+			delete(searchCache.stamps, name)
+		}
+	}
+
+	return searchCache.index, nil
+}
+
+// ResetSearchIndex drops the search index, so the next LoadSearchIndex reads every note again
+func ResetSearchIndex() {
+	searchCacheMu.Lock()
+	defer searchCacheMu.Unlock()
+
+	searchCache = nil
+}
+
+// InvalidateSearchIndex makes the next LoadSearchIndex read the named notes again,
+// even when an edit kept their size and modification time
+func InvalidateSearchIndex(names []string) {
+	searchCacheMu.Lock()
+	defer searchCacheMu.Unlock()
+
+	if searchCache == nil {
+		return
+	}
+	for _, name := range names {
+		delete(searchCache.stamps, name)
+	}
+}
+
+// SearchNotes returns the notes matching every query, most relevant first
+// Queries with a colon, like "done: false", match frontmatter lines as before and only filter
+func SearchNotes(queries []string) ([]scripts.File, error) {
+	textQueries := make([]string, 0, len(queries))
+	var allowed map[string]bool
+	for _, query := range queries {
+		if !strings.Contains(query, ":") {
+			textQueries = append(textQueries, query)
+			continue
+		}
+
+		files, err := QueryFiles(query)
+		if err != nil {
+			return nil, err
+		}
+		matched := make(map[string]bool, len(files))
+		for _, file := range files {
+			if allowed == nil || allowed[file.Name] {
+				matched[file.Name] = true
+			}
+		}
+		allowed = matched
+	}
+
+	index, err := LoadSearchIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]scripts.File, 0)
+	for _, hit := range index.Search(strings.Join(textQueries, ",")) {
+		if allowed == nil || allowed[hit.File.Name] {
+			files = append(files, hit.File)
+		}
+	}
+	return files, nil
+}
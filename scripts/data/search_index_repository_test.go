@@ -0,0 +1,120 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSearchIndex_FollowsChangesOnDisk(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "standup.md", Title: "Standup", CreatedAt: time.Now(), Content: "payments release"})
+	createTestFile(t, scripts.File{Name: "retro.md", Title: "Retro", CreatedAt: time.Now(), Content: "went well"})
+
+	index, err := LoadSearchIndex()
+	if err != nil {
+		t.Fatalf("LoadSearchIndex failed: %v", err)
+	}
+	if index.Len() != 2 || len(index.Search("payments")) != 1 {
+		t.Fatalf("Expected both notes indexed, got %d notes", index.Len())
+	}
+
+	createTestFile(t, scripts.File{Name: "standup.md", Title: "Standup", CreatedAt: time.Now(), Content: "blockers and risks"})
+	if err := os.Remove(filepath.Join(DirectoryPath, "retro.md")); err != nil {
+		t.Fatalf("Failed to remove note: %v", err)
+	}
+
+	index, err = LoadSearchIndex()
+	if err != nil {
+		t.Fatalf("LoadSearchIndex failed: %v", err)
+	}
+	if index.Len() != 1 {
+		t.Errorf("Expected the deleted note to be dropped, got %d notes", index.Len())
+	}
+	if len(index.Search("payments")) != 0 || len(index.Search("risk")) != 1 {
+		t.Error("Expected the changed note to be indexed again")
+	}
+}
+
+func TestSearchNotes_RanksAndFiltersFrontmatter(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "budget.md", Title: "Budget", CreatedAt: time.Now(), Content: "Quarterly budget review", Done: true})
+	createTestFile(t, scripts.File{Name: "review.md", Title: "Review", CreatedAt: time.Now(), Content: "Look at the budget"})
+
+	files, err := SearchNotes([]string{"budget"})
+	if err != nil {
+		t.Fatalf("SearchNotes failed: %v", err)
+	}
+	if len(files) != 2 || files[0].Name != "budget.md" {
+		t.Errorf("Expected the title match first, got %+v", files)
+	}
+
+	files, err = SearchNotes([]string{"budget", "done: false"})
+	if err != nil {
+		t.Fatalf("SearchNotes failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "review.md" {
+		t.Errorf("Expected the frontmatter query to filter, got %+v", files)
+	}
+}
+
+func TestInvalidateSearchIndex_ReadsNoteWithSameStampAgain(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "standup.md", Title: "Standup", CreatedAt: time.Now(), Content: "payments"})
+	if _, err := LoadSearchIndex(); err != nil {
+		t.Fatalf("LoadSearchIndex failed: %v", err)
+	}
+
+	// Same size and modification time, as when a sync tool keeps the time of an edit
+	path := filepath.Join(DirectoryPath, "standup.md")
+	info, _ := os.Stat(path)
+	createTestFile(t, scripts.File{Name: "standup.md", Title: "Standup", CreatedAt: time.Now(), Content: "blockers"})
+	os.Chtimes(path, info.ModTime(), info.ModTime())
+
+	index, _ := LoadSearchIndex()
+	if len(index.Search("blockers")) != 0 {
+		t.Fatal("Expected the edit to go unnoticed without invalidation")
+	}
+
+	InvalidateSearchIndex([]string{"standup.md"})
+	index, err := LoadSearchIndex()
+	if err != nil {
+		t.Fatalf("LoadSearchIndex failed: %v", err)
+	}
+	if len(index.Search("blockers")) != 1 || len(index.Search("payments")) != 0 {
+		t.Error("Expected the invalidated note to be indexed again")
+	}
+}
+
+func TestResetSearchIndex_ReadsEveryNoteAgain(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "standup.md", Title: "Standup", CreatedAt: time.Now(), Content: "payments"})
+	if _, err := LoadSearchIndex(); err != nil {
+		t.Fatalf("LoadSearchIndex failed: %v", err)
+	}
+
+	// An edit the watcher missed, keeping the size and modification time
+	path := filepath.Join(DirectoryPath, "standup.md")
+	info, _ := os.Stat(path)
+	createTestFile(t, scripts.File{Name: "standup.md", Title: "Standup", CreatedAt: time.Now(), Content: "blockers"})
+	os.Chtimes(path, info.ModTime(), info.ModTime())
+
+	ResetSearchIndex()
+	index, err := LoadSearchIndex()
+	if err != nil {
+		t.Fatalf("LoadSearchIndex failed: %v", err)
+	}
+	if len(index.Search("blockers")) != 1 || len(index.Search("payments")) != 0 {
+		t.Error("Expected every note to be indexed again")
+	}
+}
@@ -13,21 +13,23 @@ const (
 	SearchModeInsert  SearchViewMode = iota // User is typing search query (all chars go to query)
 	SearchModeNormal                        // Command mode (j/k navigate, shortcuts work)
 	SearchModeActions                       // Quick actions menu is shown
+	SearchModeTagTree                       // Tag tree filter picker is shown
 )
 
 type SearchMatchMode int
 
 const (
 	MatchModeFuzzy  SearchMatchMode = iota // Fuzzy matching (letters in sequence)
-	MatchModeStrict                        // Strict substring matching
+	MatchModeStrict                        // Whole words and phrases, ranked by relevance
 )
 
 // SearchResult represents a search match with context
 type SearchResult struct {
 	File           scripts.File
-	MatchedIndices []int  // Character indices that matched in title
-	ContentSnippet string // Matched content excerpt
-	SnippetLine    int    // Line number where snippet was found
+	MatchedIndices []int    // Character indices that matched in title
+	ContentSnippet string   // Matched content excerpt
+	SnippetLine    int      // Line number where snippet was found
+	Section        []string // Heading or block section targeted by a [[link]] query
 }
 
 // QuickAction represents an action that can be performed on a search result
@@ -40,14 +42,19 @@ type QuickAction struct {
 // SearchState holds all state for the interactive search view
 type SearchState struct {
 	ViewMode      SearchViewMode
-	Query         string         // Current search query
-	AllNotes      []scripts.File // All loaded notes
-	Results       []SearchResult // Fuzzy-matched results
-	SelectedIndex int            // Currently selected result
-	ScrollOffset  int            // For scrolling through results
-	ActionsIndex  int            // Selected action in actions menu
-	FilterMode    FilterMode     // Show all/incomplete only/complete only
+	Query         string          // Current search query
+	AllNotes      []scripts.File  // All loaded notes
+	Results       []SearchResult  // Fuzzy-matched results
+	SelectedIndex int             // Currently selected result
+	ScrollOffset  int             // For scrolling through results
+	ActionsIndex  int             // Selected action in actions menu
+	FilterMode    FilterMode      // Show all/incomplete only/complete only
 	MatchMode     SearchMatchMode // Fuzzy or strict matching
+	TagFilter     string          // Only show notes with this tag or a tag nested under it
+
+	// Tag tree picker
+	TagTree      []scripts.TagTreeNode // All tags, flattened in display order
+	TagTreeIndex int                   // Selected node in the tag tree
 
 	// UI dimensions (set during render)
 	TermWidth  int
@@ -56,8 +63,15 @@ type SearchState struct {
 	// Link mode fields
 	LinkSourceFileName string        // Source file for ln command flow (linking from this file)
 	PendingLinkSource  *scripts.File // For GS two-note selection flow (first note selected with 'l')
+
+	embedResolver scripts.EmbedResolver // Resolves ![[note]] embeds against AllNotes, built on first use
+	relatedIndex  *scripts.RelatedIndex // Ranks related notes among AllNotes, built on first use
+	searchIndex   *scripts.SearchIndex  // Ranks notes for strict queries, shared with gq
 }
 
+// PreviewRelatedLimit is how many related notes the search preview shows
+const PreviewRelatedLimit = 3
+
 // NewSearchState initializes search state with all notes
 func NewSearchState(initialQuery string) (*SearchState, error) {
 	// Query all notes (empty string returns all)
@@ -66,6 +80,11 @@ func NewSearchState(initialQuery string) (*SearchState, error) {
 		return nil, err
 	}
 
+	searchIndex, err := LoadSearchIndex()
+	if err != nil {
+		return nil, err
+	}
+
 	state := &SearchState{
 		ViewMode:      SearchModeInsert, // Start in insert mode for immediate typing
 		Query:         initialQuery,
@@ -75,7 +94,8 @@ func NewSearchState(initialQuery string) (*SearchState, error) {
 		ScrollOffset:  0,
 		ActionsIndex:  0,
 		FilterMode:    ShowIncompleteOnly, // Default to showing incomplete notes
-		MatchMode:     MatchModeStrict,    // Default to strict substring matching
+		MatchMode:     MatchModeStrict,    // Default to strict word matching
+		searchIndex:   searchIndex,
 	}
 
 	// Perform initial search if query provided
@@ -94,7 +114,7 @@ func NewSearchStateWithLinkMode(linkSourceFileName string) (*SearchState, error)
 	return state, nil
 }
 
-// UpdateQuery performs fuzzy search and updates results
+// UpdateQuery searches the notes with the current match mode and updates results
 func (s *SearchState) UpdateQuery(query string) {
 	s.Query = query
 	s.SelectedIndex = 0
@@ -102,7 +122,10 @@ func (s *SearchState) UpdateQuery(query string) {
 
 	var candidates []SearchResult
 
-	if query == "" {
+	if linkText, ok := parseLinkQuery(query); ok {
+		// A [[link]] query shows the notes it points at, with the targeted section
+		candidates = s.linkCandidates(linkText)
+	} else if query == "" {
 		// Show all notes when no query
 		candidates = make([]SearchResult, len(s.AllNotes))
 		for i, note := range s.AllNotes {
@@ -113,6 +136,8 @@ func (s *SearchState) UpdateQuery(query string) {
 				SnippetLine:    0,
 			}
 		}
+	} else if s.MatchMode == MatchModeStrict {
+		candidates = s.rankedCandidates(query)
 	} else {
 		// Split query by comma for AND logic (like gt command)
 		queries := strings.Split(query, ",")
@@ -130,19 +155,7 @@ func (s *SearchState) UpdateQuery(query string) {
 		for i, note := range s.AllNotes {
 			matchCount := 0
 			for _, q := range queries {
-				if q == "" {
-					matchCount++
-					continue
-				}
-				qLower := strings.ToLower(q)
-				var matched bool
-				if s.MatchMode == MatchModeFuzzy {
-					matches := fuzzy.Find(qLower, []string{searchTargets[i]})
-					matched = len(matches) > 0
-				} else {
-					matched = strings.Contains(searchTargets[i], qLower)
-				}
-				if matched {
+				if q == "" || len(fuzzy.Find(strings.ToLower(q), []string{searchTargets[i]})) > 0 {
 					matchCount++
 				}
 			}
@@ -159,12 +172,77 @@ func (s *SearchState) UpdateQuery(query string) {
 		}
 	}
 
-	// Apply filter mode (all/incomplete/complete)
-	s.Results = s.applyFilterMode(candidates)
+	// Apply filter mode (all/incomplete/complete) and tag filter
+	s.Results = s.applyTagFilter(s.applyFilterMode(candidates))
+}
+
+// Refresh reloads the notes and the search index from disk and runs the query again,
+// keeping the selected note selected while it is still in the results
+func (s *SearchState) Refresh() error {
+	notes, err := QueryFiles("")
+	if err != nil {
+		return err
+	}
+	searchIndex, err := LoadSearchIndex()
+	if err != nil {
+		return err
+	}
+
+	selectedName := ""
+	if selected := s.GetSelectedResult(); selected != nil {
+		selectedName = selected.File.Name
+	}
+
+	s.AllNotes = notes
+	s.searchIndex = searchIndex
+	s.embedResolver = nil
+	s.relatedIndex = nil
+	s.UpdateQuery(s.Query)
+
+	for i, result := range s.Results {
+		if result.File.Name == selectedName {
+			s.SelectedIndex = i
+			s.adjustScrollOffset()
+			break
+		}
+	}
+	return nil
+}
+
+// rankedCandidates returns the notes matching a strict query from the search index, most relevant first
+func (s *SearchState) rankedCandidates(query string) []SearchResult {
+	notesByName := make(map[string]scripts.File, len(s.AllNotes))
+	for _, note := range s.AllNotes {
+		notesByName[note.Name] = note
+	}
+
+	snippetQuery := strings.TrimSpace(strings.ReplaceAll(strings.Split(query, ",")[0], `"`, ""))
+	candidates := make([]SearchResult, 0)
+	for _, hit := range s.searchIndex.Search(query) {
+		note, exists := notesByName[hit.File.Name]
+		if !exists {
+			continue
+		}
+		snippet, lineNum := extractSnippetWithQuery(note.Content, snippetQuery, 80)
+		candidates = append(candidates, SearchResult{
+			File:           note,
+			MatchedIndices: []int{},
+			ContentSnippet: snippet,
+			SnippetLine:    lineNum,
+		})
+	}
+	return candidates
 }
 
 // SelectNext moves selection down
 func (s *SearchState) SelectNext() {
+	if s.ViewMode == SearchModeTagTree {
+		if len(s.TagTree) > 0 {
+			s.TagTreeIndex = (s.TagTreeIndex + 1) % len(s.TagTree)
+		}
+		return
+	}
+
 	if s.ViewMode == SearchModeActions {
 		actions := s.GetAvailableActions()
 		if len(actions) > 0 {
@@ -181,6 +259,16 @@ func (s *SearchState) SelectNext() {
 
 // SelectPrevious moves selection up
 func (s *SearchState) SelectPrevious() {
+	if s.ViewMode == SearchModeTagTree {
+		if len(s.TagTree) > 0 {
+			s.TagTreeIndex--
+			if s.TagTreeIndex < 0 {
+				s.TagTreeIndex = len(s.TagTree) - 1
+			}
+		}
+		return
+	}
+
 	if s.ViewMode == SearchModeActions {
 		actions := s.GetAvailableActions()
 		if len(actions) > 0 {
@@ -294,6 +382,54 @@ func (s *SearchState) applyFilterMode(candidates []SearchResult) []SearchResult
 	return filtered
 }
 
+// applyTagFilter keeps only results tagged with the tag filter or a tag nested under it
+func (s *SearchState) applyTagFilter(candidates []SearchResult) []SearchResult {
+	if s.TagFilter == "" {
+		return candidates
+	}
+
+	filtered := make([]SearchResult, 0, len(candidates))
+	for _, result := range candidates {
+		if scripts.HasMatchingTag(result.File.Tags, s.TagFilter) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// EnterTagTreeMode shows the tag tree picker, starting at the current tag filter
+func (s *SearchState) EnterTagTreeMode() {
+	s.TagTree = scripts.BuildTagTree(s.AllNotes)
+	if len(s.TagTree) == 0 {
+		return
+	}
+
+	s.TagTreeIndex = 0
+	for i, node := range s.TagTree {
+		if node.Tag == s.TagFilter {
+			s.TagTreeIndex = i
+			break
+		}
+	}
+	s.ViewMode = SearchModeTagTree
+}
+
+// ApplySelectedTagFilter filters results by the selected tag and closes the picker
+func (s *SearchState) ApplySelectedTagFilter() {
+	if s.TagTreeIndex < len(s.TagTree) {
+		s.TagFilter = s.TagTree[s.TagTreeIndex].Tag
+	}
+	s.ViewMode = SearchModeNormal
+	s.UpdateQuery(s.Query)
+}
+
+// ClearTagFilter removes the tag filter and closes the picker
+func (s *SearchState) ClearTagFilter() {
+	s.TagFilter = ""
+	s.ViewMode = SearchModeNormal
+	s.UpdateQuery(s.Query)
+}
+
 // GetAvailableActions returns quick actions for selected result
 func (s *SearchState) GetAvailableActions() []QuickAction {
 	result := s.GetSelectedResult()
@@ -328,6 +464,11 @@ func (s *SearchState) GetAvailableActions() []QuickAction {
 		actions = append(actions, QuickAction{Label: "Link to objective", Description: "Associate with an objective", Key: 'o'})
 	}
 
+	// Link the most related note
+	if related := s.RelatedNotes(result.File); len(related) > 0 {
+		actions = append(actions, QuickAction{Label: "Link related note", Description: "Link to " + related[0].File.Title, Key: 'r'})
+	}
+
 	// Open graph view
 	actions = append(actions, QuickAction{Label: "Open graph view", Description: "View linked notes", Key: 'L'})
 
@@ -397,6 +538,75 @@ func (s *SearchState) GetPendingLinkSourceTitle() string {
 	return ""
 }
 
+// ExpandEmbeds returns the content of a note with its ![[note]] embeds expanded from AllNotes
+func (s *SearchState) ExpandEmbeds(file scripts.File) string {
+	if !scripts.HasEmbeds(file.Content) {
+		return file.Content
+	}
+	if s.embedResolver == nil {
+		s.embedResolver = NewEmbedResolver(s.AllNotes)
+	}
+	return scripts.ExpandEmbeds(file, s.embedResolver)
+}
+
+// RelatedNotes returns the notes most related to a note, for the preview
+func (s *SearchState) RelatedNotes(file scripts.File) []scripts.RelatedNote {
+	if s.relatedIndex == nil {
+		s.relatedIndex = NewRelatedIndex(s.AllNotes)
+	}
+	return s.relatedIndex.Related(file.Name, PreviewRelatedLimit)
+}
+
+// parseLinkQuery returns the link text of a query written as [[link]]
+func parseLinkQuery(query string) (string, bool) {
+	query = strings.TrimSpace(query)
+	if !strings.HasPrefix(query, "[[") || !strings.HasSuffix(query, "]]") || len(query) <= 4 {
+		return "", false
+	}
+	return strings.TrimSpace(query[2 : len(query)-2]), true
+}
+
+// linkCandidates returns the notes a link points at, by ID, filename or title
+// Heading and block links carry the section they point at
+func (s *SearchState) linkCandidates(linkText string) []SearchResult {
+	id, name := scripts.ParseLinkTarget(linkText)
+	anchor, _ := scripts.ParseLinkAnchor(linkText)
+
+	candidates := make([]SearchResult, 0)
+	for _, note := range s.AllNotes {
+		matched := id != "" && strings.EqualFold(note.ID, id)
+		if !matched && name != "" && (id == "" || !s.hasNoteID(id)) {
+			matched = strings.EqualFold(note.Title, name) || strings.EqualFold(strings.TrimSuffix(note.Name, ".md"), name)
+		}
+		if !matched {
+			continue
+		}
+
+		result := SearchResult{
+			File:           note,
+			MatchedIndices: []int{},
+			ContentSnippet: extractSnippet(note.Content, "", 80),
+		}
+		if line := scripts.FindAnchorLine(note.Content, anchor); line > 0 {
+			result.Section = scripts.ExtractAnchorSection(note.Content, anchor)
+			result.SnippetLine = line
+		}
+		candidates = append(candidates, result)
+	}
+
+	return candidates
+}
+
+// hasNoteID checks whether any note has the given ID
+func (s *SearchState) hasNoteID(id string) bool {
+	for _, note := range s.AllNotes {
+		if strings.EqualFold(note.ID, id) {
+			return true
+		}
+	}
+	return false
+}
+
 // extractSnippet extracts a snippet from content
 func extractSnippet(content, query string, maxLen int) string {
 	if content == "" {
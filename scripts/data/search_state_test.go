@@ -2,6 +2,7 @@ package data
 
 import (
 	"cli-notes/scripts"
+	"strings"
 	"testing"
 	"time"
 )
@@ -404,3 +405,131 @@ func TestUpdateQuery_AppliesFilter(t *testing.T) {
 		t.Error("ShowCompleteOnly should only return done notes")
 	}
 }
+
+// ============================================
+// Tag filter Tests
+// ============================================
+
+func TestTagFilter_KeepsNestedTags(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{
+		Name:      "android.md",
+		Title:     "Android",
+		CreatedAt: time.Now(),
+		Tags:      []string{"work/payments/android"},
+	})
+	createTestFile(t, scripts.File{
+		Name:      "home.md",
+		Title:     "Home",
+		CreatedAt: time.Now(),
+		Tags:      []string{"home"},
+	})
+
+	state, err := NewSearchState("")
+	if err != nil {
+		t.Fatalf("Failed to create search state: %v", err)
+	}
+
+	state.EnterTagTreeMode()
+	if state.ViewMode != SearchModeTagTree {
+		t.Fatalf("Expected tag tree mode, got %v", state.ViewMode)
+	}
+
+	// Select the top level "work" node
+	for state.TagTree[state.TagTreeIndex].Tag != "work" {
+		state.SelectNext()
+	}
+	state.ApplySelectedTagFilter()
+
+	if state.ViewMode != SearchModeNormal {
+		t.Errorf("Expected normal mode after applying filter, got %v", state.ViewMode)
+	}
+	if len(state.Results) != 1 || state.Results[0].File.Name != "android.md" {
+		t.Errorf("Expected only android.md, got %v", state.Results)
+	}
+
+	state.ClearTagFilter()
+	if len(state.Results) != 2 {
+		t.Errorf("Expected 2 results after clearing the filter, got %d", len(state.Results))
+	}
+}
+
+func TestUpdateQuery_LinkQueryShowsTargetedSection(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{
+		Name:      "meeting.md",
+		Title:     "Meeting",
+		CreatedAt: time.Now(),
+		Content:   "# Meeting\n\n## Plan\nShip it\n\n## Notes\nOther",
+	})
+	createTestFile(t, scripts.File{
+		Name:      "other.md",
+		Title:     "Other Note",
+		CreatedAt: time.Now(),
+		Content:   "Mentions Meeting#Plan",
+	})
+
+	state, err := NewSearchState("")
+	if err != nil {
+		t.Fatalf("Failed to create search state: %v", err)
+	}
+
+	state.UpdateQuery("[[meeting#plan|the plan]]")
+	if len(state.Results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(state.Results))
+	}
+
+	result := state.Results[0]
+	if result.File.Name != "meeting.md" {
+		t.Errorf("Expected meeting.md, got %s", result.File.Name)
+	}
+	if strings.Join(result.Section, "\n") != "## Plan\nShip it" {
+		t.Errorf("Expected the Plan section, got %v", result.Section)
+	}
+	if lines := strings.Split(result.File.Content, "\n"); lines[result.SnippetLine-1] != "## Plan" {
+		t.Errorf("Expected snippet line to be the Plan heading, got line %d", result.SnippetLine)
+	}
+}
+
+func TestSearchStateRefresh_PicksUpChangesAndKeepsSelection(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{Name: "alpha.md", Title: "Alpha", CreatedAt: time.Now(), Content: "release plan"})
+	createTestFile(t, scripts.File{Name: "beta.md", Title: "Beta", CreatedAt: time.Now(), Content: "release notes"})
+
+	state, err := NewSearchState("release")
+	if err != nil {
+		t.Fatalf("Failed to create search state: %v", err)
+	}
+	if len(state.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(state.Results))
+	}
+	state.SelectNext()
+	selected := state.GetSelectedResult().File.Name
+
+	// Another program adds a note and edits the unselected one
+	createTestFile(t, scripts.File{Name: "gamma.md", Title: "Gamma", CreatedAt: time.Now(), Content: "release date"})
+	other := "alpha.md"
+	if selected == "alpha.md" {
+		other = "beta.md"
+	}
+	createTestFile(t, scripts.File{Name: other, Title: "Changed", CreatedAt: time.Now(), Content: "nothing to see"})
+
+	if err := state.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if len(state.Results) != 2 {
+		t.Errorf("Expected the edited note out and the new one in, got %d results", len(state.Results))
+	}
+	if result := state.GetSelectedResult(); result == nil || result.File.Name != selected {
+		t.Errorf("Expected %s to stay selected, got %+v", selected, result)
+	}
+	if state.Query != "release" {
+		t.Errorf("Expected the query to be kept, got %q", state.Query)
+	}
+}
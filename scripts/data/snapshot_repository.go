@@ -0,0 +1,115 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VaultSnapshot is the vault as it was at a past commit, read from git without touching the working tree
+// Its query methods match the live queries, so the same scripts functions can run against the past
+type VaultSnapshot struct {
+	Commit scripts.GitCommit
+	paths  []string          // Paths of the notes in the commit, sorted
+	notes  map[string]string // Content of each note, by path
+}
+
+// LoadVaultSnapshot loads the notes at a revision, or for a YYYY-MM-DD date at the last commit of that day
+func LoadVaultSnapshot(revision string) (*VaultSnapshot, error) {
+	notesPath, err := gitNotesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := scripts.FindCommit(notesPath, revision)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := scripts.NoteContentsAt(notesPath, commit.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(notes))
+	for path := range notes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return &VaultSnapshot{Commit: commit, paths: paths, notes: notes}, nil
+}
+
+// QueryFilesByDone is QueryFilesByDone against the snapshot
+func (s *VaultSnapshot) QueryFilesByDone(isDone bool) ([]scripts.File, error) {
+	query := fmt.Sprintf("done: %v", isDone)
+
+	files := make([]scripts.File, 0)
+	for _, path := range s.paths {
+		file, err := parseFileIfQueryMatches(filepath.Base(path), strings.NewReader(s.notes[path]), query)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s at %s: %w", path, s.Commit.ShortHash(), err)
+		}
+		if file != nil {
+			files = append(files, *file)
+		}
+	}
+	return files, nil
+}
+
+// QueryTodosWithDateCriteria is QueryTodosWithDateCriteria against the snapshot
+func (s *VaultSnapshot) QueryTodosWithDateCriteria(dateCheck func(dueDate string, dueDateParsed time.Time) bool) ([]scripts.File, error) {
+	files := make([]scripts.File, 0)
+	for _, path := range s.paths {
+		matches, err := todoMatchesDateCriteria(strings.NewReader(s.notes[path]), dateCheck)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s at %s: %w", path, s.Commit.ShortHash(), err)
+		}
+		if !matches {
+			continue
+		}
+
+		file, err := parseFileIfQueryMatches(filepath.Base(path), strings.NewReader(s.notes[path]), "date-due:")
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s at %s: %w", path, s.Commit.ShortHash(), err)
+		}
+		files = append(files, *file)
+	}
+	return files, nil
+}
+
+// QueryDueDateChurn counts how often each note's due date was moved between two days, most moved first
+func QueryDueDateChurn(since, until time.Time) ([]scripts.DueDateChurn, error) {
+	notesPath, err := gitNotesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	churn, err := scripts.DueDateChurnBetween(notesPath, since, until)
+	if err != nil {
+		return nil, err
+	}
+	for i := range churn {
+		churn[i].Title = strings.TrimSuffix(filepath.Base(churn[i].Path), ".md")
+		if file, err := LoadFileByName(filepath.Base(churn[i].Path)); err == nil && file.Title != "" {
+			churn[i].Title = file.Title
+		}
+	}
+	return churn, nil
+}
+
+// gitNotesPath returns the path of the notes directory, once it is under git
+func gitNotesPath() (string, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	notesPath := filepath.Join(currentDir, DirectoryPath)
+	if !scripts.IsGitRepo(notesPath) {
+		return "", fmt.Errorf("notes are not under git yet")
+	}
+	return notesPath, nil
+}
@@ -0,0 +1,71 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"testing"
+	"time"
+)
+
+func TestVaultSnapshot_EvaluatesQueriesAtPastCommit(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	commitOn := func(date, message string) {
+		t.Setenv("GIT_AUTHOR_DATE", date+"T12:00:00")
+		t.Setenv("GIT_COMMITTER_DATE", date+"T12:00:00")
+		if err := scripts.CommitChangesWithMessage(DirectoryPath, message, []string{"."}); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+	due := func(date string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", date)
+		return parsed
+	}
+
+	if err := scripts.InitGitRepo(DirectoryPath); err != nil {
+		t.Fatalf("InitGitRepo failed: %v", err)
+	}
+	createTestFile(t, scripts.File{Name: "late.md", Title: "Late", Tags: []string{"todo"}, DueAt: due("2025-09-25"), Priority: scripts.P1})
+	createTestFile(t, scripts.File{Name: "soon.md", Title: "Soon", Tags: []string{"todo"}, DueAt: due("2025-10-05"), Priority: scripts.P2})
+	createTestFile(t, scripts.File{Name: "shipped.md", Title: "Shipped", Tags: []string{"todo"}, DueAt: due("2025-09-20"), Done: true, Priority: scripts.P2})
+	commitOn("2025-09-30", "plan october")
+
+	// Later on the late todo was done and the soon one moved back
+	createTestFile(t, scripts.File{Name: "late.md", Title: "Late", Tags: []string{"todo"}, DueAt: due("2025-09-25"), Done: true, Priority: scripts.P1})
+	createTestFile(t, scripts.File{Name: "soon.md", Title: "Soon", Tags: []string{"todo"}, DueAt: due("2025-10-20"), Priority: scripts.P2})
+	commitOn("2025-10-02", "catch up")
+
+	snapshot, err := LoadVaultSnapshot("2025-10-01")
+	if err != nil {
+		t.Fatalf("LoadVaultSnapshot failed: %v", err)
+	}
+	if snapshot.Commit.Subject != "plan october" {
+		t.Fatalf("Expected the commit of 2025-09-30, got %+v", snapshot.Commit)
+	}
+
+	overdue, err := scripts.GetOverdueTodosAt(func(dateQuery scripts.DateQuery) ([]scripts.File, error) {
+		return snapshot.QueryTodosWithDateCriteria(dateQuery)
+	}, due("2025-10-01"))
+	if err != nil {
+		t.Fatalf("GetOverdueTodosAt failed: %v", err)
+	}
+	if len(overdue) != 1 || overdue[0].Name != "late.md" || overdue[0].Done {
+		t.Errorf("Expected only late.md overdue and open on 2025-10-01, got %+v", overdue)
+	}
+
+	open, err := scripts.GetTodos(snapshot.QueryFilesByDone)
+	if err != nil {
+		t.Fatalf("GetTodos failed: %v", err)
+	}
+	if len(open) != 2 || open[0].Name != "late.md" || open[1].Name != "soon.md" || !open[1].DueAt.Equal(due("2025-10-05")) {
+		t.Errorf("Expected late.md and soon.md open with their old due dates, got %+v", open)
+	}
+
+	churn, err := QueryDueDateChurn(due("2025-09-01"), time.Now())
+	if err != nil {
+		t.Fatalf("QueryDueDateChurn failed: %v", err)
+	}
+	if len(churn) != 1 || churn[0].Title != "Soon" || churn[0].Changes != 1 {
+		t.Errorf("Expected Soon moved once, got %+v", churn)
+	}
+}
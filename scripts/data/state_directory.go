@@ -0,0 +1,20 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// StateDirectoryPath is the directory inside the notes for the program's own files, like its log
+// Git, the backups and the notes watcher only look at notes, so they leave it alone
+const StateDirectoryPath = ".cli-notes"
+
+// StateDirectory returns the path of the vault's state directory, which may not exist yet
+func StateDirectory() (string, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(currentDir, DirectoryPath, StateDirectoryPath), nil
+}
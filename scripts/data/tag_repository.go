@@ -0,0 +1,84 @@
+package data
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// parseTagsLine splits the value of a tags frontmatter line into tags
+// Handles both the comma-separated and the space-separated format
+func parseTagsLine(tagsLine string) []string {
+	tagsLine = strings.TrimSpace(tagsLine)
+	tagsLine = strings.Trim(tagsLine, "[]")
+
+	var fileTags []string
+	if strings.Contains(tagsLine, ",") {
+		// Handle comma-separated format
+		parts := strings.Split(tagsLine, ",")
+		for _, p := range parts {
+			fileTags = append(fileTags, strings.TrimSpace(p))
+		}
+	} else {
+		// Handle space-separated format
+		fileTags = strings.Fields(tagsLine)
+	}
+
+	return fileTags
+}
+
+// QueryAllTags returns every distinct tag used in the notes directory, sorted
+func QueryAllTags() ([]string, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	notesPath := filepath.Join(currentDir, DirectoryPath)
+	seen := make(map[string]bool)
+
+	err = filepath.Walk(notesPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "tags:") {
+				for _, tag := range parseTagsLine(strings.TrimPrefix(line, "tags:")) {
+					if tag != "" {
+						seen[tag] = true
+					}
+				}
+				break
+			}
+		}
+
+		return scanner.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseTagsLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected []string
+	}{
+		{name: "Space separated", line: " [todo work]", expected: []string{"todo", "work"}},
+		{name: "Comma separated", line: " [todo, work]", expected: []string{"todo", "work"}},
+		{name: "Empty", line: " []", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseTagsLine(tt.line)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, result)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("Expected %v, got %v", tt.expected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryAllTags_ReturnsDistinctSortedTags(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	createTestFile(t, scripts.File{
+		Name:      "first.md",
+		Title:     "First",
+		Tags:      []string{"work", "todo"},
+		CreatedAt: time.Now(),
+	})
+
+	// Handwritten note using the comma-separated format
+	content := "---\ntitle: Second\ntags: [todo, android]\ndone: false\n---\n\nBody\n"
+	err := os.WriteFile(filepath.Join(DirectoryPath, "second.md"), []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	tags, err := QueryAllTags()
+	if err != nil {
+		t.Fatalf("QueryAllTags failed: %v", err)
+	}
+
+	expected := []string{"android", "todo", "work"}
+	if len(tags) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, tags)
+	}
+	for i := range expected {
+		if tags[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, tags)
+		}
+	}
+}
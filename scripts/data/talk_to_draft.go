@@ -0,0 +1,126 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TalkToDraft is the move history of a talk-to session, which only lives in the view and is what undo uses
+type TalkToDraft struct {
+	Moves   []MoveChange `json:"moves"`
+	SavedAt time.Time    `json:"-"` // When the draft was last written, set when it is loaded
+}
+
+// Draft returns the moves that can still be undone, nil when there are none
+func (s *TalkToViewState) Draft() *TalkToDraft {
+	if len(s.UndoStack) == 0 {
+		return nil
+	}
+	moves := make([]MoveChange, len(s.UndoStack))
+	copy(moves, s.UndoStack)
+	return &TalkToDraft{Moves: moves}
+}
+
+// LoadTalkToDraft reads the draft left by an earlier session, an os.IsNotExist error when there is none
+func LoadTalkToDraft() (*TalkToDraft, error) {
+	var draft TalkToDraft
+	savedAt, err := readDraft(TalkToDraftName, &draft)
+	if err != nil {
+		return nil, err
+	}
+	draft.SavedAt = savedAt
+	return &draft, nil
+}
+
+// ResumeTalkToViewState opens the talk-to view on the last move of a draft, ready to undo it
+// Moves are stale when their lines in the target note or the source notes changed since; they are
+// dropped, and how many were is returned
+func ResumeTalkToViewState(draft *TalkToDraft) (*TalkToViewState, int, error) {
+	state, err := NewTalkToViewState("")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	current := make([]MoveChange, 0, len(draft.Moves))
+	for _, move := range draft.Moves {
+		if moveIsCurrent(move) {
+			current = append(current, move)
+		}
+	}
+	stale := len(draft.Moves) - len(current)
+	if len(current) == 0 {
+		return state, stale, nil
+	}
+
+	last := current[len(current)-1]
+	state.UndoStack = current
+	state.SelectedPerson = last.Person
+	state.AvailableTodos = last.Todos
+	state.SelectedTodos = make([]bool, len(last.Todos))
+	for i := range state.SelectedTodos {
+		state.SelectedTodos[i] = true
+	}
+	state.TargetNoteName = last.TargetNote
+	state.ViewMode = SuccessView
+	return state, stale, nil
+}
+
+// moveIsCurrent reports whether the notes still look as the move left them, so undoing it is safe
+func moveIsCurrent(move MoveChange) bool {
+	for fileName, modifications := range move.SourceModifications {
+		lines, err := readNoteLines(fileName)
+		if err != nil {
+			return false
+		}
+		for _, modification := range modifications {
+			if modification.LineNumber < 1 || modification.LineNumber > len(lines) || lines[modification.LineNumber-1] != modification.NewContent {
+				return false
+			}
+		}
+	}
+
+	if len(move.Todos) == 0 {
+		return false
+	}
+	lines, err := readNoteLines(move.TargetNote)
+	if err != nil {
+		return false
+	}
+	end := move.TargetInsertionPoint + countTodoLines(move.Todos)
+	if move.TargetInsertionPoint < 0 || end > len(lines) {
+		return false
+	}
+	inserted := lines[move.TargetInsertionPoint:end]
+	for _, todo := range move.Todos {
+		if !containsLine(inserted, scripts.RemoveTalkToTags(todo.TodoLine)) {
+			return false
+		}
+	}
+	return true
+}
+
+func readNoteLines(fileName string) ([]string, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(currentDir, DirectoryPath, fileName))
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(content), "\n"), nil
+}
+
+func containsLine(lines []string, line string) bool {
+	for _, candidate := range lines {
+		if candidate == line {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMoveIsCurrent_ChecksSourceAndTargetLines(t *testing.T) {
+	th := setupTest(t)
+	defer th.cleanup(t)
+
+	writeNote := func(name string, lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join("notes", name), []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	writeNote("source.md", "# Source", "- [x] Ask about the budget")
+	writeNote("meeting.md", "# Meeting", "", "- [ ] Ask about the budget", "", "Notes")
+
+	move := MoveChange{
+		Person:               "anna",
+		Todos:                []TodoWithMeta{{File: scripts.File{Name: "source.md"}, TodoLine: "- [ ] Ask about the budget", LineNumber: 2, SourceFile: "source.md"}},
+		TargetNote:           "meeting.md",
+		TargetInsertionPoint: 1,
+		SourceModifications: map[string][]LineModification{
+			"source.md": {{LineNumber: 2, OldContent: "- [ ] Ask about the budget", NewContent: "- [x] Ask about the budget"}},
+		},
+	}
+	if !moveIsCurrent(move) {
+		t.Fatal("Expected the move to be current")
+	}
+
+	writeNote("source.md", "# Source", "- [ ] Ask about the budget")
+	if moveIsCurrent(move) {
+		t.Error("Expected a move whose source line changed to be stale")
+	}
+
+	writeNote("source.md", "# Source", "- [x] Ask about the budget")
+	writeNote("meeting.md", "# Meeting", "", "Notes")
+	if moveIsCurrent(move) {
+		t.Error("Expected a move whose todos left the target to be stale")
+	}
+}
+
+func TestTalkToDraft_CopiesTheUndoStack(t *testing.T) {
+	state := &TalkToViewState{}
+	if state.Draft() != nil {
+		t.Fatal("Expected no draft without moves")
+	}
+
+	state.UndoStack = []MoveChange{{Person: "anna", TargetNote: "meeting.md"}}
+	draft := state.Draft()
+	state.UndoStack = state.UndoStack[:0]
+	if len(draft.Moves) != 1 || draft.Moves[0].TargetNote != "meeting.md" {
+		t.Errorf("Expected the draft to keep the move, got %+v", draft.Moves)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"cli-notes/scripts"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -62,6 +63,22 @@ func ScanAllTalkToTodos() (map[string][]TodoWithMeta, error) {
 	return todosByPerson, nil
 }
 
+// QueryTalkToPeople returns the sorted names of everyone with open to-talk items
+func QueryTalkToPeople() ([]string, error) {
+	todosByPerson, err := ScanAllTalkToTodos()
+	if err != nil {
+		return nil, err
+	}
+
+	people := make([]string, 0, len(todosByPerson))
+	for person := range todosByPerson {
+		people = append(people, person)
+	}
+	sort.Strings(people)
+
+	return people, nil
+}
+
 // MarkTodoLineComplete marks a specific todo line as complete in a file
 // Changes "- [ ]" to "- [x]" at the specified line number
 func MarkTodoLineComplete(file scripts.File, lineNumber int) error {
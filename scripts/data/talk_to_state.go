@@ -60,6 +60,15 @@ type MoveChange struct {
 	SourceModifications  map[string][]LineModification    // Filename -> list of line changes
 }
 
+// Paths returns the notes the move wrote: the target note and every source note
+func (m MoveChange) Paths() []string {
+	paths := []string{m.TargetNote}
+	for fileName := range m.SourceModifications {
+		paths = append(paths, fileName)
+	}
+	return paths
+}
+
 // TalkToViewState manages the state for the Talk-To interactive view
 type TalkToViewState struct {
 	ViewMode TalkToViewMode
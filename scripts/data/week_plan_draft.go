@@ -0,0 +1,142 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"time"
+)
+
+// WeekPlanDraft is the unsaved moves of a week plan
+type WeekPlanDraft struct {
+	WeekStart time.Time           `json:"week_start"`
+	Moves     []WeekPlanDraftMove `json:"moves"`
+	SavedAt   time.Time           `json:"-"` // When the draft was last written, set when it is loaded
+}
+
+// WeekPlanDraftMove is where the unsaved moves left one todo
+type WeekPlanDraftMove struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	From  string `json:"from"` // date-due on disk before the first move
+	To    string `json:"to"`   // date-due after the last move
+}
+
+// Draft returns the unsaved moves of the plan, nil when there are none
+// Todos moved back where they were are left out, and the draft holds copies of the plan's todos
+func (wp *WeekPlan) Draft() *WeekPlanDraft {
+	if !wp.HasChanges() {
+		return nil
+	}
+
+	// The first change of a todo still has the due date it had on disk
+	from := make(map[string]string)
+	order := make([]string, 0)
+	for _, change := range wp.Changes {
+		if _, seen := from[change.Todo.Name]; !seen {
+			from[change.Todo.Name] = change.Todo.DueAt.Format(dateFormat)
+			order = append(order, change.Todo.Name)
+		}
+	}
+
+	current := make(map[string]scripts.File)
+	for _, todos := range wp.TodosByDay {
+		for _, todo := range todos {
+			current[todo.Name] = todo
+		}
+	}
+
+	moves := make([]WeekPlanDraftMove, 0, len(order))
+	for _, name := range order {
+		todo, exists := current[name]
+		if !exists || todo.DueAt.Format(dateFormat) == from[name] {
+			continue
+		}
+		moves = append(moves, WeekPlanDraftMove{Name: name, Title: todo.Title, From: from[name], To: todo.DueAt.Format(dateFormat)})
+	}
+	if len(moves) == 0 {
+		return nil
+	}
+	return &WeekPlanDraft{WeekStart: wp.StartDate, Moves: moves}
+}
+
+// LoadWeekPlanDraft reads the draft left by an earlier session, an os.IsNotExist error when there is none
+func LoadWeekPlanDraft() (*WeekPlanDraft, error) {
+	var draft WeekPlanDraft
+	savedAt, err := readDraft(WeekPlanDraftName, &draft)
+	if err != nil {
+		return nil, err
+	}
+	draft.SavedAt = savedAt
+	return &draft, nil
+}
+
+// ApplyDraft moves the todos of the plan where the draft left them, as unsaved changes
+// Moves are stale when their todo is done, gone or out of the week, or its due date changed on disk
+// since the draft was written; they are skipped and their titles returned
+func (wp *WeekPlan) ApplyDraft(draft *WeekPlanDraft) []string {
+	stale := make([]string, 0)
+	for _, move := range draft.Moves {
+		to, err := time.ParseInLocation(dateFormat, move.To, time.Local)
+		if err != nil {
+			stale = append(stale, move.Title)
+			continue
+		}
+
+		todo, day, found := wp.findTodo(move.Name)
+		if !found || todo.DueAt.Format(dateFormat) != move.From {
+			stale = append(stale, move.Title)
+			continue
+		}
+
+		targetDay := wp.GetWeekDayForDate(to)
+		switch {
+		case targetDay == Earlier:
+			stale = append(stale, move.Title)
+		case targetDay < 0:
+			nextWeekDay, inNextWeek := wp.nextWeekDayForDate(to)
+			if !inNextWeek {
+				stale = append(stale, move.Title)
+				continue
+			}
+			wp.MoveTodoToNextWeek(todo, day, nextWeekDay)
+		default:
+			wp.MoveTodo(todo, day, targetDay)
+		}
+	}
+	return stale
+}
+
+// nextWeekDayForDate returns the day of the following week a date falls on, false for other dates
+func (wp *WeekPlan) nextWeekDayForDate(date time.Time) (WeekDay, bool) {
+	nextMonday := wp.StartDate.AddDate(0, 0, 7)
+	for day := Monday; day <= Sunday; day++ {
+		if date.Equal(nextMonday.AddDate(0, 0, int(day)-1)) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// findTodo returns a todo of the plan by note name, with the day it is on
+func (wp *WeekPlan) findTodo(name string) (scripts.File, WeekDay, bool) {
+	for day, todos := range wp.TodosByDay {
+		for _, todo := range todos {
+			if todo.Name == name {
+				return todo, day, true
+			}
+		}
+	}
+	return scripts.File{}, 0, false
+}
+
+// ResumeDraft opens the week of the draft and applies its moves, returning the titles of the stale ones
+func (wps *WeekPlannerState) ResumeDraft(draft *WeekPlanDraft) ([]string, error) {
+	if !draft.WeekStart.Equal(wps.Plan.StartDate) {
+		plan, err := LoadWeekTodos(draft.WeekStart)
+		if err != nil {
+			return nil, err
+		}
+		wps.Plan = plan
+		wps.SelectedTodo = 0
+	}
+	return wps.Plan.ApplyDraft(draft), nil
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"cli-notes/scripts"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWeekPlanDraft_RecordsWhereMovesLeftTodos(t *testing.T) {
+	monday := time.Date(2025, 11, 24, 0, 0, 0, 0, time.Local)
+	plan := NewWeekPlan(monday)
+	if plan.Draft() != nil {
+		t.Fatal("Expected no draft without changes")
+	}
+
+	moved := scripts.File{Name: "moved.md", Title: "Moved", DueAt: monday}
+	later := scripts.File{Name: "later.md", Title: "Later", DueAt: monday}
+	back := scripts.File{Name: "back.md", Title: "Back", DueAt: monday}
+	plan.TodosByDay[Monday] = []scripts.File{moved, later, back}
+
+	plan.MoveTodo(moved, Monday, Tuesday)
+	moved.DueAt = plan.GetDateForWeekDay(Tuesday)
+	plan.MoveTodo(moved, Tuesday, Wednesday)
+	plan.MoveTodoToNextWeek(later, Monday, Tuesday)
+	plan.MoveTodo(back, Monday, Friday)
+	back.DueAt = plan.GetDateForWeekDay(Friday)
+	plan.MoveTodo(back, Friday, Monday)
+
+	draft := plan.Draft()
+	if draft == nil {
+		t.Fatal("Expected a draft after moves")
+	}
+	expected := []WeekPlanDraftMove{
+		{Name: "moved.md", Title: "Moved", From: "2025-11-24", To: "2025-11-26"},
+		{Name: "later.md", Title: "Later", From: "2025-11-24", To: "2025-12-02"},
+	}
+	if !reflect.DeepEqual(draft.Moves, expected) {
+		t.Errorf("Draft().Moves = %+v, want %+v", draft.Moves, expected)
+	}
+	if !draft.WeekStart.Equal(monday) {
+		t.Errorf("Expected the week of %v, got %v", monday, draft.WeekStart)
+	}
+}
+
+func TestWeekPlanApplyDraft_SkipsStaleMoves(t *testing.T) {
+	monday := time.Date(2025, 11, 24, 0, 0, 0, 0, time.Local)
+	plan := NewWeekPlan(monday)
+	plan.TodosByDay[Monday] = []scripts.File{
+		{Name: "moved.md", Title: "Moved", DueAt: monday},
+		{Name: "later.md", Title: "Later", DueAt: monday},
+		{Name: "edited.md", Title: "Edited", DueAt: monday.AddDate(0, 0, 3)},
+		{Name: "far.md", Title: "Far", DueAt: monday},
+	}
+
+	stale := plan.ApplyDraft(&WeekPlanDraft{WeekStart: monday, Moves: []WeekPlanDraftMove{
+		{Name: "moved.md", Title: "Moved", From: "2025-11-24", To: "2025-11-26"},
+		{Name: "later.md", Title: "Later", From: "2025-11-24", To: "2025-12-02"},
+		{Name: "edited.md", Title: "Edited", From: "2025-11-24", To: "2025-11-25"}, // Due date changed since
+		{Name: "done.md", Title: "Done", From: "2025-11-24", To: "2025-11-25"},     // Done or deleted since
+		{Name: "far.md", Title: "Far", From: "2025-11-24", To: "2026-01-05"},       // Beyond next week
+	}})
+
+	if !reflect.DeepEqual(stale, []string{"Edited", "Done", "Far"}) {
+		t.Errorf("Expected the stale moves to be skipped, got %v", stale)
+	}
+	if len(plan.Changes) != 2 {
+		t.Fatalf("Expected 2 unsaved changes, got %d", len(plan.Changes))
+	}
+	if todo, day, _ := plan.findTodo("moved.md"); day != Wednesday || todo.DueAt.Format(dateFormat) != "2025-11-26" {
+		t.Errorf("Expected moved.md on Wednesday, got %v due %v", day, todo.DueAt)
+	}
+	if todo, day, _ := plan.findTodo("later.md"); day != NextMonday || todo.DueAt.Format(dateFormat) != "2025-12-02" {
+		t.Errorf("Expected later.md next Tuesday, got %v due %v", day, todo.DueAt)
+	}
+
+	// The replayed moves undo like any other
+	plan.Undo()
+	if todo, day, _ := plan.findTodo("later.md"); day != Monday || todo.DueAt.Format(dateFormat) != "2025-11-24" {
+		t.Errorf("Expected undo to bring later.md back, got %v due %v", day, todo.DueAt)
+	}
+}
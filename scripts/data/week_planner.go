@@ -369,6 +369,20 @@ func (wp *WeekPlan) SaveChanges() error {
 	return nil
 }
 
+// SavedNotes returns the notes SaveChanges writes, every todo of the week, along with the moved ones
+func (wp *WeekPlan) SavedNotes() []string {
+	names := make([]string, 0)
+	for _, todos := range wp.TodosByDay {
+		for _, todo := range todos {
+			names = append(names, todo.Name)
+		}
+	}
+	for _, change := range wp.Changes {
+		names = append(names, change.Todo.Name)
+	}
+	return names
+}
+
 // GetTodoCount returns the number of todos for a given day
 func (wp *WeekPlan) GetTodoCount(day WeekDay) int {
 	return len(wp.TodosByDay[day])
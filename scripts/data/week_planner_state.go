@@ -3,6 +3,9 @@ package data
 import (
 	"cli-notes/scripts"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -232,6 +235,47 @@ func (wps *WeekPlannerState) RefreshOpenedTodo(fileName string) error {
 	return nil
 }
 
+// RefreshChangedTodos reloads the todos changed on disk by another program, adding those
+// now due this week and dropping those deleted or done, while keeping the unsaved moves
+func (wps *WeekPlannerState) RefreshChangedTodos(fileNames []string) error {
+	for _, fileName := range fileNames {
+		if err := wps.RefreshOpenedTodo(fileName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshAllTodos reloads every note like RefreshChangedTodos, for when changes on disk were missed
+func (wps *WeekPlannerState) RefreshAllTodos() error {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(currentDir, DirectoryPath))
+	if err != nil {
+		return fmt.Errorf("error reading notes: %w", err)
+	}
+
+	// The plan's own todos are refreshed too, so those deleted from disk are dropped
+	fileNames := make([]string, 0, len(entries))
+	seen := make(map[string]bool)
+	for _, fileName := range wps.Plan.SavedNotes() {
+		if !seen[fileName] {
+			seen[fileName] = true
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") && !seen[entry.Name()] {
+			seen[entry.Name()] = true
+			fileNames = append(fileNames, entry.Name())
+		}
+	}
+	return wps.RefreshChangedTodos(fileNames)
+}
+
 // Save writes all changes to disk
 func (wps *WeekPlannerState) Save() error {
 	return wps.Plan.SaveChanges()
@@ -0,0 +1,76 @@
+package scripts
+
+import "strings"
+
+// Kinds of lines in a diff
+const (
+	DiffSame    = ' '
+	DiffAdded   = '+'
+	DiffRemoved = '-'
+)
+
+// DiffLine is a line of a line-by-line diff
+type DiffLine struct {
+	Kind rune // DiffSame, DiffAdded or DiffRemoved
+	Text string
+}
+
+// DiffLines compares two texts line by line, from the longest common subsequence of their lines
+// Removed lines come before the added lines that replace them
+func DiffLines(oldText, newText string) []DiffLine {
+	oldLines := splitDiffLines(oldText)
+	newLines := splitDiffLines(newText)
+
+	// common[i][j] is the length of the longest common subsequence of oldLines[i:] and newLines[j:]
+	common := make([][]int, len(oldLines)+1)
+	for i := range common {
+		common[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	diff := make([]DiffLine, 0, len(oldLines)+len(newLines))
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			diff = append(diff, DiffLine{Kind: DiffSame, Text: oldLines[i]})
+			i++
+			j++
+		case j == len(newLines) || i < len(oldLines) && common[i+1][j] >= common[i][j+1]:
+			diff = append(diff, DiffLine{Kind: DiffRemoved, Text: oldLines[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Kind: DiffAdded, Text: newLines[j]})
+			j++
+		}
+	}
+	return diff
+}
+
+// DiffChanged reports whether a diff has any added or removed lines
+func DiffChanged(diff []DiffLine) bool {
+	for _, line := range diff {
+		if line.Kind != DiffSame {
+			return true
+		}
+	}
+	return false
+}
+
+// splitDiffLines splits text into lines, without an empty line for the final newline
+func splitDiffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
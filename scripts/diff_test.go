@@ -0,0 +1,30 @@
+package scripts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	diff := DiffLines("title\nold line\nsame\n", "title\nnew line\nsame\nadded\n")
+
+	expected := []DiffLine{
+		{Kind: DiffSame, Text: "title"},
+		{Kind: DiffRemoved, Text: "old line"},
+		{Kind: DiffAdded, Text: "new line"},
+		{Kind: DiffSame, Text: "same"},
+		{Kind: DiffAdded, Text: "added"},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, diff)
+	}
+	if !DiffChanged(diff) {
+		t.Error("Expected the diff to have changes")
+	}
+	if DiffChanged(DiffLines("same\n", "same\n")) {
+		t.Error("Expected identical texts to have no changes")
+	}
+	if diff := DiffLines("gone\n", ""); len(diff) != 1 || diff[0].Kind != DiffRemoved {
+		t.Errorf("Expected every line removed, got %+v", diff)
+	}
+}
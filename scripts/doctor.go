@@ -0,0 +1,304 @@
+package scripts
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Health checks run by the doctor, in report order
+const (
+	HealthOrphan              = "orphan"
+	HealthDeadLink            = "dead-link"
+	HealthMissingParent       = "missing-parent"
+	HealthMissingObjectiveTag = "missing-objective-tag"
+	HealthInvalidObjectiveID  = "invalid-objective-id"
+	HealthBadDate             = "bad-date"
+	HealthBadPriority         = "bad-priority"
+	HealthMissingPriority     = "missing-priority"
+)
+
+// HealthChecks lists every health check in report order
+var HealthChecks = []string{
+	HealthOrphan,
+	HealthDeadLink,
+	HealthMissingParent,
+	HealthMissingObjectiveTag,
+	HealthInvalidObjectiveID,
+	HealthBadDate,
+	HealthBadPriority,
+	HealthMissingPriority,
+}
+
+// healthDateFormat is the only date format notes are read with
+const healthDateFormat = "2006-01-02"
+
+// lenientDateFormats are date formats that can be safely rewritten as healthDateFormat
+var lenientDateFormats = []string{
+	"2006-1-2",
+	"2006/1/2",
+	"2006.1.2",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+// VaultNote is a note with what the doctor needs to know about it
+type VaultNote struct {
+	File        File
+	Frontmatter map[string]string // Raw frontmatter values, before loading replaces bad ones with defaults
+	DeadLinks   []string          // Links that match no note
+	Linked      bool              // Links to or is linked from another note
+}
+
+// HealthIssue is a problem found in a note
+type HealthIssue struct {
+	Check  string
+	File   File
+	Detail string // What is wrong, e.g. the dead link or the bad value
+	Fix    string // What the automated fix does, empty when there is no safe fix
+}
+
+// HealthFix is a note rewritten to fix its issues
+type HealthFix struct {
+	File    File     // The fixed note
+	Changes []string // What changed, one line each
+}
+
+// CheckVaultHealth finds orphan notes, dead links, broken objectives and frontmatter values
+// that loading silently replaces, each with the automated fix where one is safe
+func CheckVaultHealth(notes []VaultNote) []HealthIssue {
+	sorted := sortedVaultNotes(notes)
+	parents := objectiveParents(sorted)
+	issues := make([]HealthIssue, 0)
+
+	for _, note := range sorted {
+		file := note.File
+
+		if !note.Linked {
+			issues = append(issues, HealthIssue{Check: HealthOrphan, File: file, Detail: "no links in or out"})
+		}
+
+		for _, link := range note.DeadLinks {
+			issues = append(issues, HealthIssue{Check: HealthDeadLink, File: file, Detail: "[[" + link + "]]"})
+		}
+
+		if file.ObjectiveRole == "parent" {
+			if !hasTag(file.Tags, NoteTypeObjective) {
+				issues = append(issues, HealthIssue{
+					Check:  HealthMissingObjectiveTag,
+					File:   file,
+					Detail: "parent objective without the objective tag",
+					Fix:    "add the objective tag",
+				})
+			}
+		} else if file.ObjectiveID != "" {
+			if _, exists := parents[file.ObjectiveID]; !exists {
+				issues = append(issues, HealthIssue{
+					Check:  HealthMissingParent,
+					File:   file,
+					Detail: "objective-id " + file.ObjectiveID + " has no parent objective",
+					Fix:    "unlink from the missing objective",
+				})
+			}
+		}
+
+		if file.ObjectiveID != "" && !ValidateObjectiveID(file.ObjectiveID) {
+			issue := HealthIssue{Check: HealthInvalidObjectiveID, File: file, Detail: "objective-id " + file.ObjectiveID + " is not 8 hex characters"}
+			if file.ObjectiveRole == "parent" {
+				issue.Fix = "give the objective and its children a new ID"
+			} else if parent, exists := parents[file.ObjectiveID]; exists {
+				issue.Fix = "take the new ID of " + parent.Title
+			}
+			issues = append(issues, issue)
+		}
+
+		for _, key := range []string{"date-due", "date-created"} {
+			value, exists := note.Frontmatter[key]
+			if !exists {
+				continue
+			}
+			if _, err := time.Parse(healthDateFormat, value); err == nil {
+				continue
+			}
+			issue := HealthIssue{Check: HealthBadDate, File: file, Detail: fmt.Sprintf("%s: %q", key, value)}
+			if date, ok := ParseLenientDate(value); ok {
+				issue.Fix = "rewrite as " + date.Format(healthDateFormat)
+			}
+			issues = append(issues, issue)
+		}
+
+		if value, exists := note.Frontmatter["priority"]; exists && !validPriority(value) {
+			issues = append(issues, HealthIssue{
+				Check:  HealthBadPriority,
+				File:   file,
+				Detail: fmt.Sprintf("priority: %q", value),
+				Fix:    "set to 2, the priority it is already treated as",
+			})
+		}
+
+		// Any write of the note adds a priority, so a missing one is fixed in the open
+		if _, exists := note.Frontmatter["priority"]; !exists && len(note.Frontmatter) > 0 {
+			issues = append(issues, HealthIssue{
+				Check:  HealthMissingPriority,
+				File:   file,
+				Detail: "no priority",
+				Fix:    "set to 2",
+			})
+		}
+	}
+
+	return issues
+}
+
+// PlanHealthFixes applies the safe fixes of the issues to copies of the notes
+// Notes with a bad date that has no fix are left alone, since rewriting them would overwrite the date
+func PlanHealthFixes(notes []VaultNote, issues []HealthIssue) ([]HealthFix, error) {
+	sorted := sortedVaultNotes(notes)
+	files := make(map[string]File, len(sorted))
+	for _, note := range sorted {
+		files[note.File.Name] = note.File
+	}
+
+	blocked := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Check == HealthBadDate && issue.Fix == "" {
+			blocked[issue.File.Name] = true
+		}
+	}
+
+	changes := make(map[string][]string)
+	change := func(fileName, description string) {
+		changes[fileName] = append(changes[fileName], description)
+	}
+
+	for _, issue := range issues {
+		fileName := issue.File.Name
+		if issue.Fix == "" || blocked[fileName] {
+			continue
+		}
+		file := files[fileName]
+
+		switch issue.Check {
+		case HealthMissingObjectiveTag:
+			file.Tags = append(file.Tags, NoteTypeObjective)
+			change(fileName, "add tag objective")
+
+		case HealthMissingParent:
+			change(fileName, "remove objective-id "+file.ObjectiveID)
+			file.ObjectiveID = ""
+
+		case HealthInvalidObjectiveID:
+			if file.ObjectiveRole != "parent" {
+				continue // Children are renumbered with their parent
+			}
+			newID, err := GenerateObjectiveID()
+			if err != nil {
+				return nil, err
+			}
+			for _, note := range sorted {
+				child := files[note.File.Name]
+				if child.ObjectiveRole == "parent" || child.ObjectiveID != file.ObjectiveID || blocked[child.Name] {
+					continue
+				}
+				change(child.Name, fmt.Sprintf("objective-id %s -> %s", child.ObjectiveID, newID))
+				child.ObjectiveID = newID
+				files[child.Name] = child
+			}
+			change(fileName, fmt.Sprintf("objective-id %s -> %s", file.ObjectiveID, newID))
+			file.ObjectiveID = newID
+
+		case HealthBadDate:
+			key := strings.SplitN(issue.Detail, ":", 2)[0]
+			date, ok := ParseLenientDate(findVaultNote(sorted, fileName).Frontmatter[key])
+			if !ok {
+				continue
+			}
+			if key == "date-due" {
+				file.DueAt = date
+			} else {
+				file.CreatedAt = date
+			}
+			change(fileName, fmt.Sprintf("%s -> %s", key, date.Format(healthDateFormat)))
+
+		case HealthBadPriority, HealthMissingPriority:
+			file.Priority = P2
+			change(fileName, "priority -> 2")
+		}
+
+		files[fileName] = file
+	}
+
+	fixes := make([]HealthFix, 0, len(changes))
+	for _, note := range sorted {
+		descriptions, exists := changes[note.File.Name]
+		if !exists {
+			continue
+		}
+		fixes = append(fixes, HealthFix{File: files[note.File.Name], Changes: descriptions})
+	}
+	return fixes, nil
+}
+
+// ParseLenientDate reads a date written in a near miss of YYYY-MM-DD, such as 2024/3/5 or a quoted date
+func ParseLenientDate(value string) (time.Time, bool) {
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	if date, err := time.Parse(healthDateFormat, value); err == nil {
+		return date, true
+	}
+	for _, format := range lenientDateFormats {
+		if date, err := time.Parse(format, value); err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// validPriority checks that a raw priority value is 1, 2 or 3
+func validPriority(value string) bool {
+	priority, err := strconv.Atoi(value)
+	return err == nil && priority >= int(P1) && priority <= int(P3)
+}
+
+// objectiveParents maps objective IDs to their parent objective
+func objectiveParents(notes []VaultNote) map[string]File {
+	parents := make(map[string]File)
+	for _, note := range notes {
+		if note.File.ObjectiveRole == "parent" && note.File.ObjectiveID != "" {
+			parents[note.File.ObjectiveID] = note.File
+		}
+	}
+	return parents
+}
+
+// sortedVaultNotes returns the notes in filename order
+func sortedVaultNotes(notes []VaultNote) []VaultNote {
+	sorted := append([]VaultNote{}, notes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].File.Name < sorted[j].File.Name
+	})
+	return sorted
+}
+
+// findVaultNote returns the note with the filename
+func findVaultNote(notes []VaultNote, fileName string) VaultNote {
+	for _, note := range notes {
+		if note.File.Name == fileName {
+			return note
+		}
+	}
+	return VaultNote{}
+}
+
+// hasTag checks whether the tags contain the exact tag
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package scripts
+
+import (
+	"testing"
+	"time"
+)
+
+func healthIssuesByCheck(issues []HealthIssue) map[string][]HealthIssue {
+	byCheck := make(map[string][]HealthIssue)
+	for _, issue := range issues {
+		byCheck[issue.Check] = append(byCheck[issue.Check], issue)
+	}
+	return byCheck
+}
+
+func TestCheckVaultHealth_HealthyVault(t *testing.T) {
+	notes := []VaultNote{
+		{File: File{Name: "launch.md", Title: "Launch", Tags: []string{"objective"}, ObjectiveRole: "parent", ObjectiveID: "abcd1234"}, Linked: true,
+			Frontmatter: map[string]string{"date-due": "2024-03-05", "date-created": "2024-03-01", "priority": "1"}},
+		{File: File{Name: "ship.md", Title: "Ship", ObjectiveID: "abcd1234"}, Linked: true,
+			Frontmatter: map[string]string{"priority": "2"}},
+	}
+
+	if issues := CheckVaultHealth(notes); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %+v", issues)
+	}
+}
+
+func TestCheckVaultHealth_FindsEveryProblem(t *testing.T) {
+	notes := []VaultNote{
+		{File: File{Name: "orphan.md", Title: "Orphan"}, DeadLinks: []string{"Missing"}},
+		{File: File{Name: "child.md", Title: "Child", ObjectiveID: "deadbeef"}, Linked: true},
+		{File: File{Name: "parent.md", Title: "Parent", ObjectiveRole: "parent", ObjectiveID: "xyz"}, Linked: true},
+		{File: File{Name: "dates.md", Title: "Dates"}, Linked: true,
+			Frontmatter: map[string]string{"date-due": "2024/3/5", "date-created": "yesterday", "priority": "7"}},
+	}
+
+	byCheck := healthIssuesByCheck(CheckVaultHealth(notes))
+
+	if len(byCheck[HealthOrphan]) != 1 || byCheck[HealthOrphan][0].File.Name != "orphan.md" {
+		t.Errorf("Expected orphan.md to be an orphan, got %+v", byCheck[HealthOrphan])
+	}
+	if len(byCheck[HealthDeadLink]) != 1 || byCheck[HealthDeadLink][0].Detail != "[[Missing]]" {
+		t.Errorf("Expected one dead link, got %+v", byCheck[HealthDeadLink])
+	}
+	if len(byCheck[HealthMissingParent]) != 1 || byCheck[HealthMissingParent][0].File.Name != "child.md" {
+		t.Errorf("Expected child.md to miss its parent, got %+v", byCheck[HealthMissingParent])
+	}
+	if len(byCheck[HealthMissingObjectiveTag]) != 1 || byCheck[HealthMissingObjectiveTag][0].File.Name != "parent.md" {
+		t.Errorf("Expected parent.md to miss the objective tag, got %+v", byCheck[HealthMissingObjectiveTag])
+	}
+	if len(byCheck[HealthInvalidObjectiveID]) != 1 || byCheck[HealthInvalidObjectiveID][0].Fix == "" {
+		t.Errorf("Expected a fixable invalid objective ID, got %+v", byCheck[HealthInvalidObjectiveID])
+	}
+
+	dates := byCheck[HealthBadDate]
+	if len(dates) != 2 {
+		t.Fatalf("Expected 2 bad dates, got %+v", dates)
+	}
+	if dates[0].Detail != `date-due: "2024/3/5"` || dates[0].Fix != "rewrite as 2024-03-05" {
+		t.Errorf("Expected a fixable due date, got %+v", dates[0])
+	}
+	if dates[1].Detail != `date-created: "yesterday"` || dates[1].Fix != "" {
+		t.Errorf("Expected an unfixable created date, got %+v", dates[1])
+	}
+	if len(byCheck[HealthBadPriority]) != 1 {
+		t.Errorf("Expected a bad priority, got %+v", byCheck[HealthBadPriority])
+	}
+	// Notes without frontmatter have nothing to fix, the others are told about a missing priority
+	if len(byCheck[HealthMissingPriority]) != 0 {
+		t.Errorf("Expected no missing priority without frontmatter, got %+v", byCheck[HealthMissingPriority])
+	}
+}
+
+func TestCheckVaultHealth_ReportsMissingPriority(t *testing.T) {
+	notes := []VaultNote{
+		{File: File{Name: "todo.md", Title: "Todo"}, Linked: true, Frontmatter: map[string]string{"title": "Todo"}},
+	}
+
+	issues := CheckVaultHealth(notes)
+	if len(issues) != 1 || issues[0].Check != HealthMissingPriority || issues[0].Fix != "set to 2" {
+		t.Errorf("Expected a fixable missing priority, got %+v", issues)
+	}
+}
+
+func TestPlanHealthFixes_FixesObjectives(t *testing.T) {
+	notes := []VaultNote{
+		{File: File{Name: "parent.md", Title: "Parent", Tags: []string{"work"}, ObjectiveRole: "parent", ObjectiveID: "xyz", Priority: P1}, Linked: true,
+			Frontmatter: map[string]string{"objective-id": "xyz", "priority": "1"}},
+		{File: File{Name: "child.md", Title: "Child", ObjectiveID: "xyz", Priority: P3}, Linked: true,
+			Frontmatter: map[string]string{"objective-id": "xyz", "priority": "3"}},
+		{File: File{Name: "stray.md", Title: "Stray", ObjectiveID: "deadbeef"}, Linked: true,
+			Frontmatter: map[string]string{"objective-id": "deadbeef"}},
+	}
+
+	fixes, err := PlanHealthFixes(notes, CheckVaultHealth(notes))
+	if err != nil {
+		t.Fatalf("PlanHealthFixes failed: %v", err)
+	}
+	if len(fixes) != 3 {
+		t.Fatalf("Expected 3 fixed notes, got %+v", fixes)
+	}
+
+	child, parent, stray := fixes[0].File, fixes[1].File, fixes[2].File
+	if !ValidateObjectiveID(parent.ObjectiveID) || child.ObjectiveID != parent.ObjectiveID {
+		t.Errorf("Expected parent and child to share a new valid ID, got %q and %q", parent.ObjectiveID, child.ObjectiveID)
+	}
+	if !hasTag(parent.Tags, "objective") || !hasTag(parent.Tags, "work") {
+		t.Errorf("Expected the objective tag to be added, got %v", parent.Tags)
+	}
+	if stray.ObjectiveID != "" {
+		t.Errorf("Expected stray.md to be unlinked, got %q", stray.ObjectiveID)
+	}
+	if parent.Priority != P1 || child.Priority != P3 {
+		t.Errorf("Expected the priorities to be kept, got %d and %d", parent.Priority, child.Priority)
+	}
+	// A missing priority is its own issue, fixed with the others instead of on the quiet
+	if stray.Priority != P2 || fixes[2].Changes[len(fixes[2].Changes)-1] != "priority -> 2" {
+		t.Errorf("Expected stray.md to get priority 2, got %d with %v", stray.Priority, fixes[2].Changes)
+	}
+}
+
+func TestPlanHealthFixes_FixesDatesAndPriority(t *testing.T) {
+	notes := []VaultNote{
+		{File: File{Name: "dates.md", Title: "Dates", DueAt: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), Priority: P2}, Linked: true,
+			Frontmatter: map[string]string{"date-due": `"2024-03-05"`, "priority": "0"}},
+		{File: File{Name: "lost.md", Title: "Lost", Priority: P2}, Linked: true,
+			Frontmatter: map[string]string{"date-due": "next week", "priority": "9"}},
+	}
+
+	fixes, err := PlanHealthFixes(notes, CheckVaultHealth(notes))
+	if err != nil {
+		t.Fatalf("PlanHealthFixes failed: %v", err)
+	}
+
+	// lost.md keeps its unreadable date, so it is not rewritten at all
+	if len(fixes) != 1 || fixes[0].File.Name != "dates.md" {
+		t.Fatalf("Expected only dates.md to be fixed, got %+v", fixes)
+	}
+	if got := fixes[0].File.DueAt.Format("2006-01-02"); got != "2024-03-05" {
+		t.Errorf("Expected due date 2024-03-05, got %s", got)
+	}
+	if len(fixes[0].Changes) != 2 {
+		t.Errorf("Expected 2 changes, got %v", fixes[0].Changes)
+	}
+}
+
+func TestParseLenientDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"2024-03-05", "2024-03-05"},
+		{"2024-3-5", "2024-03-05"},
+		{"2024/03/05", "2024-03-05"},
+		{"'2024-03-05'", "2024-03-05"},
+		{"2024-03-05T10:30:00Z", "2024-03-05"},
+		{"05/03/2024", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		date, ok := ParseLenientDate(tt.value)
+		if tt.expected == "" {
+			if ok {
+				t.Errorf("ParseLenientDate(%q): expected no date, got %v", tt.value, date)
+			}
+			continue
+		}
+		if !ok || date.Format("2006-01-02") != tt.expected {
+			t.Errorf("ParseLenientDate(%q) = %v, %v; expected %s", tt.value, date, ok, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package scripts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DueDateChurn is how often a note's date-due was moved over a period
+type DueDateChurn struct {
+	Path    string   // Path of the note at the end of the period, or when it was deleted
+	Title   string   // Title of the note, filled in by the caller
+	Changes int      // Commits that moved the due date
+	Dues    []string // Due dates in order, from before the first move to after the last
+}
+
+// DueDateChurnBetween counts the commits between since and until that moved each note's date-due,
+// following renames, and returns the moved notes most moved first
+func DueDateChurnBetween(dirPath string, since, until time.Time) ([]DueDateChurn, error) {
+	output, err := gitOutput(dirPath, "-c", "core.quotepath=false", "log", "--reverse", "-p", "-U0", "-M",
+		"--format="+gitRecordSeparator+"%H",
+		"--since="+since.Format("2006-01-02T15:04:05"),
+		"--until="+until.Format("2006-01-02T15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	return parseDueDateChurn(output), nil
+}
+
+// fileDueChange is what a commit's patch of a single file did to its path and due date
+type fileDueChange struct {
+	oldPath, newPath string
+	oldDue, newDue   string
+	inHunks          bool // Past the header, where lines are changed lines of the note
+}
+
+// parseDueDateChurn reads git log -p -U0 output, oldest commit first
+func parseDueDateChurn(output string) []DueDateChurn {
+	churn := make(map[string]*DueDateChurn)
+	apply := func(change *fileDueChange) {
+		if change == nil {
+			return
+		}
+		if change.oldPath != "" && change.newPath != "" && change.oldPath != change.newPath {
+			if entry, exists := churn[change.oldPath]; exists {
+				delete(churn, change.oldPath)
+				entry.Path = change.newPath
+				churn[change.newPath] = entry
+			}
+		}
+
+		path := change.newPath
+		if path == "" {
+			path = change.oldPath // Deleted, keep the churn it had
+		}
+		if !strings.HasSuffix(path, ".md") || change.oldDue == "" || change.newDue == "" || change.oldDue == change.newDue {
+			return
+		}
+
+		entry, exists := churn[path]
+		if !exists {
+			entry = &DueDateChurn{Path: path, Dues: []string{change.oldDue}}
+			churn[path] = entry
+		}
+		entry.Changes++
+		entry.Dues = append(entry.Dues, change.newDue)
+	}
+
+	var current *fileDueChange
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, gitRecordSeparator), strings.HasPrefix(line, "diff --git "):
+			apply(current)
+			current = nil
+			if strings.HasPrefix(line, "diff --git ") {
+				current = &fileDueChange{}
+			}
+		case current == nil:
+		case strings.HasPrefix(line, "@@"):
+			current.inHunks = true
+		case current.inHunks:
+			if strings.HasPrefix(line, "-date-due:") {
+				current.oldDue = strings.TrimSpace(strings.TrimPrefix(line, "-date-due:"))
+			} else if strings.HasPrefix(line, "+date-due:") {
+				current.newDue = strings.TrimSpace(strings.TrimPrefix(line, "+date-due:"))
+			}
+		case strings.HasPrefix(line, "rename from "):
+			current.oldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			current.newPath = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "--- "):
+			current.oldPath = diffHeaderPath(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			current.newPath = diffHeaderPath(strings.TrimPrefix(line, "+++ "), "b/")
+		}
+	}
+	apply(current)
+
+	result := make([]DueDateChurn, 0, len(churn))
+	for _, entry := range churn {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Changes != result[j].Changes {
+			return result[i].Changes > result[j].Changes
+		}
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
+// diffHeaderPath reads the path of a ---/+++ line of a patch, "" for /dev/null
+func diffHeaderPath(path, prefix string) string {
+	if path == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSuffix(path, "\t"), prefix)
+}
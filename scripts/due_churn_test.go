@@ -0,0 +1,61 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDueDateChurnBetween_CountsMovesAcrossRenames(t *testing.T) {
+	dir := t.TempDir()
+	if err := InitGitRepo(dir); err != nil {
+		t.Fatalf("InitGitRepo failed: %v", err)
+	}
+
+	todo := func(name, title, due string) {
+		content := "---\ntitle: " + title + "\ndate-due: " + due + "\ndone: false\n---\n\nSome notes about the task\nthat are long enough\nto follow a rename\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	commit := func(message string) {
+		if err := CommitChangesWithMessage(dir, message, []string{"."}); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+
+	todo("login.md", "Fix login", "2025-10-03")
+	todo("review.md", "API review", "2025-10-05")
+	commit("create todos")
+	todo("login.md", "Fix login", "2025-10-10")
+	commit("move login")
+	todo("review.md", "API review", "2025-10-12")
+	commit("move review")
+	os.Rename(filepath.Join(dir, "login.md"), filepath.Join(dir, "fix-login.md"))
+	commit("rename login")
+	todo("fix-login.md", "Fix login", "2025-10-17")
+	commit("move login again")
+
+	now := time.Now()
+	churn, err := DueDateChurnBetween(dir, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("DueDateChurnBetween failed: %v", err)
+	}
+
+	if len(churn) != 2 {
+		t.Fatalf("Expected 2 moved todos, got %+v", churn)
+	}
+	login := churn[0]
+	if login.Path != "fix-login.md" || login.Changes != 2 || len(login.Dues) != 3 || login.Dues[0] != "2025-10-03" || login.Dues[2] != "2025-10-17" {
+		t.Errorf("Expected fix-login.md moved twice from 2025-10-03 to 2025-10-17, got %+v", login)
+	}
+	if churn[1].Path != "review.md" || churn[1].Changes != 1 {
+		t.Errorf("Expected review.md moved once, got %+v", churn[1])
+	}
+
+	churn, err = DueDateChurnBetween(dir, now.Add(time.Hour), now.Add(2*time.Hour))
+	if err != nil || len(churn) != 0 {
+		t.Errorf("Expected no moves outside the period, got %+v (%v)", churn, err)
+	}
+}
@@ -0,0 +1,141 @@
+package scripts
+
+import (
+	"sort"
+	"strings"
+)
+
+// DuplicateTitleGroup is a set of notes that share a title, oldest first
+type DuplicateTitleGroup struct {
+	Title string
+	Files []File
+}
+
+// LinkQualification is a note whose ambiguous links are pointed at one note by ID
+type LinkQualification struct {
+	File   File // The linking note, with the qualified content
+	Target File
+	Count  int
+}
+
+// FindDuplicateTitles groups notes whose titles match case-insensitively
+// Groups are sorted by title, the notes in a group by creation date
+func FindDuplicateTitles(files []File) []DuplicateTitleGroup {
+	byTitle := make(map[string][]File)
+	for _, file := range files {
+		if file.Title == "" {
+			continue
+		}
+		key := strings.ToLower(file.Title)
+		byTitle[key] = append(byTitle[key], file)
+	}
+
+	groups := make([]DuplicateTitleGroup, 0)
+	for _, group := range byTitle {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			if !group[i].CreatedAt.Equal(group[j].CreatedAt) {
+				return group[i].CreatedAt.Before(group[j].CreatedAt)
+			}
+			return group[i].Name < group[j].Name
+		})
+		groups = append(groups, DuplicateTitleGroup{Title: group[0].Title, Files: group})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Title) < strings.ToLower(groups[j].Title)
+	})
+
+	return groups
+}
+
+// PlanDatedTitles renames every note in the group but the newest to "<title> <date-created>"
+// Links by title then resolve to the newest note. When the dated title is taken,
+// the filename is used as the title instead
+// Returns the renamed notes with their first heading updated
+func PlanDatedTitles(group DuplicateTitleGroup, files []File) []File {
+	taken := make(map[string]bool)
+	for _, file := range files {
+		taken[strings.ToLower(file.Title)] = true
+	}
+
+	renamed := make([]File, 0, len(group.Files)-1)
+	for _, file := range group.Files[:len(group.Files)-1] {
+		newTitle := file.Title + " " + file.CreatedAt.Format("2006-01-02")
+		if taken[strings.ToLower(newTitle)] {
+			newTitle = strings.TrimSuffix(file.Name, ".md")
+		}
+		taken[strings.ToLower(newTitle)] = true
+
+		file.Content = retitleHeading(file.Content, file.Title, newTitle)
+		file.Title = newTitle
+		renamed = append(renamed, file)
+	}
+
+	return renamed
+}
+
+// retitleHeading replaces the first heading when it is the old title
+func retitleHeading(content, oldTitle, newTitle string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmedLine, "# ") {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(trimmedLine[2:]), oldTitle) {
+			lines[i] = "# " + newTitle
+		}
+		break
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SuggestLinkTarget picks the note a link from source most likely means:
+// the newest note created on or before the source, or else the oldest one
+func SuggestLinkTarget(source File, candidates []File) File {
+	suggested := candidates[0]
+	for _, candidate := range candidates {
+		if candidate.CreatedAt.After(source.CreatedAt) {
+			break
+		}
+		suggested = candidate
+	}
+	return suggested
+}
+
+// PlanLinkQualifications turns the links to a duplicate title into ID-backed links,
+// pointing each linking note at its suggested target
+func PlanLinkQualifications(group DuplicateTitleGroup, files []File) []LinkQualification {
+	title := strings.ToLower(group.Title)
+
+	// A note whose filename is the title makes the link unambiguous
+	for _, file := range files {
+		if strings.ToLower(strings.TrimSuffix(file.Name, ".md")) == title {
+			return []LinkQualification{}
+		}
+	}
+
+	qualifications := make([]LinkQualification, 0)
+	for _, file := range files {
+		candidates := make([]File, 0, len(group.Files))
+		for _, candidate := range group.Files {
+			if candidate.Name != file.Name {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		target := SuggestLinkTarget(file, candidates)
+		content, count := QualifyLinks(file.Content, group.Title, target)
+		if count == 0 {
+			continue
+		}
+
+		file.Content = content
+		qualifications = append(qualifications, LinkQualification{File: file, Target: target, Count: count})
+	}
+
+	return qualifications
+}
@@ -0,0 +1,103 @@
+package scripts
+
+import (
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse("2006-01-02", value)
+	return parsed
+}
+
+func TestFindDuplicateTitles(t *testing.T) {
+	files := []File{
+		{Name: "standup-2025-01-10.md", Title: "standup", CreatedAt: date("2025-01-10")},
+		{Name: "plan.md", Title: "Plan", CreatedAt: date("2025-01-01")},
+		{Name: "standup-2025-01-03.md", Title: "Standup", CreatedAt: date("2025-01-03")},
+		{Name: "other.md", Title: "Other", CreatedAt: date("2025-01-01")},
+	}
+
+	groups := FindDuplicateTitles(files)
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 group, got %d", len(groups))
+	}
+	if len(groups[0].Files) != 2 || groups[0].Files[0].Name != "standup-2025-01-03.md" {
+		t.Errorf("Expected both standups, oldest first, got %v", groups[0].Files)
+	}
+}
+
+func TestPlanDatedTitles(t *testing.T) {
+	group := DuplicateTitleGroup{Title: "standup", Files: []File{
+		{Name: "standup-2025-01-03.md", Title: "standup", CreatedAt: date("2025-01-03"), Content: "# standup\n\nNotes"},
+		{Name: "standup-2025-01-03-1.md", Title: "standup", CreatedAt: date("2025-01-03"), Content: "# standup\n"},
+		{Name: "standup-2025-01-10.md", Title: "standup", CreatedAt: date("2025-01-10"), Content: "# standup\n"},
+	}}
+
+	renamed := PlanDatedTitles(group, group.Files)
+	if len(renamed) != 2 {
+		t.Fatalf("Expected the two older notes to be renamed, got %d", len(renamed))
+	}
+	if renamed[0].Title != "standup 2025-01-03" || renamed[0].Content != "# standup 2025-01-03\n\nNotes" {
+		t.Errorf("Expected dated title and heading, got '%s' '%s'", renamed[0].Title, renamed[0].Content)
+	}
+	if renamed[1].Title != "standup-2025-01-03-1" {
+		t.Errorf("Expected filename as title when the dated title is taken, got '%s'", renamed[1].Title)
+	}
+}
+
+func TestSuggestLinkTarget(t *testing.T) {
+	candidates := []File{
+		{Name: "a.md", CreatedAt: date("2025-01-03")},
+		{Name: "b.md", CreatedAt: date("2025-01-10")},
+	}
+
+	tests := []struct {
+		created  string
+		expected string
+	}{
+		{created: "2025-01-01", expected: "a.md"},
+		{created: "2025-01-05", expected: "a.md"},
+		{created: "2025-01-10", expected: "b.md"},
+		{created: "2025-02-01", expected: "b.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.created, func(t *testing.T) {
+			result := SuggestLinkTarget(File{CreatedAt: date(tt.created)}, candidates)
+			if result.Name != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result.Name)
+			}
+		})
+	}
+}
+
+func TestPlanLinkQualifications(t *testing.T) {
+	group := DuplicateTitleGroup{Title: "standup", Files: []File{
+		{Name: "standup-2025-01-03.md", Title: "standup", ID: "aaaa1111", CreatedAt: date("2025-01-03")},
+		{Name: "standup-2025-01-10.md", Title: "standup", ID: "bbbb2222", CreatedAt: date("2025-01-10")},
+	}}
+	files := append([]File{
+		{Name: "meeting.md", Title: "meeting", CreatedAt: date("2025-01-11"), Content: "See [[standup]] and [[Standup#Friday]]"},
+		{Name: "other.md", Title: "other", CreatedAt: date("2025-01-11"), Content: "No links"},
+	}, group.Files...)
+
+	qualifications := PlanLinkQualifications(group, files)
+	if len(qualifications) != 1 {
+		t.Fatalf("Expected 1 qualification, got %d", len(qualifications))
+	}
+	if qualifications[0].Target.Name != "standup-2025-01-10.md" || qualifications[0].Count != 2 {
+		t.Errorf("Expected 2 links to standup-2025-01-10.md, got %d to %s", qualifications[0].Count, qualifications[0].Target.Name)
+	}
+	expected := "See [[id:bbbb2222|standup]] and [[id:bbbb2222#Friday|Standup]]"
+	if qualifications[0].File.Content != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, qualifications[0].File.Content)
+	}
+
+	t.Run("Filename match is not ambiguous", func(t *testing.T) {
+		withStem := append(files, File{Name: "standup.md", Title: "daily"})
+		if result := PlanLinkQualifications(group, withStem); len(result) != 0 {
+			t.Errorf("Expected no qualifications, got %d", len(result))
+		}
+	})
+}
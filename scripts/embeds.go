@@ -0,0 +1,58 @@
+package scripts
+
+import (
+	"regexp"
+	"strings"
+)
+
+// embedPattern matches ![[note]] embed syntax
+var embedPattern = regexp.MustCompile(`!\[\[([^\]]+)\]\]`)
+
+// maxEmbedDepth stops runaway expansion of deeply nested embeds
+const maxEmbedDepth = 10
+
+// EmbedResolver finds the note an embed points at
+type EmbedResolver func(linkText string) (File, bool)
+
+// HasEmbeds checks whether content embeds other notes
+func HasEmbeds(content string) bool {
+	return embedPattern.MatchString(content)
+}
+
+// ExpandEmbeds replaces every ![[Note]] and ![[Note#Heading]] in a note with the embedded content
+// Embeds inside embedded notes are expanded too. A missing note or heading, and an embed
+// that would include a note already being expanded, leave a marker in place of the content
+func ExpandEmbeds(file File, resolve EmbedResolver) string {
+	return expandEmbeds(file.Content, resolve, []string{file.Name})
+}
+
+// expandEmbeds expands the embeds in content, stack holding the notes being expanded
+func expandEmbeds(content string, resolve EmbedResolver, stack []string) string {
+	return embedPattern.ReplaceAllStringFunc(content, func(embed string) string {
+		linkText := strings.TrimSpace(embed[3 : len(embed)-2])
+
+		target, found := resolve(linkText)
+		if !found {
+			return "[missing embed: " + linkText + "]"
+		}
+		for _, fileName := range stack {
+			if fileName == target.Name {
+				return "[embed cycle: " + linkText + "]"
+			}
+		}
+		if len(stack) > maxEmbedDepth {
+			return "[embed too deep: " + linkText + "]"
+		}
+
+		embedded := strings.Trim(target.Content, "\n")
+		if anchor, _ := ParseLinkAnchor(linkText); !anchor.IsZero() {
+			section := ExtractAnchorSection(target.Content, anchor)
+			if len(section) == 0 {
+				return "[missing embed: " + linkText + "]"
+			}
+			embedded = strings.Join(section, "\n")
+		}
+
+		return expandEmbeds(embedded, resolve, append(stack[:len(stack):len(stack)], target.Name))
+	})
+}
@@ -0,0 +1,85 @@
+package scripts
+
+import (
+	"strings"
+	"testing"
+)
+
+// resolverFor resolves embeds by title against the given notes
+func resolverFor(files ...File) EmbedResolver {
+	return func(linkText string) (File, bool) {
+		_, name := ParseLinkTarget(linkText)
+		for _, file := range files {
+			if strings.EqualFold(file.Title, name) {
+				return file, true
+			}
+		}
+		return File{}, false
+	}
+}
+
+func TestHasEmbeds(t *testing.T) {
+	if !HasEmbeds("Report\n![[Payments]]") {
+		t.Error("Expected an embed to be found")
+	}
+	if HasEmbeds("See [[Payments]]") {
+		t.Error("Expected a plain link not to count as an embed")
+	}
+}
+
+func TestExpandEmbeds_ExpandsRecursively(t *testing.T) {
+	report := File{Name: "report.md", Title: "Report", Content: "# Report\n![[Payments]]\nEnd"}
+	payments := File{Name: "payments.md", Title: "Payments", Content: "\nPayments work\n![[Android]]\n"}
+	android := File{Name: "android.md", Title: "Android", Content: "- [ ] Ship the app"}
+
+	got := ExpandEmbeds(report, resolverFor(report, payments, android))
+
+	expected := "# Report\nPayments work\n- [ ] Ship the app\nEnd"
+	if got != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, got)
+	}
+}
+
+func TestExpandEmbeds_EmbedsHeadingSection(t *testing.T) {
+	report := File{Name: "report.md", Title: "Report", Content: "![[Meeting#Plan]]"}
+	meeting := File{Name: "meeting.md", Title: "Meeting", Content: "# Meeting\n## Plan\nShip it\n## Notes\nOther"}
+
+	got := ExpandEmbeds(report, resolverFor(report, meeting))
+
+	if got != "## Plan\nShip it" {
+		t.Errorf("Expected only the Plan section, got '%s'", got)
+	}
+}
+
+func TestExpandEmbeds_MarksMissingTargets(t *testing.T) {
+	report := File{Name: "report.md", Title: "Report", Content: "![[Gone]]\n![[Meeting#Missing]]"}
+	meeting := File{Name: "meeting.md", Title: "Meeting", Content: "# Meeting"}
+
+	got := ExpandEmbeds(report, resolverFor(report, meeting))
+
+	if got != "[missing embed: Gone]\n[missing embed: Meeting#Missing]" {
+		t.Errorf("Expected missing markers, got '%s'", got)
+	}
+}
+
+func TestExpandEmbeds_StopsCycles(t *testing.T) {
+	a := File{Name: "a.md", Title: "A", Content: "A text\n![[B]]"}
+	b := File{Name: "b.md", Title: "B", Content: "B text\n![[A]]"}
+
+	got := ExpandEmbeds(a, resolverFor(a, b))
+
+	if got != "A text\nB text\n[embed cycle: A]" {
+		t.Errorf("Expected the cycle to be cut, got '%s'", got)
+	}
+}
+
+func TestExpandEmbeds_EmbedsSameNoteTwice(t *testing.T) {
+	report := File{Name: "report.md", Title: "Report", Content: "![[Note]] and ![[Note]]"}
+	note := File{Name: "note.md", Title: "Note", Content: "text"}
+
+	got := ExpandEmbeds(report, resolverFor(report, note))
+
+	if got != "text and text" {
+		t.Errorf("Expected a repeated embed not to count as a cycle, got '%s'", got)
+	}
+}
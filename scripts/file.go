@@ -22,4 +22,33 @@ type File struct {
 	Priority      Priority
 	ObjectiveRole string // "parent" or "" (empty for non-objectives)
 	ObjectiveID   string // 8-char hash linking parent and children
+	ID            string // Stable 8-char note ID, survives renames
+}
+
+// Note types, derived from the tag a note was created with
+const (
+	NoteTypeObjective = "objective"
+	NoteTypeTodo      = "todo"
+	NoteTypeMeeting   = "meeting"
+	NoteTypeStandup   = "standup"
+	NoteTypePlan      = "plan"
+	NoteTypeNote      = "note" // Any other note
+)
+
+// NoteTypes lists every note type, in the order filters cycle through them
+var NoteTypes = []string{NoteTypeObjective, NoteTypeTodo, NoteTypeMeeting, NoteTypeStandup, NoteTypePlan, NoteTypeNote}
+
+// NoteType returns the type of a note: parent objectives first, then by creation tag
+func NoteType(file File) string {
+	if file.ObjectiveRole == "parent" {
+		return NoteTypeObjective
+	}
+	for _, noteType := range NoteTypes {
+		for _, tag := range file.Tags {
+			if tag == noteType {
+				return noteType
+			}
+		}
+	}
+	return NoteTypeNote
 }
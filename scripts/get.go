@@ -10,7 +10,6 @@ import (
 // TODO refactor this code to just be domain code then hook it into main.go
 
 type GetFilesByIsDone func(isDone bool) ([]File, error)
-type GetFilesByQuery func(query string) ([]File, error)
 type GetFilesByTag func(tags []string) ([]File, error)
 
 // TODO simplify this when done refactoring
@@ -53,36 +52,6 @@ func QueryOpenTodos(queries []string, getFilesByIsDone GetFilesByIsDone) ([]File
 	return SortTodosByPriorityAndDueDate(matchingTodos), nil
 }
 
-func QueryAllFiles(queries []string, getFilesByQuery GetFilesByQuery) ([]File, error) {
-	if len(queries) < 1 {
-		return make([]File, 0), nil
-	}
-
-	files, err := getFilesByQuery(queries[0])
-	if err != nil {
-		return nil, err
-	}
-
-	if len(queries) > 1 {
-		matchingFiles := QueryFiles(queries[1:], files)
-		return matchingFiles, nil
-	} else {
-		// Sort by priority and due date if these are todos
-		hasTodos := false
-		for _, file := range files {
-			if !file.DueAt.IsZero() {
-				hasTodos = true
-				break
-			}
-		}
-
-		if hasTodos {
-			return SortTodosByPriorityAndDueDate(files), nil
-		}
-		return files, nil
-	}
-}
-
 func QueryFiles(queries []string, files []File) []File {
 	if len(queries) < 1 {
 		return make([]File, 0)
@@ -175,7 +144,12 @@ func GetUncompletedTasksInFiles(files []File) ([]string, error) {
 }
 
 func GetOverdueTodos(getFiles GetFilesByDateQuery) ([]File, error) {
-	today := time.Now().Format("2006-01-02")
+	return GetOverdueTodosAt(getFiles, time.Now())
+}
+
+// GetOverdueTodosAt returns the todos that were overdue on a day, for querying the vault as it was then
+func GetOverdueTodosAt(getFiles GetFilesByDateQuery, day time.Time) ([]File, error) {
+	today := day.Format("2006-01-02")
 	files, err := getFiles(func(dueDate string, _ time.Time) bool {
 		return dueDate <= today
 	})
@@ -1,15 +1,22 @@
 package scripts
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 )
 
 const GIT_COMMIT_INTERVAL_SECONDS = 60
 
+// gitMutex keeps the commit timer, operation commits and the remote sync from running git at the same time
+var gitMutex sync.Mutex
+
 func StartGitVersioning(dirPath string) {
 	if os.Getenv("CLI_NOTES_TEST_MODE") == "true" {
 		return
@@ -17,7 +24,7 @@ func StartGitVersioning(dirPath string) {
 
 	err := InitGitRepo(dirPath)
 	if err != nil {
-		fmt.Printf("Git versioning init error: %v\n", err)
+		slog.Error("git versioning init failed", "dir", dirPath, "err", err)
 		return
 	}
 
@@ -25,7 +32,7 @@ func StartGitVersioning(dirPath string) {
 		time.Sleep(GIT_COMMIT_INTERVAL_SECONDS * time.Second)
 		err := CommitChanges(dirPath)
 		if err != nil {
-			fmt.Printf("Git versioning error: %v\n", err)
+			slog.Error("git commit failed", "dir", dirPath, "err", err)
 		}
 	}
 }
@@ -37,7 +44,45 @@ func RunFinalGitCommit(dirPath string) {
 
 	err := CommitChanges(dirPath)
 	if err != nil {
-		fmt.Printf("Git final commit error: %v\n", err)
+		slog.Error("git final commit failed", "dir", dirPath, "err", err)
+	}
+}
+
+// RunOperationCommit commits the notes a single bulk operation touched with its own message
+// Paths are relative to dirPath; other changes in the vault are left to the commit timer
+func RunOperationCommit(dirPath, message string, paths []string) {
+	if os.Getenv("CLI_NOTES_TEST_MODE") == "true" {
+		return
+	}
+
+	err := InitGitRepo(dirPath)
+	if err != nil {
+		slog.Error("git versioning init failed", "dir", dirPath, "err", err)
+		return
+	}
+
+	err = CommitChangesWithMessage(dirPath, message, paths)
+	if err != nil {
+		slog.Error("git commit failed", "dir", dirPath, "message", message, "err", err)
+	}
+}
+
+// RunDescribedCommit commits the notes an operation changed right away, instead of waiting for the timer
+// The message names the operation and describes the note changes, e.g. "week plan: due moved: API review → Fri"
+func RunDescribedCommit(dirPath, operation string, paths []string) {
+	if os.Getenv("CLI_NOTES_TEST_MODE") == "true" {
+		return
+	}
+
+	err := InitGitRepo(dirPath)
+	if err != nil {
+		slog.Error("git versioning init failed", "dir", dirPath, "err", err)
+		return
+	}
+
+	err = commitDescribedChanges(dirPath, operation, paths)
+	if err != nil {
+		slog.Error("git commit failed", "dir", dirPath, "operation", operation, "err", err)
 	}
 }
 
@@ -71,18 +116,55 @@ func InitGitRepo(dirPath string) error {
 	return nil
 }
 
+// CommitChanges stages everything and commits it with a message describing the note changes
 func CommitChanges(dirPath string) error {
-	err := runGit(dirPath, "add", ".")
+	return commitDescribedChanges(dirPath, "", nil)
+}
+
+// commitDescribedChanges stages the paths, or everything when paths is nil, and commits them with a message
+// built from the staged notes, prefixed with the operation if there is one. Falls back to a timestamp
+// when the notes can't be described
+func commitDescribedChanges(dirPath, operation string, paths []string) error {
+	gitMutex.Lock()
+	defer gitMutex.Unlock()
+
+	return commitNoteChanges(dirPath, operation, paths)
+}
+
+// commitNoteChanges is commitDescribedChanges for callers already holding gitMutex
+func commitNoteChanges(dirPath, operation string, paths []string) error {
+	addArgs := []string{"add", "."}
+	if paths != nil {
+		paths = stageablePaths(dirPath, paths)
+		if len(paths) == 0 {
+			return nil
+		}
+		addArgs = append([]string{"add", "--all", "--"}, paths...)
+	}
+
+	err := runGit(dirPath, addArgs...)
 	if err != nil {
 		return fmt.Errorf("git add failed: %w", err)
 	}
 
-	if !hasChanges(dirPath) {
+	if !hasChanges(dirPath, paths...) {
 		return nil
 	}
 
-	msg := fmt.Sprintf("auto: %s", time.Now().Format("2006-01-02 15:04:05"))
-	err = runGit(dirPath, "commit", "-m", msg)
+	message := ""
+	if changes, err := stagedNoteChanges(dirPath, paths...); err == nil {
+		message = DescribeNoteChanges(changes, time.Now())
+	}
+	switch {
+	case operation != "" && message != "":
+		message = operation + ": " + message
+	case operation != "":
+		message = operation
+	case message == "":
+		message = fmt.Sprintf("auto: %s", time.Now().Format("2006-01-02 15:04:05"))
+	}
+
+	err = runGit(dirPath, append([]string{"commit", "-m", message, "--"}, paths...)...)
 	if err != nil {
 		return fmt.Errorf("git commit failed: %w", err)
 	}
@@ -90,6 +172,102 @@ func CommitChanges(dirPath string) error {
 	return nil
 }
 
+// stagedNoteChanges returns the staged files with their content in HEAD and in the index,
+// limited to paths when given
+func stagedNoteChanges(dirPath string, paths ...string) ([]NoteChange, error) {
+	args := []string{"diff", "--cached", "--name-status", "-M", "-z"}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	output, err := gitOutput(dirPath, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+	changes := make([]NoteChange, 0)
+	for i := 0; i < len(fields); {
+		if fields[i] == "" {
+			break
+		}
+		change := NoteChange{Status: fields[i][0]}
+		if change.Status == 'R' || change.Status == 'C' {
+			if i+2 >= len(fields) {
+				break
+			}
+			change.OldPath, change.Path = fields[i+1], fields[i+2]
+			i += 3
+		} else {
+			if i+1 >= len(fields) {
+				break
+			}
+			change.OldPath, change.Path = fields[i+1], fields[i+1]
+			i += 2
+		}
+
+		if change.Status != 'A' && change.Status != 'C' {
+			change.Before, _ = gitOutput(dirPath, "show", "HEAD:"+change.OldPath)
+		}
+		if change.Status != 'D' {
+			change.After, _ = gitOutput(dirPath, "show", ":"+change.Path)
+		}
+		if change.Status == 'C' {
+			change.Status = 'A'
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// CommitChangesWithMessage stages the given paths and commits only them with the message
+// Paths that are neither on disk nor tracked are skipped. Does nothing when there are no changes
+func CommitChangesWithMessage(dirPath, message string, paths []string) error {
+	gitMutex.Lock()
+	defer gitMutex.Unlock()
+
+	paths = stageablePaths(dirPath, paths)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	err := runGit(dirPath, append([]string{"add", "--all", "--"}, paths...)...)
+	if err != nil {
+		return fmt.Errorf("git add failed: %w", err)
+	}
+
+	if !hasChanges(dirPath, paths...) {
+		return nil
+	}
+
+	err = runGit(dirPath, append([]string{"commit", "-m", message, "--"}, paths...)...)
+	if err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// stageablePaths drops the paths git can't stage: those neither on disk nor in the index
+// A removed note that was tracked is kept, so its deletion is committed
+func stageablePaths(dirPath string, paths []string) []string {
+	stageable := make([]string, 0, len(paths))
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		if _, err := os.Stat(filepath.Join(dirPath, path)); err == nil {
+			stageable = append(stageable, path)
+			continue
+		}
+		if runGit(dirPath, "ls-files", "--error-unmatch", "--", path) == nil {
+			stageable = append(stageable, path)
+		}
+	}
+	return stageable
+}
+
 func runGit(dirPath string, args ...string) error {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = dirPath
@@ -100,8 +278,24 @@ func runGit(dirPath string, args ...string) error {
 	return nil
 }
 
-func hasChanges(dirPath string) bool {
-	cmd := exec.Command("git", "diff", "--cached", "--quiet")
+// runGitContext runs git like runGit, killing it once ctx is done
+func runGitContext(ctx context.Context, dirPath string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dirPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, output)
+	}
+	return nil
+}
+
+// hasChanges reports whether anything is staged, limited to paths when given
+func hasChanges(dirPath string, paths ...string) bool {
+	args := []string{"diff", "--cached", "--quiet"}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	cmd := exec.Command("git", args...)
 	cmd.Dir = dirPath
 	err := cmd.Run()
 	return err != nil
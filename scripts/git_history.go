@@ -0,0 +1,226 @@
+package scripts
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Separators of the commit records in git log output
+const (
+	gitRecordSeparator = "\x1e"
+	gitFieldSeparator  = "\x1f"
+	gitLogFormat       = "--format=" + gitRecordSeparator + "%H" + gitFieldSeparator + "%aI" + gitFieldSeparator + "%s"
+)
+
+// NoteVersion is a commit that changed a note
+type NoteVersion struct {
+	Hash    string
+	Date    time.Time
+	Subject string
+	Path    string // Path of the note in the commit, which differs from the current one after a rename
+	Deleted bool   // The commit deleted the note
+}
+
+// ShortHash returns the abbreviated commit hash
+func (v NoteVersion) ShortHash() string {
+	if len(v.Hash) > 7 {
+		return v.Hash[:7]
+	}
+	return v.Hash
+}
+
+// Revision returns the revision holding the note's content at this version
+// A deleting commit has no content, so its content is that of the commit before
+func (v NoteVersion) Revision() string {
+	if v.Deleted {
+		return v.Hash + "^"
+	}
+	return v.Hash
+}
+
+// GitCommit is a commit of the notes repository
+type GitCommit struct {
+	Hash    string
+	Date    time.Time
+	Subject string
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c GitCommit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// IsGitRepo checks whether the directory has been put under git
+func IsGitRepo(dirPath string) bool {
+	_, err := os.Stat(filepath.Join(dirPath, ".git"))
+	return err == nil
+}
+
+// NoteHistory returns the commits that changed a note, newest first, following renames
+// Works for deleted notes too, as long as they were committed before
+func NoteHistory(dirPath, fileName string) ([]NoteVersion, error) {
+	output, err := gitOutput(dirPath, "-c", "core.quotepath=false", "log", "--follow", gitLogFormat, "--name-status", "--", fileName)
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	return parseNoteVersions(output), nil
+}
+
+// DeletedNotes returns the latest deleting commit of every note that is no longer in the directory
+func DeletedNotes(dirPath string) ([]NoteVersion, error) {
+	output, err := gitOutput(dirPath, "-c", "core.quotepath=false", "log", "--diff-filter=D", gitLogFormat, "--name-status")
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	deleted := make([]NoteVersion, 0)
+	seen := make(map[string]bool)
+	for _, version := range parseNoteVersions(output) {
+		if !version.Deleted || !strings.HasSuffix(version.Path, ".md") || seen[version.Path] {
+			continue
+		}
+		seen[version.Path] = true
+		if _, err := os.Stat(filepath.Join(dirPath, version.Path)); err == nil {
+			continue // Deleted once, but back again
+		}
+		deleted = append(deleted, version)
+	}
+	return deleted, nil
+}
+
+// NoteVersionContent returns the content of the note at a version
+func NoteVersionContent(dirPath string, version NoteVersion) (string, error) {
+	output, err := gitOutput(dirPath, "show", version.Revision()+":"+version.Path)
+	if err != nil {
+		return "", fmt.Errorf("git show failed: %w", err)
+	}
+	return output, nil
+}
+
+// FindCommit returns the commit a revision names, or for a YYYY-MM-DD date the last commit made by the end of that day
+func FindCommit(dirPath, revision string) (GitCommit, error) {
+	args := []string{"log", "-1", gitLogFormat}
+	if day, err := time.ParseInLocation("2006-01-02", revision, time.Local); err == nil {
+		args = append(args, "--before="+day.Add(24*time.Hour-time.Second).Format("2006-01-02T15:04:05"), "HEAD")
+	} else {
+		args = append(args, revision)
+	}
+
+	output, err := gitOutput(dirPath, append(args, "--")...)
+	if err != nil {
+		return GitCommit{}, fmt.Errorf("unknown revision %s", revision)
+	}
+	fields := strings.Split(strings.TrimSpace(strings.TrimPrefix(output, gitRecordSeparator)), gitFieldSeparator)
+	if len(fields) != 3 {
+		return GitCommit{}, fmt.Errorf("no commits on or before %s", revision)
+	}
+	date, _ := time.Parse(time.RFC3339, fields[1])
+	return GitCommit{Hash: fields[0], Date: date, Subject: fields[2]}, nil
+}
+
+// NoteContentsAt returns the content of every note at a commit, by path
+// The notes are read from git objects, so the working tree is left untouched
+func NoteContentsAt(dirPath, hash string) (map[string]string, error) {
+	output, err := gitOutput(dirPath, "ls-tree", "-r", "-z", hash)
+	if err != nil {
+		return nil, fmt.Errorf("git ls-tree failed: %w", err)
+	}
+
+	paths := make([]string, 0)
+	objects := make([]string, 0)
+	for _, entry := range strings.Split(output, "\x00") {
+		// Entries are "<mode> <type> <object>\t<path>"
+		meta, path, found := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 3 || fields[1] != "blob" || !strings.HasSuffix(path, ".md") {
+			continue
+		}
+		paths = append(paths, path)
+		objects = append(objects, fields[2])
+	}
+	if len(objects) == 0 {
+		return map[string]string{}, nil
+	}
+
+	// Read every blob with a single git process
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Dir = dirPath
+	cmd.Stdin = strings.NewReader(strings.Join(objects, "\n") + "\n")
+	output, err = gitOutputOf(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file failed: %w", err)
+	}
+
+	contents := make(map[string]string, len(paths))
+	for _, path := range paths {
+		// Each blob is "<object> blob <size>\n<content>\n"
+		header, rest, found := strings.Cut(output, "\n")
+		fields := strings.Fields(header)
+		if !found || len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git cat-file output for %s", path)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil || size+1 > len(rest) {
+			return nil, fmt.Errorf("unexpected git cat-file output for %s", path)
+		}
+		contents[path] = rest[:size]
+		output = rest[size+1:]
+	}
+	return contents, nil
+}
+
+// parseNoteVersions reads git log output in gitLogFormat with --name-status
+// A commit touching several files gives one version per file
+func parseNoteVersions(output string) []NoteVersion {
+	versions := make([]NoteVersion, 0)
+	for _, record := range strings.Split(output, gitRecordSeparator) {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.Split(lines[0], gitFieldSeparator)
+		if len(fields) != 3 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[1])
+
+		for _, line := range lines[1:] {
+			status := strings.Split(strings.TrimSpace(line), "\t")
+			if len(status) < 2 {
+				continue
+			}
+			versions = append(versions, NoteVersion{
+				Hash:    fields[0],
+				Date:    date,
+				Subject: fields[2],
+				Path:    status[len(status)-1],
+				Deleted: strings.HasPrefix(status[0], "D"),
+			})
+		}
+	}
+	return versions
+}
+
+// gitOutput runs git in the directory and returns its standard output
+func gitOutput(dirPath string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dirPath
+	return gitOutputOf(cmd)
+}
+
+// gitOutputOf runs a git command and returns its standard output
+func gitOutputOf(cmd *exec.Cmd) (string, error) {
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("%s: %s", err, exitErr.Stderr)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
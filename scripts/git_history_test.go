@@ -0,0 +1,141 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNoteHistory_FollowsEditsRenamesAndDeletes(t *testing.T) {
+	dir := t.TempDir()
+	if err := InitGitRepo(dir); err != nil {
+		t.Fatalf("InitGitRepo failed: %v", err)
+	}
+
+	commit := func(message string) {
+		if err := CommitChangesWithMessage(dir, message, []string{"."}); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	write("plan.md", "# Plan\nfirst draft\nwith enough lines\nto follow a rename\n")
+	commit("create plan")
+	write("plan.md", "# Plan\nsecond draft\nwith enough lines\nto follow a rename\n")
+	commit("edit plan")
+	os.Rename(filepath.Join(dir, "plan.md"), filepath.Join(dir, "roadmap.md"))
+	commit("rename plan")
+
+	versions, err := NoteHistory(dir, "roadmap.md")
+	if err != nil {
+		t.Fatalf("NoteHistory failed: %v", err)
+	}
+	if len(versions) != 3 || versions[0].Subject != "rename plan" || versions[2].Path != "plan.md" {
+		t.Fatalf("Expected 3 versions across the rename, got %+v", versions)
+	}
+
+	content, err := NoteVersionContent(dir, versions[2])
+	if err != nil || content != "# Plan\nfirst draft\nwith enough lines\nto follow a rename\n" {
+		t.Errorf("Expected the first draft, got %q (%v)", content, err)
+	}
+
+	os.Remove(filepath.Join(dir, "roadmap.md"))
+	commit("delete roadmap")
+
+	deleted, err := DeletedNotes(dir)
+	if err != nil {
+		t.Fatalf("DeletedNotes failed: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].Path != "roadmap.md" || !deleted[0].Deleted {
+		t.Fatalf("Expected roadmap.md to be deleted, got %+v", deleted)
+	}
+	content, err = NoteVersionContent(dir, deleted[0])
+	if err != nil || content != "# Plan\nsecond draft\nwith enough lines\nto follow a rename\n" {
+		t.Errorf("Expected the content before deletion, got %q (%v)", content, err)
+	}
+}
+
+func TestNoteHistory_KeepsNonASCIINamesUnquoted(t *testing.T) {
+	dir := t.TempDir()
+	if err := InitGitRepo(dir); err != nil {
+		t.Fatalf("InitGitRepo failed: %v", err)
+	}
+
+	path := filepath.Join(dir, "café.md")
+	if err := os.WriteFile(path, []byte("# Café\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+	if err := CommitChangesWithMessage(dir, "create café", []string{"."}); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	versions, err := NoteHistory(dir, "café.md")
+	if err != nil || len(versions) != 1 || versions[0].Path != "café.md" {
+		t.Fatalf("Expected one version at café.md, got %+v (%v)", versions, err)
+	}
+	if content, err := NoteVersionContent(dir, versions[0]); err != nil || content != "# Café\n" {
+		t.Errorf("Expected the note content, got %q (%v)", content, err)
+	}
+
+	os.Remove(path)
+	if err := CommitChangesWithMessage(dir, "delete café", []string{"."}); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	deleted, err := DeletedNotes(dir)
+	if err != nil || len(deleted) != 1 || deleted[0].Path != "café.md" {
+		t.Fatalf("Expected café.md to be deleted, got %+v (%v)", deleted, err)
+	}
+}
+
+func TestFindCommitAndNoteContentsAt_ReadThePast(t *testing.T) {
+	dir := t.TempDir()
+	if err := InitGitRepo(dir); err != nil {
+		t.Fatalf("InitGitRepo failed: %v", err)
+	}
+
+	commitOn := func(date, message string) {
+		t.Setenv("GIT_AUTHOR_DATE", date+"T12:00:00")
+		t.Setenv("GIT_COMMITTER_DATE", date+"T12:00:00")
+		if err := CommitChangesWithMessage(dir, message, []string{"."}); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+
+	os.WriteFile(filepath.Join(dir, "plan.md"), []byte("first draft\n"), 0644)
+	commitOn("2025-09-30", "create plan")
+	os.WriteFile(filepath.Join(dir, "plan.md"), []byte("second draft\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "later.md"), []byte("later\n"), 0644)
+	commitOn("2025-10-02", "edit plan")
+
+	commit, err := FindCommit(dir, "2025-10-01")
+	if err != nil {
+		t.Fatalf("FindCommit failed: %v", err)
+	}
+	if commit.Subject != "create plan" {
+		t.Fatalf("Expected the last commit by 2025-10-01, got %+v", commit)
+	}
+
+	contents, err := NoteContentsAt(dir, commit.Hash)
+	if err != nil {
+		t.Fatalf("NoteContentsAt failed: %v", err)
+	}
+	if len(contents) != 1 || contents["plan.md"] != "first draft\n" {
+		t.Errorf("Expected only the first draft of plan.md, got %q", contents)
+	}
+
+	current, _ := os.ReadFile(filepath.Join(dir, "plan.md"))
+	if string(current) != "second draft\n" {
+		t.Errorf("Expected the working tree untouched, got %q", current)
+	}
+
+	if _, err := FindCommit(dir, "2020-01-01"); err == nil {
+		t.Error("Expected an error for a date before the first commit")
+	}
+	if commit, err := FindCommit(dir, "HEAD"); err != nil || commit.Subject != "edit plan" {
+		t.Errorf("Expected HEAD to be the latest commit, got %+v (%v)", commit, err)
+	}
+}
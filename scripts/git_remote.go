@@ -0,0 +1,302 @@
+package scripts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxSyncConflictRounds bounds how many rebased commits can stop on conflicts in a single sync
+const maxSyncConflictRounds = 100
+
+// RemoteSyncOptions configures syncing the notes repository with a git remote
+type RemoteSyncOptions struct {
+	Remote string // Name of the remote, the sync is skipped while the repository doesn't have it
+	Rebase bool   // Rebase local commits onto the remote, otherwise merge the remote into them
+}
+
+// RemoteSyncResult lists the notes changed on both sides that a sync had to merge
+type RemoteSyncResult struct {
+	Merged    []string // Notes merged cleanly, field by field
+	Conflicts []string // Notes left with conflict markers in their body
+}
+
+// StartRemoteSync syncs the notes with the remote every interval, when the remote is set up
+// An interval of 0 only syncs on exit
+func StartRemoteSync(dirPath string, options RemoteSyncOptions, interval time.Duration) {
+	if os.Getenv("CLI_NOTES_TEST_MODE") == "true" || interval <= 0 {
+		return
+	}
+
+	for {
+		time.Sleep(interval)
+		runRemoteSync(context.Background(), dirPath, options)
+	}
+}
+
+// RunFinalRemoteSync syncs the notes with the remote once more before exiting
+// Once ctx is done the sync is skipped, or stopped with its rebase or merge aborted
+func RunFinalRemoteSync(ctx context.Context, dirPath string, options RemoteSyncOptions) {
+	if os.Getenv("CLI_NOTES_TEST_MODE") == "true" {
+		return
+	}
+
+	runRemoteSync(ctx, dirPath, options)
+}
+
+func runRemoteSync(ctx context.Context, dirPath string, options RemoteSyncOptions) {
+	result, err := SyncWithRemote(ctx, dirPath, options)
+	if err != nil {
+		slog.Error("git sync failed", "remote", options.Remote, "err", err)
+		return
+	}
+	if len(result.Merged) > 0 {
+		slog.Info("git sync merged notes changed on both sides", "notes", strings.Join(result.Merged, ", "))
+	}
+	if len(result.Conflicts) > 0 {
+		slog.Warn("git sync left notes with conflicts, run conflicts to list them", "notes", strings.Join(result.Conflicts, ", "))
+	}
+}
+
+// SyncWithRemote commits local changes, fetches the remote, rebases or merges onto it and pushes
+// Notes changed on both sides are merged with MergeNote, so the sync always completes;
+// notes whose bodies could not be merged keep conflict markers and are listed in the result
+// Does nothing when the repository is not under git or has no such remote
+// Cancelling ctx stops the fetch or push, and aborts a rebase or merge between its steps
+func SyncWithRemote(ctx context.Context, dirPath string, options RemoteSyncOptions) (RemoteSyncResult, error) {
+	result := RemoteSyncResult{}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	if !IsGitRepo(dirPath) || !hasRemote(dirPath, options.Remote) {
+		return result, nil
+	}
+
+	branch, err := commitForSync(dirPath)
+	if err != nil {
+		return result, err
+	}
+
+	// The fetch and push run outside gitMutex, so notes can be committed while they wait on the network
+	if err := runGitContext(ctx, dirPath, "fetch", options.Remote); err != nil {
+		return result, fmt.Errorf("git fetch failed: %w", err)
+	}
+
+	head, err := integrateRemote(ctx, dirPath, options.Remote+"/"+branch, options.Rebase, &result)
+	if err != nil {
+		return result, err
+	}
+
+	if err := runGitContext(ctx, dirPath, "push", options.Remote, head+":refs/heads/"+branch); err != nil {
+		return result, fmt.Errorf("git push failed: %w", err)
+	}
+	return result, nil
+}
+
+// commitForSync commits the local changes and returns the checked out branch
+// A rebase or merge left over by a sync that was killed is aborted first
+func commitForSync(dirPath string) (string, error) {
+	gitMutex.Lock()
+	defer gitMutex.Unlock()
+
+	if err := abortInterruptedSync(dirPath); err != nil {
+		return "", err
+	}
+	if err := commitNoteChanges(dirPath, "", nil); err != nil {
+		return "", err
+	}
+
+	output, err := gitOutput(dirPath, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("no branch checked out: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// integrateRemote rebases or merges the fetched upstream and returns the commit to push
+// The commit is read under the lock, so the push never sees another sync's rebase half done
+func integrateRemote(ctx context.Context, dirPath, upstream string, rebase bool, result *RemoteSyncResult) (string, error) {
+	gitMutex.Lock()
+	defer gitMutex.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if _, err := gitOutput(dirPath, "rev-parse", "--verify", "--quiet", "refs/remotes/"+upstream); err == nil {
+		if rebase {
+			err = rebaseOntoRemote(ctx, dirPath, upstream, result)
+		} else {
+			err = mergeRemote(ctx, dirPath, upstream, result)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	head, err := gitOutput(dirPath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return strings.TrimSpace(head), nil
+}
+
+// rebaseOntoRemote replays the local commits on top of the remote ones
+// While rebasing, stage 2 of a conflicted file is the remote side and stage 3 the local commit
+func rebaseOntoRemote(ctx context.Context, dirPath, upstream string, result *RemoteSyncResult) error {
+	err := runGit(dirPath, "rebase", "--autostash", upstream)
+	for round := 0; err != nil; round++ {
+		if ctx.Err() != nil {
+			_ = runGit(dirPath, "rebase", "--abort")
+			return ctx.Err()
+		}
+
+		conflicted, listErr := conflictedFiles(dirPath)
+		if listErr != nil || len(conflicted) == 0 || round == maxSyncConflictRounds {
+			_ = runGit(dirPath, "rebase", "--abort")
+			return fmt.Errorf("git rebase failed: %w", err)
+		}
+
+		for _, path := range conflicted {
+			if resolveErr := resolveConflictedFile(dirPath, path, 3, 2, result); resolveErr != nil {
+				_ = runGit(dirPath, "rebase", "--abort")
+				return resolveErr
+			}
+		}
+
+		if hasChanges(dirPath) {
+			err = runGit(dirPath, "-c", "core.editor=true", "rebase", "--continue")
+		} else {
+			err = runGit(dirPath, "rebase", "--skip") // The local commit is already on the remote
+		}
+	}
+	return nil
+}
+
+// mergeRemote merges the remote commits into the local ones
+// While merging, stage 2 of a conflicted file is the local side and stage 3 the remote one
+func mergeRemote(ctx context.Context, dirPath, upstream string, result *RemoteSyncResult) error {
+	err := runGit(dirPath, "merge", "--no-edit", "--autostash", upstream)
+	if err == nil {
+		return nil
+	}
+
+	conflicted, listErr := conflictedFiles(dirPath)
+	if listErr != nil || len(conflicted) == 0 {
+		_ = runGit(dirPath, "merge", "--abort")
+		return fmt.Errorf("git merge failed: %w", err)
+	}
+	for _, path := range conflicted {
+		if err := resolveConflictedFile(dirPath, path, 2, 3, result); err != nil {
+			_ = runGit(dirPath, "merge", "--abort")
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		_ = runGit(dirPath, "merge", "--abort")
+		return ctx.Err()
+	}
+
+	if err := runGit(dirPath, "-c", "core.editor=true", "commit", "--no-edit"); err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+	return nil
+}
+
+// abortInterruptedSync aborts a rebase or merge a killed sync left in progress
+func abortInterruptedSync(dirPath string) error {
+	for _, state := range []struct{ path, command string }{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+	} {
+		path, err := gitOutput(dirPath, "rev-parse", "--git-path", state.path)
+		if err != nil {
+			return fmt.Errorf("git rev-parse failed: %w", err)
+		}
+		path = strings.TrimSpace(path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dirPath, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		slog.Warn("git sync aborting an interrupted "+state.command, "dir", dirPath)
+		if err := runGit(dirPath, state.command, "--abort"); err != nil {
+			return fmt.Errorf("git %s --abort failed: %w", state.command, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// resolveConflictedFile writes the merge of a conflicted file and stages it
+// A note deleted on one side and changed on the other is kept, so no change is lost
+func resolveConflictedFile(dirPath, path string, localStage, remoteStage int, result *RemoteSyncResult) error {
+	base, _ := indexStage(dirPath, 1, path)
+	local, hasLocal := indexStage(dirPath, localStage, path)
+	remote, hasRemote := indexStage(dirPath, remoteStage, path)
+
+	var content string
+	switch {
+	case !hasLocal && !hasRemote:
+		if err := runGit(dirPath, "rm", "--cached", "--ignore-unmatch", "--", path); err != nil {
+			return fmt.Errorf("git rm failed: %w", err)
+		}
+		return nil
+	case !hasLocal:
+		content = remote
+	case !hasRemote:
+		content = local
+	default:
+		merged, clean := MergeNote(base, local, remote)
+		content = merged
+		if clean && !containsString(result.Merged, path) {
+			result.Merged = append(result.Merged, path)
+		} else if !clean && !containsString(result.Conflicts, path) {
+			result.Conflicts = append(result.Conflicts, path)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dirPath, path), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write merged %s: %w", path, err)
+	}
+	if err := runGit(dirPath, "add", "--", path); err != nil {
+		return fmt.Errorf("git add failed: %w", err)
+	}
+	return nil
+}
+
+// conflictedFiles lists the files left unmerged by a rebase or merge
+func conflictedFiles(dirPath string) ([]string, error) {
+	output, err := gitOutput(dirPath, "diff", "--name-only", "--diff-filter=U", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, path := range strings.Split(output, "\x00") {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// indexStage returns a conflicted file's content at a merge stage: 1 base, 2 ours, 3 theirs
+func indexStage(dirPath string, stage int, path string) (string, bool) {
+	content, err := gitOutput(dirPath, "show", fmt.Sprintf(":%d:%s", stage, path))
+	return content, err == nil
+}
+
+func hasRemote(dirPath, remote string) bool {
+	if remote == "" {
+		return false
+	}
+	_, err := gitOutput(dirPath, "remote", "get-url", remote)
+	return err == nil
+}
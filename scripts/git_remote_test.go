@@ -0,0 +1,210 @@
+package scripts
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupSyncedClones creates a bare remote and two notes repositories syncing with it,
+// sharing a committed note with the given content
+func setupSyncedClones(t *testing.T, note string) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	laptop := filepath.Join(root, "laptop")
+	desktop := filepath.Join(root, "desktop")
+
+	runTestGit(t, root, "init", "--bare", remote)
+	os.MkdirAll(laptop, 0755)
+	if err := InitGitRepo(laptop); err != nil {
+		t.Fatalf("InitGitRepo failed: %v", err)
+	}
+	runTestGit(t, laptop, "remote", "add", "origin", remote)
+	os.WriteFile(filepath.Join(laptop, "task.md"), []byte(note), 0644)
+	if _, err := SyncWithRemote(context.Background(), laptop, RemoteSyncOptions{Remote: "origin", Rebase: true}); err != nil {
+		t.Fatalf("SyncWithRemote failed: %v", err)
+	}
+
+	branch := strings.TrimSpace(runTestGit(t, laptop, "symbolic-ref", "--short", "HEAD"))
+	runTestGit(t, root, "clone", "--branch", branch, remote, desktop)
+	return laptop, desktop
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
+func readTestNote(t *testing.T, dir string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, "task.md"))
+	if err != nil {
+		t.Fatalf("Failed to read note: %v", err)
+	}
+	return string(content)
+}
+
+func TestSyncWithRemote_NoRemote(t *testing.T) {
+	dir := t.TempDir()
+	InitGitRepo(dir)
+	os.WriteFile(filepath.Join(dir, "note.md"), []byte("# Note"), 0644)
+
+	result, err := SyncWithRemote(context.Background(), dir, RemoteSyncOptions{Remote: "origin", Rebase: true})
+	if err != nil {
+		t.Fatalf("SyncWithRemote without a remote failed: %v", err)
+	}
+	if len(result.Merged) != 0 || len(result.Conflicts) != 0 {
+		t.Errorf("Expected nothing merged, got %+v", result)
+	}
+	if status := runTestGit(t, dir, "status", "--porcelain"); status == "" {
+		t.Error("Expected local changes to be left for the commit timer")
+	}
+}
+
+func TestSyncWithRemote_PullsAndPushes(t *testing.T) {
+	laptop, desktop := setupSyncedClones(t, "---\ntitle: Task\ndone: false\n---\n\nBody\n")
+	options := RemoteSyncOptions{Remote: "origin", Rebase: true}
+
+	os.WriteFile(filepath.Join(desktop, "task.md"), []byte("---\ntitle: Task\ndone: true\n---\n\nBody\n"), 0644)
+	if _, err := SyncWithRemote(context.Background(), desktop, options); err != nil {
+		t.Fatalf("SyncWithRemote on desktop failed: %v", err)
+	}
+
+	if _, err := SyncWithRemote(context.Background(), laptop, options); err != nil {
+		t.Fatalf("SyncWithRemote on laptop failed: %v", err)
+	}
+	if note := readTestNote(t, laptop); !strings.Contains(note, "done: true") {
+		t.Errorf("Expected the desktop change on the laptop, got:\n%s", note)
+	}
+	if subject := runTestGit(t, laptop, "log", "-1", "--pretty=%s"); subject != "done: Task\n" {
+		t.Errorf("Expected the desktop commit on the laptop, got %q", subject)
+	}
+}
+
+func TestSyncWithRemote_MergesFrontmatterFieldByField(t *testing.T) {
+	for _, rebase := range []bool{true, false} {
+		name := "merge"
+		if rebase {
+			name = "rebase"
+		}
+		t.Run(name, func(t *testing.T) {
+			laptop, desktop := setupSyncedClones(t, "---\ntitle: Task\ndate-due: 2025-01-10\ndone: false\n---\n\nBody\n")
+			options := RemoteSyncOptions{Remote: "origin", Rebase: rebase}
+
+			os.WriteFile(filepath.Join(laptop, "task.md"), []byte("---\ntitle: Task\ndate-due: 2025-01-15\ndone: true\n---\n\nBody\n"), 0644)
+			os.WriteFile(filepath.Join(desktop, "task.md"), []byte("---\ntitle: Task\ndate-due: 2025-01-20\ndone: false\n---\n\nBody\n"), 0644)
+			if _, err := SyncWithRemote(context.Background(), laptop, options); err != nil {
+				t.Fatalf("SyncWithRemote on laptop failed: %v", err)
+			}
+
+			result, err := SyncWithRemote(context.Background(), desktop, options)
+			if err != nil {
+				t.Fatalf("SyncWithRemote on desktop failed: %v", err)
+			}
+			if len(result.Merged) != 1 || result.Merged[0] != "task.md" || len(result.Conflicts) != 0 {
+				t.Errorf("Expected task.md merged without conflicts, got %+v", result)
+			}
+
+			expected := "---\ntitle: Task\ndate-due: 2025-01-20\ndone: true\n---\n\nBody\n"
+			if note := readTestNote(t, desktop); note != expected {
+				t.Errorf("Desktop note =\n%s\nwant\n%s", note, expected)
+			}
+
+			if _, err := SyncWithRemote(context.Background(), laptop, options); err != nil {
+				t.Fatalf("Second SyncWithRemote on laptop failed: %v", err)
+			}
+			if note := readTestNote(t, laptop); note != expected {
+				t.Errorf("Laptop note =\n%s\nwant\n%s", note, expected)
+			}
+		})
+	}
+}
+
+func TestSyncWithRemote_LeavesBodyConflictMarkers(t *testing.T) {
+	laptop, desktop := setupSyncedClones(t, "---\ntitle: Task\n---\n\nAgenda\n")
+	options := RemoteSyncOptions{Remote: "origin", Rebase: true}
+
+	os.WriteFile(filepath.Join(laptop, "task.md"), []byte("---\ntitle: Task\n---\n\nAgenda from the laptop\n"), 0644)
+	os.WriteFile(filepath.Join(desktop, "task.md"), []byte("---\ntitle: Task\n---\n\nAgenda from the desktop\n"), 0644)
+	if _, err := SyncWithRemote(context.Background(), laptop, options); err != nil {
+		t.Fatalf("SyncWithRemote on laptop failed: %v", err)
+	}
+
+	result, err := SyncWithRemote(context.Background(), desktop, options)
+	if err != nil {
+		t.Fatalf("SyncWithRemote on desktop failed: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "task.md" {
+		t.Errorf("Expected task.md in conflicts, got %+v", result)
+	}
+
+	note := readTestNote(t, desktop)
+	if !HasConflictMarkers(note) || !strings.Contains(note, "Agenda from the laptop") || !strings.Contains(note, "Agenda from the desktop") {
+		t.Errorf("Expected both agendas between conflict markers, got:\n%s", note)
+	}
+	if status := runTestGit(t, desktop, "status", "--porcelain"); status != "" {
+		t.Errorf("Expected the conflicted note to be committed, got status:\n%s", status)
+	}
+
+	// The conflicted note reaches the other machine, to be fixed on either
+	if _, err := SyncWithRemote(context.Background(), laptop, options); err != nil {
+		t.Fatalf("Second SyncWithRemote on laptop failed: %v", err)
+	}
+	if !HasConflictMarkers(readTestNote(t, laptop)) {
+		t.Error("Expected the conflicted note on the laptop too")
+	}
+}
+
+func TestSyncWithRemote_SkippedOnceCancelled(t *testing.T) {
+	laptop, _ := setupSyncedClones(t, "---\ntitle: Task\n---\n\nBody\n")
+	os.WriteFile(filepath.Join(laptop, "task.md"), []byte("---\ntitle: Task\n---\n\nChanged\n"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SyncWithRemote(ctx, laptop, RemoteSyncOptions{Remote: "origin", Rebase: true}); err != context.Canceled {
+		t.Fatalf("Expected the cancelled sync to be skipped, got %v", err)
+	}
+	if status := runTestGit(t, laptop, "status", "--porcelain"); status == "" {
+		t.Error("Expected the change to be left uncommitted")
+	}
+}
+
+func TestSyncWithRemote_AbortsAnInterruptedRebase(t *testing.T) {
+	laptop, desktop := setupSyncedClones(t, "---\ntitle: Task\n---\n\nBody\n")
+	options := RemoteSyncOptions{Remote: "origin", Rebase: true}
+
+	os.WriteFile(filepath.Join(laptop, "task.md"), []byte("---\ntitle: Task\n---\n\nLaptop body\n"), 0644)
+	if _, err := SyncWithRemote(context.Background(), laptop, options); err != nil {
+		t.Fatalf("SyncWithRemote on laptop failed: %v", err)
+	}
+
+	// A sync killed while rebasing leaves the rebase stopped on the conflict
+	os.WriteFile(filepath.Join(desktop, "task.md"), []byte("---\ntitle: Task\n---\n\nDesktop body\n"), 0644)
+	runTestGit(t, desktop, "commit", "-am", "desktop change")
+	runTestGit(t, desktop, "fetch", "origin")
+	branch := strings.TrimSpace(runTestGit(t, desktop, "symbolic-ref", "--short", "HEAD"))
+	if err := runGit(desktop, "rebase", "origin/"+branch); err == nil {
+		t.Fatal("Expected the rebase to stop on the conflict")
+	}
+
+	result, err := SyncWithRemote(context.Background(), desktop, options)
+	if err != nil {
+		t.Fatalf("SyncWithRemote after the interrupted rebase failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(desktop, ".git", "rebase-merge")); err == nil {
+		t.Error("Expected the interrupted rebase to be aborted")
+	}
+	if len(result.Conflicts) != 1 || !HasConflictMarkers(readTestNote(t, desktop)) {
+		t.Errorf("Expected the sync to merge the note with conflict markers, got %+v", result)
+	}
+}
@@ -115,6 +115,121 @@ func TestCommitChanges_NoChanges(t *testing.T) {
 	}
 }
 
+func TestCommitChangesWithMessage_UsesMessage(t *testing.T) {
+	dir := t.TempDir()
+	InitGitRepo(dir)
+
+	os.WriteFile(filepath.Join(dir, "a.md"), []byte("# A"), 0644)
+	os.WriteFile(filepath.Join(dir, "b.md"), []byte("# B"), 0644)
+
+	err := CommitChangesWithMessage(dir, "tags: rename work to job", []string{"a.md", "b.md"})
+	if err != nil {
+		t.Fatalf("CommitChangesWithMessage failed: %v", err)
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--pretty=%s", "--name-only")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git log failed: %v\n%s", err, output)
+	}
+	if !contains(string(output), "tags: rename work to job") {
+		t.Errorf("Expected commit message in log, got:\n%s", output)
+	}
+	if !contains(string(output), "a.md") || !contains(string(output), "b.md") {
+		t.Errorf("Expected both files in a single commit, got:\n%s", output)
+	}
+}
+
+func TestCommitChangesWithMessage_CommitsOnlyGivenPaths(t *testing.T) {
+	dir := t.TempDir()
+	InitGitRepo(dir)
+
+	os.WriteFile(filepath.Join(dir, "old.md"), []byte("# Old"), 0644)
+	CommitChanges(dir)
+
+	os.WriteFile(filepath.Join(dir, "a.md"), []byte("# A"), 0644)
+	os.WriteFile(filepath.Join(dir, "unrelated.md"), []byte("# Unrelated"), 0644)
+	os.Remove(filepath.Join(dir, "old.md"))
+
+	err := CommitChangesWithMessage(dir, "tags: rename work to job", []string{"a.md", "old.md", "missing.md"})
+	if err != nil {
+		t.Fatalf("CommitChangesWithMessage failed: %v", err)
+	}
+
+	cmd := exec.Command("git", "show", "--pretty=", "--name-status", "HEAD")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git show failed: %v\n%s", err, output)
+	}
+	if !contains(string(output), "A\ta.md") || !contains(string(output), "D\told.md") {
+		t.Errorf("Expected the added and the removed note in the commit, got:\n%s", output)
+	}
+	if contains(string(output), "unrelated.md") {
+		t.Errorf("Expected unrelated changes to stay out of the commit, got:\n%s", output)
+	}
+}
+
+func TestCommitChanges_DescribesNoteChanges(t *testing.T) {
+	dir := t.TempDir()
+	InitGitRepo(dir)
+
+	todoPath := filepath.Join(dir, "fix-login.md")
+	os.WriteFile(todoPath, []byte("---\ntitle: Fix login bug\ndone: false\n---\n"), 0644)
+	CommitChanges(dir)
+
+	os.WriteFile(todoPath, []byte("---\ntitle: Fix login bug\ndone: true\n---\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "standup-2025-11-28.md"), []byte("---\ntitle: Standup\n---\n"), 0644)
+
+	err := CommitChanges(dir)
+	if err != nil {
+		t.Fatalf("CommitChanges failed: %v", err)
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--pretty=%s")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git log failed: %v\n%s", err, output)
+	}
+	expected := "done: Fix login bug; created: standup-2025-11-28\n"
+	if string(output) != expected {
+		t.Errorf("Commit message = %q, want %q", output, expected)
+	}
+}
+
+func TestRunDescribedCommit_PrefixesOperation(t *testing.T) {
+	t.Setenv("CLI_NOTES_TEST_MODE", "")
+	dir := t.TempDir()
+	InitGitRepo(dir)
+
+	notePath := filepath.Join(dir, "review.md")
+	os.WriteFile(notePath, []byte("---\ntitle: API review\ndate-due: 2020-01-01\n---\n"), 0644)
+	CommitChanges(dir)
+
+	os.WriteFile(notePath, []byte("---\ntitle: API review\ndate-due: 2020-02-03\n---\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "unrelated.md"), []byte("# Unrelated"), 0644)
+	RunDescribedCommit(dir, "week plan", []string{"review.md"})
+
+	cmd := exec.Command("git", "log", "-1", "--pretty=%s")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git log failed: %v\n%s", err, output)
+	}
+	expected := "week plan: due moved: API review → 2020-02-03\n"
+	if string(output) != expected {
+		t.Errorf("Commit message = %q, want %q", output, expected)
+	}
+
+	cmd = exec.Command("git", "status", "--porcelain")
+	cmd.Dir = dir
+	if status, _ := cmd.CombinedOutput(); string(status) != "?? unrelated.md\n" {
+		t.Errorf("Expected only the unrelated note left uncommitted, got %q", status)
+	}
+}
+
 func TestCommitChanges_ModifiedFiles(t *testing.T) {
 	dir := t.TempDir()
 	InitGitRepo(dir)
@@ -0,0 +1,221 @@
+package scripts
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Graph export formats
+const (
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+	GraphFormatJSON    = "json"
+)
+
+// Graph export edge kinds
+const (
+	GraphEdgeLink      = "link"      // A [[link]] from one note to another
+	GraphEdgeObjective = "objective" // A parent objective to one of its child todos
+	GraphEdgeTag       = "tag"       // A note to one of its tags
+)
+
+// GraphTagType is the type of the tag nodes in an exported graph
+const GraphTagType = "tag"
+
+// GraphExportNode is a note or tag in an exported graph
+type GraphExportNode struct {
+	ID       string `json:"id"` // Filename for notes, "tag:<tag>" for tags
+	Title    string `json:"title"`
+	Type     string `json:"type"` // Note type from NoteType, or GraphTagType
+	Done     bool   `json:"done"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+// GraphExportEdge connects two nodes of an exported graph
+type GraphExportEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// GraphExport is a note graph ready to be written in an export format
+type GraphExport struct {
+	Nodes []GraphExportNode `json:"nodes"`
+	Edges []GraphExportEdge `json:"edges"`
+}
+
+// NewGraphExportNode returns the export node of a note
+func NewGraphExportNode(file File) GraphExportNode {
+	title := file.Title
+	if title == "" {
+		title = strings.TrimSuffix(file.Name, ".md")
+	}
+	return GraphExportNode{
+		ID:       file.Name,
+		Title:    title,
+		Type:     NoteType(file),
+		Done:     file.Done,
+		Priority: int(file.Priority),
+	}
+}
+
+// TagNodeID returns the node ID of a tag in an exported graph
+func TagNodeID(tag string) string {
+	return GraphTagType + ":" + tag
+}
+
+// FormatGraph writes a graph in the given format: dot, mermaid or json
+func FormatGraph(graph GraphExport, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case GraphFormatDOT:
+		return FormatGraphDOT(graph), nil
+	case GraphFormatMermaid:
+		return FormatGraphMermaid(graph), nil
+	case GraphFormatJSON:
+		return FormatGraphJSON(graph)
+	default:
+		return "", fmt.Errorf("unknown graph format %q, use dot, mermaid or json", format)
+	}
+}
+
+// FormatGraphDOT writes a graph as a Graphviz digraph
+// Node attributes carry type, done and priority, and are styled by them:
+// done notes are greyed out, P1 notes outlined in red, tags drawn as ellipses
+func FormatGraphDOT(graph GraphExport) string {
+	var sb strings.Builder
+	sb.WriteString("digraph notes {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	for _, node := range graph.Nodes {
+		attributes := []string{
+			"label=" + dotQuote(node.Title),
+			"type=" + dotQuote(node.Type),
+			fmt.Sprintf("done=%t", node.Done),
+		}
+		if node.Priority > 0 {
+			attributes = append(attributes, fmt.Sprintf("priority=%d", node.Priority))
+		}
+		attributes = append(attributes, dotNodeStyle(node)...)
+		sb.WriteString(fmt.Sprintf("  %s [%s];\n", dotQuote(node.ID), strings.Join(attributes, ", ")))
+	}
+
+	for _, edge := range graph.Edges {
+		attributes := []string{"kind=" + dotQuote(edge.Kind)}
+		switch edge.Kind {
+		case GraphEdgeObjective:
+			attributes = append(attributes, "style=bold")
+		case GraphEdgeTag:
+			attributes = append(attributes, "style=dotted", "arrowhead=none")
+		}
+		sb.WriteString(fmt.Sprintf("  %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To), strings.Join(attributes, ", ")))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotNodeStyle returns the Graphviz styling of a node
+func dotNodeStyle(node GraphExportNode) []string {
+	style := make([]string, 0)
+	switch node.Type {
+	case GraphTagType:
+		style = append(style, "shape=ellipse")
+	case NoteTypeObjective:
+		style = append(style, "shape=box3d")
+	}
+	if node.Done {
+		style = append(style, "style=filled", `fillcolor="lightgrey"`, `fontcolor="grey40"`)
+	}
+	if node.Priority == int(P1) && !node.Done {
+		style = append(style, `color="red"`)
+	}
+	return style
+}
+
+// dotQuote quotes a DOT identifier or string
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// FormatGraphMermaid writes a graph as a Mermaid flowchart
+// Nodes get classes by type plus "done" and "p1", styled at the end of the chart
+func FormatGraphMermaid(graph GraphExport) string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+
+	ids := make(map[string]string, len(graph.Nodes))
+	classes := make(map[string][]string)
+	for i, node := range graph.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.ID] = id
+
+		if node.Type == GraphTagType {
+			sb.WriteString(fmt.Sprintf("  %s([%s])\n", id, mermaidQuote("#"+node.Title)))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %s[%s]\n", id, mermaidQuote(node.Title)))
+		}
+
+		classes[node.Type] = append(classes[node.Type], id)
+		if node.Done {
+			classes["done"] = append(classes["done"], id)
+		}
+		if node.Priority == int(P1) && !node.Done {
+			classes["p1"] = append(classes["p1"], id)
+		}
+	}
+
+	for _, edge := range graph.Edges {
+		from, to := ids[edge.From], ids[edge.To]
+		if from == "" || to == "" {
+			continue
+		}
+		switch edge.Kind {
+		case GraphEdgeObjective:
+			sb.WriteString(fmt.Sprintf("  %s ==>|objective| %s\n", from, to))
+		case GraphEdgeTag:
+			sb.WriteString(fmt.Sprintf("  %s -.- %s\n", from, to))
+		default:
+			sb.WriteString(fmt.Sprintf("  %s --> %s\n", from, to))
+		}
+	}
+
+	classNames := make([]string, 0, len(classes))
+	for class := range classes {
+		classNames = append(classNames, class)
+	}
+	sort.Strings(classNames)
+	for _, class := range classNames {
+		sb.WriteString(fmt.Sprintf("  class %s %s\n", strings.Join(classes[class], ","), class))
+	}
+
+	sb.WriteString("  classDef done fill:#eee,color:#888\n")
+	sb.WriteString("  classDef p1 stroke:#d33,stroke-width:2px\n")
+	sb.WriteString("  classDef objective stroke-width:3px\n")
+	sb.WriteString("  classDef tag fill:#eef,stroke-dasharray:3\n")
+
+	return sb.String()
+}
+
+// mermaidQuote quotes a Mermaid node label
+func mermaidQuote(label string) string {
+	return `"` + strings.ReplaceAll(label, `"`, "#quot;") + `"`
+}
+
+// FormatGraphJSON writes a graph as a JSON node and edge list
+func FormatGraphJSON(graph GraphExport) (string, error) {
+	if graph.Nodes == nil {
+		graph.Nodes = []GraphExportNode{}
+	}
+	if graph.Edges == nil {
+		graph.Edges = []GraphExportEdge{}
+	}
+
+	encoded, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(encoded) + "\n", nil
+}
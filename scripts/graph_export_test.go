@@ -0,0 +1,108 @@
+package scripts
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testGraphExport() GraphExport {
+	return GraphExport{
+		Nodes: []GraphExportNode{
+			NewGraphExportNode(File{Name: "launch.md", Title: "Launch", ObjectiveRole: "parent", ObjectiveID: "abcd1234", Priority: P1}),
+			NewGraphExportNode(File{Name: "ship.md", Title: `Ship "it"`, Tags: []string{"todo"}, Done: true, Priority: P2}),
+			{ID: TagNodeID("work"), Title: "work", Type: GraphTagType},
+		},
+		Edges: []GraphExportEdge{
+			{From: "launch.md", To: "ship.md", Kind: GraphEdgeObjective},
+			{From: "ship.md", To: "launch.md", Kind: GraphEdgeLink},
+			{From: "ship.md", To: "tag:work", Kind: GraphEdgeTag},
+		},
+	}
+}
+
+func TestNewGraphExportNode_SetsAttributes(t *testing.T) {
+	node := NewGraphExportNode(File{Name: "untitled.md", Tags: []string{"meeting"}, Done: true, Priority: P3})
+
+	if node.ID != "untitled.md" || node.Title != "untitled" || node.Type != NoteTypeMeeting || !node.Done || node.Priority != 3 {
+		t.Errorf("Unexpected node %+v", node)
+	}
+}
+
+func TestFormatGraphDOT_WritesNodesEdgesAndStyles(t *testing.T) {
+	dot := FormatGraphDOT(testGraphExport())
+
+	for _, expected := range []string{
+		"digraph notes {",
+		`"launch.md" [label="Launch", type="objective", done=false, priority=1, shape=box3d, color="red"];`,
+		`"ship.md" [label="Ship \"it\"", type="todo", done=true, priority=2, style=filled`,
+		`"tag:work" [label="work", type="tag", done=false, shape=ellipse];`,
+		`"launch.md" -> "ship.md" [kind="objective", style=bold];`,
+		`"ship.md" -> "launch.md" [kind="link"];`,
+		`"ship.md" -> "tag:work" [kind="tag", style=dotted, arrowhead=none];`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("Expected %q in DOT output:\n%s", expected, dot)
+		}
+	}
+}
+
+func TestFormatGraphMermaid_WritesNodesEdgesAndClasses(t *testing.T) {
+	mermaid := FormatGraphMermaid(testGraphExport())
+
+	for _, expected := range []string{
+		"flowchart LR",
+		`n0["Launch"]`,
+		`n1["Ship #quot;it#quot;"]`,
+		`n2(["#work"])`,
+		"n0 ==>|objective| n1",
+		"n1 --> n0",
+		"n1 -.- n2",
+		"class n1 done",
+		"class n0 objective",
+		"class n0 p1",
+		"class n2 tag",
+		"classDef done",
+	} {
+		if !strings.Contains(mermaid, expected) {
+			t.Errorf("Expected %q in Mermaid output:\n%s", expected, mermaid)
+		}
+	}
+}
+
+func TestFormatGraphJSON_RoundTrips(t *testing.T) {
+	output, err := FormatGraphJSON(testGraphExport())
+	if err != nil {
+		t.Fatalf("FormatGraphJSON failed: %v", err)
+	}
+
+	var decoded GraphExport
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, output)
+	}
+	if len(decoded.Nodes) != 3 || len(decoded.Edges) != 3 {
+		t.Fatalf("Expected 3 nodes and 3 edges, got %+v", decoded)
+	}
+	if decoded.Nodes[1].Type != NoteTypeTodo || !decoded.Nodes[1].Done || decoded.Edges[0].Kind != GraphEdgeObjective {
+		t.Errorf("Unexpected decoded graph %+v", decoded)
+	}
+}
+
+func TestFormatGraphJSON_EmptyGraphHasEmptyLists(t *testing.T) {
+	output, err := FormatGraphJSON(GraphExport{})
+	if err != nil {
+		t.Fatalf("FormatGraphJSON failed: %v", err)
+	}
+	if !strings.Contains(output, `"nodes": []`) || !strings.Contains(output, `"edges": []`) {
+		t.Errorf("Expected empty lists, got %s", output)
+	}
+}
+
+func TestFormatGraph_RejectsUnknownFormat(t *testing.T) {
+	if _, err := FormatGraph(GraphExport{}, "png"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+	if output, err := FormatGraph(GraphExport{}, "DOT"); err != nil || !strings.HasPrefix(output, "digraph") {
+		t.Errorf("Expected formats to be case-insensitive, got %q, %v", output, err)
+	}
+}
@@ -0,0 +1,312 @@
+package scripts
+
+import (
+	"regexp"
+	"strings"
+)
+
+// wikiLinkPattern matches [[link text]] syntax
+var wikiLinkPattern = regexp.MustCompile(`\[\[([^\]]+)\]\]`)
+
+// IDLinkPrefix marks a link target that refers to a note ID, e.g. [[id:1a2b3c4d|Title]]
+const IDLinkPrefix = "id:"
+
+// LinkRename is how the links to a renamed note change
+type LinkRename struct {
+	targets  map[string]string // Lowercase old title or filename -> the new one
+	id       string            // ID of the note, whose ID-backed links keep their target
+	oldTitle string
+	newTitle string
+}
+
+// NewLinkRename maps the old title and filename of a note to the new ones
+// Links by title keep pointing at the title, links by filename at the filename,
+// and ID-backed links labelled with the old title get the new one
+func NewLinkRename(oldFile, newFile File) LinkRename {
+	rename := LinkRename{targets: make(map[string]string), id: strings.ToLower(newFile.ID)}
+
+	oldName := strings.TrimSuffix(oldFile.Name, ".md")
+	newName := strings.TrimSuffix(newFile.Name, ".md")
+	if oldName != "" && oldName != newName {
+		rename.targets[strings.ToLower(oldName)] = newName
+	}
+	if oldFile.Title != "" && oldFile.Title != newFile.Title {
+		rename.targets[strings.ToLower(oldFile.Title)] = newFile.Title
+		rename.oldTitle, rename.newTitle = oldFile.Title, newFile.Title
+	}
+
+	return rename
+}
+
+// relabel returns the link with the new title as label, for an ID-backed link to the
+// renamed note still labelled with the old title. Other labels are the user's own and kept
+func (r LinkRename) relabel(target, rest string) (string, bool) {
+	if r.id == "" || r.oldTitle == "" || !strings.HasPrefix(strings.ToLower(target), IDLinkPrefix) {
+		return "", false
+	}
+	if strings.ToLower(strings.TrimSpace(target[len(IDLinkPrefix):])) != r.id {
+		return "", false
+	}
+
+	labelStart := strings.LastIndex(rest, "|")
+	if labelStart < 0 || !strings.EqualFold(strings.TrimSpace(rest[labelStart+1:]), r.oldTitle) {
+		return "", false
+	}
+	return "[[" + target + rest[:labelStart+1] + r.newTitle + "]]", true
+}
+
+// SplitLinkText splits link text into its target and the rest of the link
+// e.g. "Note#Heading|alias" -> ("Note", "#Heading|alias")
+func SplitLinkText(linkText string) (string, string) {
+	end := strings.IndexAny(linkText, "#^|")
+	if end < 0 {
+		return linkText, ""
+	}
+	return linkText[:end], linkText[end:]
+}
+
+// ParseLinkTarget returns the note ID and the title or filename a link points at
+// For ID-backed links the name is the label, used when the ID is unknown
+// e.g. "id:1a2b3c4d|Title" -> ("1a2b3c4d", "Title"), "Note#Heading" -> ("", "Note")
+func ParseLinkTarget(linkText string) (string, string) {
+	target, rest := SplitLinkText(strings.TrimSpace(linkText))
+	target = strings.TrimSpace(target)
+
+	if !strings.HasPrefix(strings.ToLower(target), IDLinkPrefix) {
+		return "", target
+	}
+
+	id := strings.ToLower(strings.TrimSpace(target[len(IDLinkPrefix):]))
+	name := ""
+	if labelStart := strings.LastIndex(rest, "|"); labelStart >= 0 {
+		name = strings.TrimSpace(rest[labelStart+1:])
+	}
+	return id, name
+}
+
+// LinkAnchor is the heading or block inside a note that a link points at
+type LinkAnchor struct {
+	Heading string // [[Note#Heading]]
+	BlockID string // [[Note^block-id]] or [[Note#^block-id]]
+}
+
+// IsZero reports whether the link points at the whole note
+func (a LinkAnchor) IsZero() bool {
+	return a.Heading == "" && a.BlockID == ""
+}
+
+// ParseLinkAnchor returns the heading or block a link points at and its alias text
+// e.g. "Note#Heading|see here" -> ({Heading: "Heading"}, "see here"), "Note^abc" -> ({BlockID: "abc"}, "")
+func ParseLinkAnchor(linkText string) (LinkAnchor, string) {
+	_, rest := SplitLinkText(strings.TrimSpace(linkText))
+
+	alias := ""
+	if aliasStart := strings.Index(rest, "|"); aliasStart >= 0 {
+		alias = strings.TrimSpace(rest[aliasStart+1:])
+		rest = rest[:aliasStart]
+	}
+
+	anchor := LinkAnchor{}
+	switch {
+	case strings.HasPrefix(rest, "#^"):
+		anchor.BlockID = strings.TrimSpace(rest[2:])
+	case strings.HasPrefix(rest, "^"):
+		anchor.BlockID = strings.TrimSpace(rest[1:])
+	case strings.HasPrefix(rest, "#"):
+		anchor.Heading = strings.TrimSpace(rest[1:])
+	}
+	return anchor, alias
+}
+
+// FormatLink returns the link text for a target with an optional anchor and alias
+// e.g. ("Note", {Heading: "Plan"}, "the plan") -> "Note#Plan|the plan"
+func FormatLink(target string, anchor LinkAnchor, alias string) string {
+	linkText := target
+	if anchor.Heading != "" {
+		linkText += "#" + anchor.Heading
+	} else if anchor.BlockID != "" {
+		linkText += "^" + anchor.BlockID
+	}
+	if alias != "" {
+		linkText += "|" + alias
+	}
+	return linkText
+}
+
+// FindAnchorLine returns the 1-based line of content the anchor points at, or 0 when it is missing
+// Headings match case-insensitively, blocks by a trailing ^block-id marker
+func FindAnchorLine(content string, anchor LinkAnchor) int {
+	if anchor.IsZero() {
+		return 0
+	}
+
+	for i, line := range strings.Split(content, "\n") {
+		if anchor.Heading != "" {
+			if level, text := parseHeading(line); level > 0 && strings.EqualFold(text, anchor.Heading) {
+				return i + 1
+			}
+			continue
+		}
+		if blockIDOf(line) == anchor.BlockID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ExtractAnchorSection returns the lines of the section the anchor points at
+// A heading's section runs to the next heading of the same or higher level,
+// a block is the paragraph holding the block ID, without the marker
+func ExtractAnchorSection(content string, anchor LinkAnchor) []string {
+	line := FindAnchorLine(content, anchor)
+	if line == 0 {
+		return []string{}
+	}
+
+	lines := strings.Split(content, "\n")
+	start := line - 1
+
+	if anchor.Heading != "" {
+		level, _ := parseHeading(lines[start])
+		end := start + 1
+		for end < len(lines) {
+			if nextLevel, _ := parseHeading(lines[end]); nextLevel > 0 && nextLevel <= level {
+				break
+			}
+			end++
+		}
+		return trimBlankLines(lines[start:end])
+	}
+
+	// A block ID on its own line marks the paragraph above it
+	end := start + 1
+	if strings.TrimSpace(lines[start]) == "^"+anchor.BlockID {
+		end = start
+	}
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		if level, _ := parseHeading(lines[start-1]); level > 0 {
+			break
+		}
+		start--
+	}
+
+	section := make([]string, 0, end-start)
+	for _, sectionLine := range lines[start:end] {
+		section = append(section, strings.TrimSuffix(strings.TrimRight(sectionLine, " "), " ^"+anchor.BlockID))
+	}
+	return section
+}
+
+// parseHeading returns the level and text of a markdown heading line, or 0 for other lines
+func parseHeading(line string) (int, string) {
+	trimmedLine := strings.TrimSpace(line)
+	level := 0
+	for level < len(trimmedLine) && trimmedLine[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(trimmedLine) && trimmedLine[level] != ' ') {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(trimmedLine[level:])
+}
+
+// blockIDOf returns the ^block-id marker at the end of a line, if any
+func blockIDOf(line string) string {
+	trimmedLine := strings.TrimSpace(line)
+	markerStart := strings.LastIndex(trimmedLine, "^")
+	if markerStart < 0 || (markerStart > 0 && trimmedLine[markerStart-1] != ' ') {
+		return ""
+	}
+	blockID := trimmedLine[markerStart+1:]
+	if blockID == "" || strings.ContainsAny(blockID, " \t") {
+		return ""
+	}
+	return blockID
+}
+
+// trimBlankLines drops the blank lines at the end of a section
+func trimBlankLines(lines []string) []string {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return lines[:end]
+}
+
+// FormatIDLink returns the link text of an ID-backed link to a note, labelled with its title
+func FormatIDLink(file File) string {
+	label := file.Title
+	if label == "" {
+		label = strings.TrimSuffix(file.Name, ".md")
+	}
+	return IDLinkPrefix + file.ID + "|" + label
+}
+
+// RewriteLinks points every link to a renamed target at its new target
+// Headings, block references and alias text are kept. ID-backed links keep their target,
+// and only those labelled with the old title are relabelled
+// Returns the new content and the number of links rewritten
+func RewriteLinks(content string, rename LinkRename) (string, int) {
+	if len(rename.targets) == 0 {
+		return content, 0
+	}
+
+	count := 0
+	rewritten := wikiLinkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		target, rest := SplitLinkText(link[2 : len(link)-2])
+
+		if relabelled, ok := rename.relabel(target, rest); ok {
+			count++
+			return relabelled
+		}
+
+		newTarget, ok := rename.targets[strings.ToLower(strings.TrimSpace(target))]
+		if !ok {
+			return link
+		}
+
+		count++
+		return "[[" + newTarget + rest + "]]"
+	})
+
+	return rewritten, count
+}
+
+// QualifyLinks turns links to a title into ID-backed links to the target note
+// Headings and alias text are kept, the title becomes the label when there is none
+// Returns the new content and the number of links qualified
+func QualifyLinks(content string, title string, target File) (string, int) {
+	count := 0
+	qualified := wikiLinkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		linkTarget, rest := SplitLinkText(link[2 : len(link)-2])
+		linkTarget = strings.TrimSpace(linkTarget)
+		if !strings.EqualFold(linkTarget, title) {
+			return link
+		}
+
+		if !strings.Contains(rest, "|") {
+			rest += "|" + linkTarget
+		}
+		count++
+		return "[[" + IDLinkPrefix + target.ID + rest + "]]"
+	})
+
+	return qualified, count
+}
+
+// PlanLinkRewrites returns the files whose links change after a rename, with the new content
+func PlanLinkRewrites(files []File, rename LinkRename) []File {
+	updated := make([]File, 0)
+
+	for _, file := range files {
+		content, count := RewriteLinks(file.Content, rename)
+		if count == 0 {
+			continue
+		}
+
+		file.Content = content
+		updated = append(updated, file)
+	}
+
+	return updated
+}
@@ -0,0 +1,276 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitLinkText(t *testing.T) {
+	tests := []struct {
+		linkText   string
+		wantTarget string
+		wantRest   string
+	}{
+		{linkText: "Note", wantTarget: "Note", wantRest: ""},
+		{linkText: "Note|alias", wantTarget: "Note", wantRest: "|alias"},
+		{linkText: "Note#Heading", wantTarget: "Note", wantRest: "#Heading"},
+		{linkText: "Note^block|alias", wantTarget: "Note", wantRest: "^block|alias"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.linkText, func(t *testing.T) {
+			target, rest := SplitLinkText(tt.linkText)
+			if target != tt.wantTarget || rest != tt.wantRest {
+				t.Errorf("Expected (%s, %s), got (%s, %s)", tt.wantTarget, tt.wantRest, target, rest)
+			}
+		})
+	}
+}
+
+func TestParseLinkTarget(t *testing.T) {
+	tests := []struct {
+		linkText string
+		wantID   string
+		wantName string
+	}{
+		{linkText: "Note", wantID: "", wantName: "Note"},
+		{linkText: "Note#Heading|alias", wantID: "", wantName: "Note"},
+		{linkText: "id:1a2b3c4d|Meeting", wantID: "1a2b3c4d", wantName: "Meeting"},
+		{linkText: "ID:1A2B3C4D", wantID: "1a2b3c4d", wantName: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.linkText, func(t *testing.T) {
+			id, name := ParseLinkTarget(tt.linkText)
+			if id != tt.wantID || name != tt.wantName {
+				t.Errorf("Expected (%s, %s), got (%s, %s)", tt.wantID, tt.wantName, id, name)
+			}
+		})
+	}
+}
+
+func TestFormatIDLink(t *testing.T) {
+	link := FormatIDLink(File{Name: "meeting-1.md", Title: "Meeting", ID: "1a2b3c4d"})
+	if link != "id:1a2b3c4d|Meeting" {
+		t.Errorf("Expected 'id:1a2b3c4d|Meeting', got '%s'", link)
+	}
+}
+
+func TestQualifyLinks(t *testing.T) {
+	target := File{Name: "standup-2025-01-10.md", Title: "standup", ID: "bbbb2222"}
+
+	content, count := QualifyLinks("[[standup|this week]] [[standup-2025-01-03]] [[id:aaaa1111|standup]]", "standup", target)
+	expected := "[[id:bbbb2222|this week]] [[standup-2025-01-03]] [[id:aaaa1111|standup]]"
+	if content != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, content)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 qualified link, got %d", count)
+	}
+}
+
+func TestRewriteLinks(t *testing.T) {
+	rename := NewLinkRename(
+		File{Name: "old-title-2025-01-01.md", Title: "old-title", ID: "1a2b3c4d"},
+		File{Name: "new-title-2025-01-01.md", Title: "new-title", ID: "1a2b3c4d"},
+	)
+
+	tests := []struct {
+		name      string
+		content   string
+		expected  string
+		wantCount int
+	}{
+		{
+			name:      "Title link",
+			content:   "See [[old-title]] for details",
+			expected:  "See [[new-title]] for details",
+			wantCount: 1,
+		},
+		{
+			name:      "Case insensitive filename link",
+			content:   "[[Old-Title-2025-01-01]]",
+			expected:  "[[new-title-2025-01-01]]",
+			wantCount: 1,
+		},
+		{
+			name:      "Alias and heading are kept",
+			content:   "[[old-title#Plan|the plan]] and [[old-title|old]]",
+			expected:  "[[new-title#Plan|the plan]] and [[new-title|old]]",
+			wantCount: 2,
+		},
+		{
+			name:      "ID link labels follow the title",
+			content:   "[[id:1a2b3c4d|Old-Title]] and [[id:1A2B3C4D#Plan|old-title]]",
+			expected:  "[[id:1a2b3c4d|new-title]] and [[id:1A2B3C4D#Plan|new-title]]",
+			wantCount: 2,
+		},
+		{
+			name:      "ID links with their own label are kept",
+			content:   "[[id:1a2b3c4d|the plan]]",
+			expected:  "[[id:1a2b3c4d|the plan]]",
+			wantCount: 0,
+		},
+		{
+			name:      "ID links to a note with the same title are kept",
+			content:   "[[id:9f8e7d6c|old-title]]",
+			expected:  "[[id:9f8e7d6c|old-title]]",
+			wantCount: 0,
+		},
+		{
+			name:      "Other links untouched",
+			content:   "[[old-title-extra]] [[Other]]",
+			expected:  "[[old-title-extra]] [[Other]]",
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, count := RewriteLinks(tt.content, rename)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+			if count != tt.wantCount {
+				t.Errorf("Expected %d rewrites, got %d", tt.wantCount, count)
+			}
+		})
+	}
+}
+
+func TestRenameFileWithLinks(t *testing.T) {
+	_, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	if err := os.WriteFile(filepath.Join("notes", "old-2025-01-01.md"), []byte("# old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	originalReadLatest := readLatestFileContent
+	defer func() { readLatestFileContent = originalReadLatest }()
+	readLatestFileContent = func(f File) (File, error) {
+		return f, nil
+	}
+
+	file := File{Name: "old-2025-01-01.md", Title: "old", Content: "# old\n\nSelf link [[old]]"}
+	referencing := []File{
+		{Name: "a.md", Title: "A", Content: "See [[old|the old note]]"},
+		{Name: "b.md", Title: "B", Content: "No links here"},
+	}
+
+	var written []File
+	writeFiles := func(files []File) error {
+		written = files
+		return nil
+	}
+
+	renamed, count, err := RenameFileWithLinks("new", file, referencing, writeFiles)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if renamed.Name != "new-2025-01-01.md" {
+		t.Errorf("Expected new-2025-01-01.md, got %s", renamed.Name)
+	}
+	if renamed.Content != "# new\n\nSelf link [[new]]" {
+		t.Errorf("Expected heading and self link to be renamed, got '%s'", renamed.Content)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 updated note, got %d", count)
+	}
+	if len(written) != 2 {
+		t.Fatalf("Expected renamed note and one rewritten note in a single write, got %d", len(written))
+	}
+	if written[1].Content != "See [[new|the old note]]" {
+		t.Errorf("Expected alias to be kept, got '%s'", written[1].Content)
+	}
+	if _, err := os.Stat(filepath.Join("notes", "old-2025-01-01.md")); !os.IsNotExist(err) {
+		t.Error("Expected the old file to be removed")
+	}
+}
+
+func TestParseLinkAnchor(t *testing.T) {
+	tests := []struct {
+		linkText   string
+		wantAnchor LinkAnchor
+		wantAlias  string
+	}{
+		{linkText: "Note", wantAnchor: LinkAnchor{}, wantAlias: ""},
+		{linkText: "Note|see here", wantAnchor: LinkAnchor{}, wantAlias: "see here"},
+		{linkText: "Note#Plan", wantAnchor: LinkAnchor{Heading: "Plan"}, wantAlias: ""},
+		{linkText: "Note#Plan|the plan", wantAnchor: LinkAnchor{Heading: "Plan"}, wantAlias: "the plan"},
+		{linkText: "Note^abc-1", wantAnchor: LinkAnchor{BlockID: "abc-1"}, wantAlias: ""},
+		{linkText: "Note#^abc-1|quote", wantAnchor: LinkAnchor{BlockID: "abc-1"}, wantAlias: "quote"},
+		{linkText: "id:1a2b3c4d#Plan|Meeting", wantAnchor: LinkAnchor{Heading: "Plan"}, wantAlias: "Meeting"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.linkText, func(t *testing.T) {
+			anchor, alias := ParseLinkAnchor(tt.linkText)
+			if anchor != tt.wantAnchor || alias != tt.wantAlias {
+				t.Errorf("Expected (%+v, %s), got (%+v, %s)", tt.wantAnchor, tt.wantAlias, anchor, alias)
+			}
+		})
+	}
+}
+
+func TestFormatLink(t *testing.T) {
+	if got := FormatLink("Note", LinkAnchor{Heading: "Plan"}, "the plan"); got != "Note#Plan|the plan" {
+		t.Errorf("Expected 'Note#Plan|the plan', got '%s'", got)
+	}
+	if got := FormatLink("Note", LinkAnchor{BlockID: "abc"}, ""); got != "Note^abc" {
+		t.Errorf("Expected 'Note^abc', got '%s'", got)
+	}
+	if got := FormatLink("Note", LinkAnchor{}, ""); got != "Note" {
+		t.Errorf("Expected 'Note', got '%s'", got)
+	}
+}
+
+func TestFindAnchorLine(t *testing.T) {
+	content := "# Meeting\n\n## Plan\nShip it\n\nA quote ^abc\n#hashtag"
+
+	tests := []struct {
+		name   string
+		anchor LinkAnchor
+		want   int
+	}{
+		{name: "heading", anchor: LinkAnchor{Heading: "plan"}, want: 3},
+		{name: "block", anchor: LinkAnchor{BlockID: "abc"}, want: 6},
+		{name: "missing heading", anchor: LinkAnchor{Heading: "Notes"}, want: 0},
+		{name: "tag is not a heading", anchor: LinkAnchor{Heading: "hashtag"}, want: 0},
+		{name: "whole note", anchor: LinkAnchor{}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindAnchorLine(content, tt.anchor); got != tt.want {
+				t.Errorf("Expected line %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractAnchorSection(t *testing.T) {
+	content := "# Meeting\n\n## Plan\nShip it\n### Detail\nSmall steps\n\n## Notes\nFirst line\nA quote ^abc\n\nAfter\n\nOwn line\n^def\n"
+
+	section := ExtractAnchorSection(content, LinkAnchor{Heading: "Plan"})
+	if got := strings.Join(section, "\n"); got != "## Plan\nShip it\n### Detail\nSmall steps" {
+		t.Errorf("Expected the heading section with subheadings, got '%s'", got)
+	}
+
+	section = ExtractAnchorSection(content, LinkAnchor{BlockID: "abc"})
+	if got := strings.Join(section, "\n"); got != "First line\nA quote" {
+		t.Errorf("Expected the paragraph without the marker, got '%s'", got)
+	}
+
+	section = ExtractAnchorSection(content, LinkAnchor{BlockID: "def"})
+	if got := strings.Join(section, "\n"); got != "Own line" {
+		t.Errorf("Expected the paragraph above the marker, got '%s'", got)
+	}
+
+	if section := ExtractAnchorSection(content, LinkAnchor{Heading: "Missing"}); len(section) != 0 {
+		t.Errorf("Expected no section for a missing heading, got %v", section)
+	}
+}
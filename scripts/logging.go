@@ -0,0 +1,226 @@
+package scripts
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// LOG_FILE_NAME is the log of the program in the vault's state directory
+// It is rotated to .1, .2, ... once it grows past LOG_MAX_SIZE_BYTES, keeping LOG_ROTATED_FILES of them
+const (
+	LOG_FILE_NAME      = "cli-notes.log"
+	LOG_MAX_SIZE_BYTES = 1 << 20
+	LOG_ROTATED_FILES  = 3
+)
+
+// SetupLogging sends the slog logs at level and above to the rotating log file in stateDir,
+// which is only created once something is logged. Warnings and errors are also counted for
+// the status indicator, so background failures don't need to print over the views
+func SetupLogging(stateDir string, level slog.Level) *RotatingFile {
+	file := NewRotatingFile(filepath.Join(stateDir, LOG_FILE_NAME), LOG_MAX_SIZE_BYTES, LOG_ROTATED_FILES)
+	handler := slog.NewTextHandler(file, &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(statusHandler{Handler: handler}))
+	return file
+}
+
+// ParseLogLevel reads a level name like debug, info, warn or error
+func ParseLogLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", name)
+	}
+	return level, nil
+}
+
+// logProblems counts the warnings and errors logged since they were last looked at
+var logProblems struct {
+	mu    sync.Mutex
+	count int
+	last  string
+}
+
+// statusHandler counts warnings and errors before handing every record to the log file
+type statusHandler struct {
+	slog.Handler
+}
+
+func (h statusHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelWarn {
+		logProblems.mu.Lock()
+		logProblems.count++
+		logProblems.last = record.Message
+		logProblems.mu.Unlock()
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h statusHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return statusHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h statusHandler) WithGroup(name string) slog.Handler {
+	return statusHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// UnseenLogProblems returns how many warnings and errors were logged since MarkLogProblemsSeen,
+// and the message of the latest one
+func UnseenLogProblems() (int, string) {
+	logProblems.mu.Lock()
+	defer logProblems.mu.Unlock()
+	return logProblems.count, logProblems.last
+}
+
+// MarkLogProblemsSeen clears the status indicator, once the log has been looked at
+func MarkLogProblemsSeen() {
+	logProblems.mu.Lock()
+	defer logProblems.mu.Unlock()
+	logProblems.count = 0
+	logProblems.last = ""
+}
+
+// ReadRecentLogEntries returns the last limit lines of the log in stateDir, oldest first,
+// reading the rotated files too when the current one is shorter
+func ReadRecentLogEntries(stateDir string, limit int) ([]string, error) {
+	path := filepath.Join(stateDir, LOG_FILE_NAME)
+	entries := make([]string, 0, limit)
+	for rotation := 0; rotation <= LOG_ROTATED_FILES && len(entries) < limit; rotation++ {
+		lines, err := readLogLines(rotatedLogPath(path, rotation))
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if missing := limit - len(entries); len(lines) > missing {
+			lines = lines[len(lines)-missing:]
+		}
+		entries = append(lines, entries...)
+	}
+	return entries, nil
+}
+
+func readLogLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// RotatingFile appends to a log file, created with its directory on the first write,
+// and renames it to .1, .2, ... once it grows past maxSize
+type RotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	keep    int
+	file    *os.File
+	size    int64
+}
+
+func NewRotatingFile(path string, maxSize int64, keep int) *RotatingFile {
+	return &RotatingFile{path: path, maxSize: maxSize, keep: keep}
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file != nil && r.size+int64(len(p)) > r.maxSize && r.size > 0 {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file; a later write opens it again
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+	if r.size >= r.maxSize {
+		return r.rotate()
+	}
+	return nil
+}
+
+// rotate shifts the rotated files up by one, dropping the oldest, and starts a new log file
+func (r *RotatingFile) rotate() error {
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+
+	_ = os.Remove(rotatedLogPath(r.path, r.keep))
+	for rotation := r.keep - 1; rotation >= 0; rotation-- {
+		if err := os.Rename(rotatedLogPath(r.path, rotation), rotatedLogPath(r.path, rotation+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	r.file = file
+	r.size = 0
+	return nil
+}
+
+// rotatedLogPath is the path of the log rotated the given number of times, the log itself for 0
+func rotatedLogPath(path string, rotation int) string {
+	if rotation == 0 {
+		return path
+	}
+	return fmt.Sprintf("%s.%d", path, rotation)
+}
@@ -0,0 +1,98 @@
+package scripts
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile_RotatesAndKeepsRecentEntries(t *testing.T) {
+	stateDir := filepath.Join(t.TempDir(), ".cli-notes")
+	path := filepath.Join(stateDir, LOG_FILE_NAME)
+	file := NewRotatingFile(path, 20, 2)
+	defer file.Close()
+
+	if _, err := os.Stat(stateDir); !os.IsNotExist(err) {
+		t.Fatal("Expected the state directory to be created on the first write only")
+	}
+
+	// Each line is 8 bytes, so every file holds 2 lines before rotating
+	for i := 1; i <= 7; i++ {
+		if _, err := fmt.Fprintf(file, "entry %d\n", i); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected only 2 rotated files to be kept")
+	}
+	entries, err := ReadRecentLogEntries(stateDir, 10)
+	if err != nil {
+		t.Fatalf("ReadRecentLogEntries failed: %v", err)
+	}
+	// ReadRecentLogEntries reads up to LOG_ROTATED_FILES rotations, which covers the 2 kept here
+	expected := []string{"entry 3", "entry 4", "entry 5", "entry 6", "entry 7"}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("ReadRecentLogEntries() = %v, want %v", entries, expected)
+	}
+
+	entries, _ = ReadRecentLogEntries(stateDir, 3)
+	if !reflect.DeepEqual(entries, []string{"entry 5", "entry 6", "entry 7"}) {
+		t.Errorf("Expected the last 3 entries, got %v", entries)
+	}
+}
+
+func TestReadRecentLogEntries_NoLogYet(t *testing.T) {
+	entries, err := ReadRecentLogEntries(t.TempDir(), 5)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries, got %v (%v)", entries, err)
+	}
+}
+
+func TestSetupLogging_WritesLevelsAndCountsProblems(t *testing.T) {
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+	MarkLogProblemsSeen()
+
+	stateDir := t.TempDir()
+	file := SetupLogging(stateDir, slog.LevelInfo)
+	defer file.Close()
+
+	slog.Debug("not written")
+	slog.Info("backup snapshot taken", "path", "notes-1.tar.gz")
+	slog.Warn("git sync left notes with conflicts", "notes", "plan.md")
+	slog.Error("git commit failed", "err", "index.lock exists")
+
+	problems, last := UnseenLogProblems()
+	if problems != 2 || last != "git commit failed" {
+		t.Errorf("UnseenLogProblems() = %d, %q", problems, last)
+	}
+	MarkLogProblemsSeen()
+	if problems, _ := UnseenLogProblems(); problems != 0 {
+		t.Errorf("Expected no problems after marking them seen, got %d", problems)
+	}
+
+	entries, err := ReadRecentLogEntries(stateDir, 10)
+	if err != nil {
+		t.Fatalf("ReadRecentLogEntries failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries at info and above, got %v", entries)
+	}
+	if !strings.Contains(entries[2], "level=ERROR") || !strings.Contains(entries[2], `msg="git commit failed"`) || !strings.Contains(entries[2], `err="index.lock exists"`) {
+		t.Errorf("Expected a structured error entry, got %q", entries[2])
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	if level, err := ParseLogLevel("warn"); err != nil || level != slog.LevelWarn {
+		t.Errorf("ParseLogLevel(warn) = %v, %v", level, err)
+	}
+	if level, err := ParseLogLevel("loud"); err == nil || level != slog.LevelInfo {
+		t.Errorf("Expected an error and info for an unknown level, got %v, %v", level, err)
+	}
+}
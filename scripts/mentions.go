@@ -0,0 +1,205 @@
+package scripts
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minMentionLength skips terms so short they would match ordinary words everywhere
+const minMentionLength = 3
+
+// Mention is a plain-text occurrence of a note's title or alias in another note
+type Mention struct {
+	File   File
+	Line   int    // 1-based line in the note content
+	Column int    // Byte offset of the mention in the line
+	Text   string // The mentioned text as written
+}
+
+// Context returns the trimmed line holding the mention
+func (m Mention) Context() string {
+	lines := strings.Split(m.File.Content, "\n")
+	if m.Line < 1 || m.Line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[m.Line-1])
+}
+
+// FindMentions returns the whole-word, case-insensitive occurrences of the terms in a note
+// Text inside [[links]] and `code` is skipped, and longer terms win over the terms they contain
+func FindMentions(file File, terms []string) []Mention {
+	terms = mentionTerms(terms)
+	mentions := make([]Mention, 0)
+	if len(terms) == 0 {
+		return mentions
+	}
+
+	for i, line := range strings.Split(file.Content, "\n") {
+		skipped := skippedRanges(line)
+
+		for column := 0; column < len(line); {
+			matched := ""
+			for _, term := range terms {
+				length, ok := foldPrefixLength(line[column:], term)
+				if ok && isWordBoundary(line, column, column+length) && !inRanges(skipped, column) {
+					matched = line[column : column+length]
+					break
+				}
+			}
+
+			if matched == "" {
+				_, size := utf8.DecodeRuneInString(line[column:])
+				column += size
+				continue
+			}
+
+			mentions = append(mentions, Mention{File: file, Line: i + 1, Column: column, Text: matched})
+			column += len(matched)
+		}
+	}
+
+	return mentions
+}
+
+// LinkMentions turns mentions in a note into links to the target, in place
+// The mentioned text is kept as the link's display text. Mentions from other notes are ignored
+func LinkMentions(file File, mentions []Mention, target File) File {
+	lines := strings.Split(file.Content, "\n")
+
+	// Later mentions first, so earlier offsets stay valid
+	sorted := make([]Mention, 0, len(mentions))
+	for _, mention := range mentions {
+		if mention.File.Name == file.Name {
+			sorted = append(sorted, mention)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Line != sorted[j].Line {
+			return sorted[i].Line > sorted[j].Line
+		}
+		return sorted[i].Column > sorted[j].Column
+	})
+
+	for _, mention := range sorted {
+		if mention.Line < 1 || mention.Line > len(lines) {
+			continue
+		}
+		line := lines[mention.Line-1]
+		end := mention.Column + len(mention.Text)
+		if end > len(line) || line[mention.Column:end] != mention.Text {
+			continue // The note changed since the mention was found
+		}
+		lines[mention.Line-1] = line[:mention.Column] + "[[" + mentionLinkText(mention.Text, target) + "]]" + line[end:]
+	}
+
+	file.Content = strings.Join(lines, "\n")
+	return file
+}
+
+// mentionLinkText returns the link text for a mention, ID-backed when the target has an ID
+func mentionLinkText(text string, target File) string {
+	if target.ID != "" {
+		return IDLinkPrefix + target.ID + "|" + text
+	}
+	if text == target.Title {
+		return text
+	}
+	return FormatLink(target.Title, LinkAnchor{}, text)
+}
+
+// foldPrefixLength returns the byte length of the text at the start of s that equals term
+// case-insensitively, comparing rune by rune since case variants can differ in byte length
+func foldPrefixLength(s, term string) (int, bool) {
+	length := 0
+	for _, termRune := range term {
+		if length >= len(s) {
+			return 0, false
+		}
+		r, size := utf8.DecodeRuneInString(s[length:])
+		if r != termRune && !strings.EqualFold(string(r), string(termRune)) {
+			return 0, false
+		}
+		length += size
+	}
+	return length, true
+}
+
+// mentionTerms trims and deduplicates the terms case-insensitively, longest first
+func mentionTerms(terms []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		key := strings.ToLower(term)
+		if utf8.RuneCountInString(term) < minMentionLength || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, term)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return utf8.RuneCountInString(result[i]) > utf8.RuneCountInString(result[j])
+	})
+	return result
+}
+
+// skippedRanges returns the byte ranges of a line inside [[links]] and `code`
+func skippedRanges(line string) [][2]int {
+	ranges := make([][2]int, 0)
+	for _, match := range wikiLinkPattern.FindAllStringIndex(line, -1) {
+		// Include a leading ! so embeds are skipped too
+		start := match[0]
+		if start > 0 && line[start-1] == '!' {
+			start--
+		}
+		ranges = append(ranges, [2]int{start, match[1]})
+	}
+
+	start := -1
+	for i := 0; i < len(line); i++ {
+		if line[i] != '`' {
+			continue
+		}
+		if start < 0 {
+			start = i
+		} else {
+			ranges = append(ranges, [2]int{start, i + 1})
+			start = -1
+		}
+	}
+	return ranges
+}
+
+// inRanges checks whether an offset falls inside any of the ranges
+func inRanges(ranges [][2]int, offset int) bool {
+	for _, r := range ranges {
+		if offset >= r[0] && offset < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordBoundary checks that the text between start and end is not part of a longer word
+func isWordBoundary(line string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(line[:start])
+		if isWordRune(before) {
+			return false
+		}
+	}
+	if end < len(line) {
+		after, _ := utf8.DecodeRuneInString(line[end:])
+		if isWordRune(after) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordRune reports whether a rune can be part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
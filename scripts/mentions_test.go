@@ -0,0 +1,88 @@
+package scripts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindMentions_MatchesWholeWordsCaseInsensitively(t *testing.T) {
+	file := File{Name: "meeting.md", Content: "# Meeting\nTalked about Payments today\npaymentsteam is not a mention\nPAYMENTS again"}
+
+	mentions := FindMentions(file, []string{"payments"})
+
+	if len(mentions) != 2 {
+		t.Fatalf("Expected 2 mentions, got %d: %+v", len(mentions), mentions)
+	}
+	if mentions[0].Line != 2 || mentions[0].Text != "Payments" || mentions[0].Context() != "Talked about Payments today" {
+		t.Errorf("Unexpected first mention %+v", mentions[0])
+	}
+	if mentions[1].Line != 4 || mentions[1].Column != 0 {
+		t.Errorf("Unexpected second mention %+v", mentions[1])
+	}
+}
+
+func TestFindMentions_SkipsLinksCodeAndShortTerms(t *testing.T) {
+	file := File{Name: "meeting.md", Content: "See [[payments]] and ![[payments]] and `payments` and [[id:1a2b3c4d|payments]]\nOK ok"}
+
+	if mentions := FindMentions(file, []string{"payments", "ok"}); len(mentions) != 0 {
+		t.Errorf("Expected no mentions, got %+v", mentions)
+	}
+}
+
+func TestFindMentions_PrefersLongerTerms(t *testing.T) {
+	file := File{Name: "meeting.md", Content: "The payments plan is ready"}
+
+	mentions := FindMentions(file, []string{"payments", "payments plan"})
+
+	if len(mentions) != 1 || mentions[0].Text != "payments plan" {
+		t.Errorf("Expected one mention of the longer term, got %+v", mentions)
+	}
+}
+
+func TestFindMentions_MatchesCaseVariantsOfDifferentByteLength(t *testing.T) {
+	// The Kelvin sign folds to k and İ is longer than its lowercase form in bytes
+	file := File{Name: "trip.md", Content: "\u212Aelvin scale\nTrip to İstanbul in May\nİSTANBUL again"}
+
+	mentions := FindMentions(file, []string{"kelvin", "İstanbul"})
+
+	if len(mentions) != 3 {
+		t.Fatalf("Expected 3 mentions, got %d: %+v", len(mentions), mentions)
+	}
+	if mentions[0].Text != "\u212Aelvin" || mentions[0].Column != 0 {
+		t.Errorf("Unexpected first mention %+v", mentions[0])
+	}
+	if mentions[1].Text != "İstanbul" || mentions[1].Column != len("Trip to ") {
+		t.Errorf("Unexpected second mention %+v", mentions[1])
+	}
+	if mentions[2].Text != "İSTANBUL" {
+		t.Errorf("Unexpected third mention %+v", mentions[2])
+	}
+
+	linked := LinkMentions(file, mentions, File{Title: "İstanbul", ID: "1a2b3c4d"})
+	if !strings.Contains(linked.Content, "Trip to [[id:1a2b3c4d|İstanbul]] in May") {
+		t.Errorf("Expected the mention to be linked in place, got '%s'", linked.Content)
+	}
+}
+
+func TestLinkMentions_LinksInPlace(t *testing.T) {
+	file := File{Name: "meeting.md", Content: "Payments and payments\nMore payments"}
+	mentions := FindMentions(file, []string{"payments"})
+
+	linked := LinkMentions(file, mentions, File{Name: "payments.md", Title: "payments", ID: "1a2b3c4d"})
+
+	expected := "[[id:1a2b3c4d|Payments]] and [[id:1a2b3c4d|payments]]\nMore [[id:1a2b3c4d|payments]]"
+	if linked.Content != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, linked.Content)
+	}
+}
+
+func TestLinkMentions_UsesTitleLinksWithoutID(t *testing.T) {
+	file := File{Name: "meeting.md", Content: "payments and Payments"}
+	mentions := FindMentions(file, []string{"payments"})
+
+	linked := LinkMentions(file, mentions[1:], File{Name: "payments.md", Title: "payments"})
+
+	if linked.Content != "payments and [[payments|Payments]]" {
+		t.Errorf("Expected only the chosen mention to be linked, got '%s'", linked.Content)
+	}
+}
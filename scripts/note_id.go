@@ -0,0 +1,25 @@
+package scripts
+
+import "fmt"
+
+// noteIDAttempts bounds the retries for an ID no note has yet
+const noteIDAttempts = 10
+
+// GenerateNoteID creates a stable note ID that isn't taken yet, using the same format as objective IDs
+func GenerateNoteID(taken func(id string) bool) (string, error) {
+	for attempt := 0; attempt < noteIDAttempts; attempt++ {
+		id, err := GenerateObjectiveID()
+		if err != nil {
+			return "", err
+		}
+		if !taken(id) {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("no free note ID after %d attempts", noteIDAttempts)
+}
+
+// ValidateNoteID checks if a note ID is valid format
+func ValidateNoteID(id string) bool {
+	return ValidateObjectiveID(id)
+}
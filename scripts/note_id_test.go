@@ -0,0 +1,27 @@
+package scripts
+
+import "testing"
+
+func TestGenerateNoteID_SkipsTakenIDs(t *testing.T) {
+	generated := make([]string, 0)
+	id, err := GenerateNoteID(func(id string) bool {
+		generated = append(generated, id)
+		return len(generated) < 3
+	})
+	if err != nil {
+		t.Fatalf("GenerateNoteID failed: %v", err)
+	}
+	if len(generated) != 3 || id != generated[2] {
+		t.Errorf("Expected the third ID after two taken ones, got %s after %v", id, generated)
+	}
+	if !ValidateNoteID(id) {
+		t.Errorf("Expected a valid note ID, got %s", id)
+	}
+}
+
+func TestGenerateNoteID_FailsWhenEveryIDIsTaken(t *testing.T) {
+	_, err := GenerateNoteID(func(id string) bool { return true })
+	if err == nil {
+		t.Error("Expected an error when every ID is taken")
+	}
+}
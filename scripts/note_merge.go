@@ -0,0 +1,267 @@
+package scripts
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Conflict markers left in the body of a note when both sides changed the same lines
+const (
+	ConflictMarkerLocal  = "<<<<<<< local"
+	ConflictMarkerMiddle = "======="
+	ConflictMarkerRemote = ">>>>>>> remote"
+)
+
+// frontmatterField is a key: value line of a note's frontmatter
+// Lines without a key, such as list items or comments, are kept verbatim in the value of the field above them
+type frontmatterField struct {
+	Key   string
+	Value string
+}
+
+// line writes the field back as it appears in the frontmatter
+func (f frontmatterField) line() string {
+	switch {
+	case f.Key == "":
+		return f.Value + "\n" // Lines before the first field
+	case f.Value == "" || strings.HasPrefix(f.Value, "\n"):
+		return f.Key + ":" + f.Value + "\n"
+	}
+	return f.Key + ": " + f.Value + "\n"
+}
+
+// MergeNote merges the local and remote versions of a note changed on both sides since base
+// Frontmatter is merged field by field: a field changed on one side takes that change, and a field
+// changed on both sides is settled by resolveFieldConflict. The body is merged line by line, and lines
+// changed on both sides are kept between conflict markers. Returns false when conflict markers were left
+func MergeNote(base, local, remote string) (string, bool) {
+	baseFields, baseBody, baseHas := splitNoteFrontmatter(base)
+	localFields, localBody, localHas := splitNoteFrontmatter(local)
+	remoteFields, remoteBody, remoteHas := splitNoteFrontmatter(remote)
+
+	body, clean := mergeText(baseBody, localBody, remoteBody)
+	if !localHas && !remoteHas {
+		return body, clean
+	}
+	if !baseHas {
+		baseFields = nil
+	}
+
+	var merged strings.Builder
+	merged.WriteString("---\n")
+	for _, field := range mergeFrontmatter(baseFields, localFields, remoteFields) {
+		merged.WriteString(field.line())
+	}
+	merged.WriteString("---\n")
+	merged.WriteString(body)
+	return merged.String(), clean
+}
+
+// HasConflictMarkers reports whether a note still has conflict markers left by a sync
+func HasConflictMarkers(content string) bool {
+	hasLocal, hasMiddle := false, false
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case strings.HasPrefix(line, ConflictMarkerLocal):
+			hasLocal = true
+		case hasLocal && line == ConflictMarkerMiddle:
+			hasMiddle = true
+		case hasMiddle && strings.HasPrefix(line, ConflictMarkerRemote):
+			return true
+		}
+	}
+	return false
+}
+
+// mergeFrontmatter merges the fields of both sides, in local order followed by fields only the remote has
+// A field removed on one side and unchanged on the other is removed
+func mergeFrontmatter(base, local, remote []frontmatterField) []frontmatterField {
+	baseValues := frontmatterValues(base)
+	localValues := frontmatterValues(local)
+	remoteValues := frontmatterValues(remote)
+
+	keys := make([]string, 0, len(local)+len(remote))
+	seen := make(map[string]bool)
+	for _, field := range append(append([]frontmatterField{}, local...), remote...) {
+		if !seen[field.Key] {
+			seen[field.Key] = true
+			keys = append(keys, field.Key)
+		}
+	}
+
+	merged := make([]frontmatterField, 0, len(keys))
+	for _, key := range keys {
+		baseValue, inBase := baseValues[key]
+		localValue, inLocal := localValues[key]
+		remoteValue, inRemote := remoteValues[key]
+
+		value, keep := localValue, inLocal
+		switch {
+		case inLocal == inRemote && localValue == remoteValue:
+		case inLocal == inBase && localValue == baseValue:
+			value, keep = remoteValue, inRemote
+		case inRemote == inBase && remoteValue == baseValue:
+		case !inLocal:
+			value, keep = remoteValue, true // Changed on one side beats removed on the other
+		case inRemote:
+			value = resolveFieldConflict(key, baseValue, localValue, remoteValue)
+		}
+		if keep {
+			merged = append(merged, frontmatterField{Key: key, Value: value})
+		}
+	}
+	return merged
+}
+
+// resolveFieldConflict settles a field changed differently on both sides
+// The latest date-due wins, done is sticky, tags added or removed on either side are applied,
+// and for any other field the local value wins
+func resolveFieldConflict(key, base, local, remote string) string {
+	if strings.Contains(local+remote, "\n") {
+		return local // Values spanning lines are kept whole
+	}
+
+	switch key {
+	case "done":
+		if local == "true" || remote == "true" {
+			return "true"
+		}
+	case "date-due":
+		localDue, localErr := time.Parse("2006-01-02", local)
+		remoteDue, remoteErr := time.Parse("2006-01-02", remote)
+		if localErr != nil || (remoteErr == nil && remoteDue.After(localDue)) {
+			return remote
+		}
+	case "tags":
+		return mergeTagsValue(base, local, remote)
+	}
+	return local
+}
+
+// mergeTagsValue applies the tags added and removed on each side to the base tags
+func mergeTagsValue(base, local, remote string) string {
+	baseTags := splitTagsValue(base)
+	localTags := splitTagsValue(local)
+	remoteTags := splitTagsValue(remote)
+
+	removed := make(map[string]bool)
+	for _, tag := range baseTags {
+		if !containsString(localTags, tag) || !containsString(remoteTags, tag) {
+			removed[tag] = true
+		}
+	}
+
+	merged := make([]string, 0, len(localTags)+len(remoteTags))
+	for _, tag := range append(localTags, remoteTags...) {
+		if !removed[tag] && !containsString(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return "[" + strings.Join(merged, " ") + "]"
+}
+
+// splitTagsValue reads a tags value written as [a b] or [a, b]
+func splitTagsValue(value string) []string {
+	value = strings.Trim(strings.TrimSpace(value), "[]")
+	return strings.Fields(strings.ReplaceAll(value, ",", " "))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// splitNoteFrontmatter splits a note into its frontmatter fields and the body after the closing ---
+func splitNoteFrontmatter(content string) ([]frontmatterField, string, bool) {
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, content, false
+	}
+
+	rest := content[len("---\n"):]
+	fields := make([]frontmatterField, 0)
+	for {
+		end := strings.Index(rest, "\n")
+		if end < 0 {
+			return nil, content, false // Unclosed frontmatter, treat it all as body
+		}
+		line := rest[:end]
+		rest = rest[end+1:]
+		if line == "---" {
+			return fields, rest, true
+		}
+		parts := strings.SplitN(line, ":", 2)
+		switch {
+		case len(parts) == 2 && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t"):
+			fields = append(fields, frontmatterField{Key: strings.TrimSpace(parts[0]), Value: strings.TrimSpace(parts[1])})
+		case len(fields) == 0:
+			fields = append(fields, frontmatterField{Value: line})
+		default:
+			fields[len(fields)-1].Value += "\n" + line
+		}
+	}
+}
+
+func frontmatterValues(fields []frontmatterField) map[string]string {
+	values := make(map[string]string, len(fields))
+	for _, field := range fields {
+		values[field.Key] = field.Value
+	}
+	return values
+}
+
+// mergeText merges two texts changed from base line by line with git merge-file
+// Returns false when lines changed on both sides were left between conflict markers
+func mergeText(base, local, remote string) (string, bool) {
+	switch {
+	case local == remote, remote == base:
+		return local, true
+	case local == base:
+		return remote, true
+	}
+
+	dir, err := os.MkdirTemp("", "cli-notes-merge")
+	if err != nil {
+		return conflictText(local, remote), false
+	}
+	defer os.RemoveAll(dir)
+
+	paths := make([]string, 0, 3)
+	for _, side := range []struct{ name, content string }{{"local", local}, {"base", base}, {"remote", remote}} {
+		path := filepath.Join(dir, side.name)
+		if err := os.WriteFile(path, []byte(side.content), 0644); err != nil {
+			return conflictText(local, remote), false
+		}
+		paths = append(paths, path)
+	}
+
+	cmd := exec.Command("git", "merge-file", "-p", "-L", "local", "-L", "base", "-L", "remote", paths[0], paths[1], paths[2])
+	output, err := cmd.Output()
+	if err == nil {
+		return string(output), true
+	}
+	// merge-file exits with the number of conflicts, or a negative code on error
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return string(output), false
+	}
+	return conflictText(local, remote), false
+}
+
+// conflictText keeps both texts whole between conflict markers, when they can't be merged line by line
+func conflictText(local, remote string) string {
+	return fmt.Sprintf("%s\n%s%s\n%s%s\n", ConflictMarkerLocal, ensureNewline(local), ConflictMarkerMiddle, ensureNewline(remote), ConflictMarkerRemote)
+}
+
+func ensureNewline(text string) string {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		return text + "\n"
+	}
+	return text
+}
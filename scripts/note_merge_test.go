@@ -0,0 +1,150 @@
+package scripts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeNote_Frontmatter(t *testing.T) {
+	note := func(fields ...string) string {
+		return "---\n" + strings.Join(fields, "\n") + "\n---\n\nBody\n"
+	}
+
+	tests := []struct {
+		name                string
+		base, local, remote string
+		expected            string
+	}{
+		{
+			name:     "fields changed on different sides",
+			base:     note("title: Task", "priority: 2", "done: false"),
+			local:    note("title: Task", "priority: 1", "done: false"),
+			remote:   note("title: Task", "priority: 2", "done: true"),
+			expected: note("title: Task", "priority: 1", "done: true"),
+		},
+		{
+			name:     "latest date-due wins",
+			base:     note("title: Task", "date-due: 2025-01-10"),
+			local:    note("title: Task", "date-due: 2025-01-20"),
+			remote:   note("title: Task", "date-due: 2025-01-15"),
+			expected: note("title: Task", "date-due: 2025-01-20"),
+		},
+		{
+			name:     "done is sticky",
+			base:     note("title: Task", "done: maybe"),
+			local:    note("title: Task", "done: false"),
+			remote:   note("title: Task", "done: true"),
+			expected: note("title: Task", "done: true"),
+		},
+		{
+			name:     "tags added and removed on both sides",
+			base:     note("title: Task", "tags: [todo work]"),
+			local:    note("title: Task", "tags: [todo urgent]"),
+			remote:   note("title: Task", "tags: [todo work home]"),
+			expected: note("title: Task", "tags: [todo urgent home]"),
+		},
+		{
+			name:     "other fields keep the local value",
+			base:     note("title: Task"),
+			local:    note("title: Local task"),
+			remote:   note("title: Remote task"),
+			expected: note("title: Local task"),
+		},
+		{
+			name:     "field only added remotely",
+			base:     note("title: Task"),
+			local:    note("title: Task"),
+			remote:   note("title: Task", "id: abcd1234"),
+			expected: note("title: Task", "id: abcd1234"),
+		},
+		{
+			name:     "field removed on one side",
+			base:     note("title: Task", "objective-id: abcd1234"),
+			local:    note("title: Task"),
+			remote:   note("title: Task", "objective-id: abcd1234"),
+			expected: note("title: Task"),
+		},
+		{
+			name:     "lines without a key kept verbatim",
+			base:     note("# synced", "title: Task", "aliases:", "  - first", "priority: 2"),
+			local:    note("# synced", "title: Task", "aliases:", "  - first", "priority: 1"),
+			remote:   note("# synced", "title: Task", "aliases:", "  - first", "  - second", "priority: 2"),
+			expected: note("# synced", "title: Task", "aliases:", "  - first", "  - second", "priority: 1"),
+		},
+		{
+			name:     "empty values have no trailing space",
+			base:     note("title: Task", "date-due:", "priority: 2"),
+			local:    note("title: Task", "date-due:", "priority: 1"),
+			remote:   note("title: Task", "date-due:", "priority: 2", "id: abcd1234"),
+			expected: note("title: Task", "date-due:", "priority: 1", "id: abcd1234"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, clean := MergeNote(tt.base, tt.local, tt.remote)
+			if !clean {
+				t.Errorf("MergeNote() left conflicts:\n%s", merged)
+			}
+			if merged != tt.expected {
+				t.Errorf("MergeNote() =\n%s\nwant\n%s", merged, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMergeNote_Body(t *testing.T) {
+	base := "---\ntitle: Note\n---\n\nfirst\nsecond\nthird\nfourth\nfifth\n"
+
+	t.Run("changes to different lines merge", func(t *testing.T) {
+		local := strings.Replace(base, "first", "first local", 1)
+		remote := strings.Replace(base, "fifth", "fifth remote", 1)
+
+		merged, clean := MergeNote(base, local, remote)
+		if !clean {
+			t.Fatalf("MergeNote() left conflicts:\n%s", merged)
+		}
+		expected := "---\ntitle: Note\n---\n\nfirst local\nsecond\nthird\nfourth\nfifth remote\n"
+		if merged != expected {
+			t.Errorf("MergeNote() =\n%s\nwant\n%s", merged, expected)
+		}
+	})
+
+	t.Run("changes to the same line keep conflict markers", func(t *testing.T) {
+		local := strings.Replace(base, "third", "third local", 1)
+		remote := strings.Replace(strings.Replace(base, "third", "third remote", 1), "title: Note", "title: Renamed", 1)
+
+		merged, clean := MergeNote(base, local, remote)
+		if clean {
+			t.Fatalf("MergeNote() reported a clean merge:\n%s", merged)
+		}
+		if !strings.HasPrefix(merged, "---\ntitle: Renamed\n---\n") {
+			t.Errorf("Frontmatter should still be merged field by field, got:\n%s", merged)
+		}
+		for _, expected := range []string{ConflictMarkerLocal + "\nthird local\n", ConflictMarkerMiddle + "\nthird remote\n" + ConflictMarkerRemote} {
+			if !strings.Contains(merged, expected) {
+				t.Errorf("Expected %q in merged note:\n%s", expected, merged)
+			}
+		}
+		if !HasConflictMarkers(merged) {
+			t.Error("HasConflictMarkers() = false for a note with conflict markers")
+		}
+	})
+}
+
+func TestHasConflictMarkers(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected bool
+	}{
+		{"<<<<<<< local\na\n=======\nb\n>>>>>>> remote\n", true},
+		{"plain note\n=======\n", false},
+		{"<<<<<<< local\na\n>>>>>>> remote\n", false},
+	}
+
+	for _, tt := range tests {
+		if got := HasConflictMarkers(tt.content); got != tt.expected {
+			t.Errorf("HasConflictMarkers(%q) = %v, want %v", tt.content, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package scripts
+
+import (
+	"sync"
+	"time"
+)
+
+// NOTES_WATCH_DEBOUNCE is how long the watcher waits for more changes before publishing them,
+// so an editor saving through a temporary file or a burst of writes is published once
+const NOTES_WATCH_DEBOUNCE = 150 * time.Millisecond
+
+// NoteOp is what happened to a note on disk
+type NoteOp int
+
+const (
+	NoteCreated NoteOp = iota
+	NoteModified
+	NoteDeleted
+	NotesRescan // Events were lost, so any note may have changed; the event has no name
+)
+
+// NoteEvent is a note created, modified or deleted in the watched directory
+type NoteEvent struct {
+	Name string // File name of the note, like plan.md
+	Op   NoteOp
+}
+
+// NoteWatcher publishes the notes created, modified or deleted in a directory to its subscribers,
+// whether by this program, an external editor or another process
+type NoteWatcher struct {
+	mu          sync.Mutex
+	subscribers map[*NoteSubscription]bool
+	events      chan NoteEvent // Raw events from the platform, closed when the watcher stops
+	closeSource func() error
+}
+
+// NoteSubscription collects the note events published since they were last taken
+// A nil subscription never receives any, so views work the same without a watcher
+type NoteSubscription struct {
+	watcher *NoteWatcher
+	changed chan struct{}
+	mu      sync.Mutex
+	pending []NoteEvent
+}
+
+func newNoteWatcher(closeSource func() error) *NoteWatcher {
+	watcher := &NoteWatcher{
+		subscribers: make(map[*NoteSubscription]bool),
+		events:      make(chan NoteEvent, 64),
+		closeSource: closeSource,
+	}
+	go watcher.run()
+	return watcher
+}
+
+// Subscribe starts collecting the note events published from now on
+func (w *NoteWatcher) Subscribe() *NoteSubscription {
+	subscription := &NoteSubscription{watcher: w, changed: make(chan struct{}, 1)}
+	w.mu.Lock()
+	w.subscribers[subscription] = true
+	w.mu.Unlock()
+	return subscription
+}
+
+// Close stops watching; the subscriptions receive no more events
+func (w *NoteWatcher) Close() error {
+	return w.closeSource()
+}
+
+// run merges the raw events of each burst and publishes them once the burst is over
+func (w *NoteWatcher) run() {
+	pending := make([]NoteEvent, 0)
+	debounce := time.NewTimer(NOTES_WATCH_DEBOUNCE)
+	debounce.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.events:
+			if !ok {
+				debounce.Stop()
+				w.publish(pending)
+				return
+			}
+			pending = mergeNoteEvents(pending, []NoteEvent{event})
+			debounce.Reset(NOTES_WATCH_DEBOUNCE)
+		case <-debounce.C:
+			w.publish(pending)
+			pending = make([]NoteEvent, 0)
+		}
+	}
+}
+
+func (w *NoteWatcher) publish(events []NoteEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for subscription := range w.subscribers {
+		subscription.mu.Lock()
+		subscription.pending = mergeNoteEvents(subscription.pending, events)
+		subscription.mu.Unlock()
+
+		select {
+		case subscription.changed <- struct{}{}:
+		default: // Already signalled, the events wait in pending
+		}
+	}
+}
+
+// Changed receives a value whenever events are waiting to be taken
+func (s *NoteSubscription) Changed() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+	return s.changed
+}
+
+// Take returns the events waiting, merged per note in the order the notes first changed
+func (s *NoteSubscription) Take() []NoteEvent {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := s.pending
+	s.pending = nil
+	return events
+}
+
+// Close stops collecting events
+func (s *NoteSubscription) Close() {
+	if s == nil {
+		return
+	}
+	s.watcher.mu.Lock()
+	delete(s.watcher.subscribers, s)
+	s.watcher.mu.Unlock()
+}
+
+// NoteEventNames returns the names of the notes the events are about
+func NoteEventNames(events []NoteEvent) []string {
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		if event.Op != NotesRescan {
+			names = append(names, event.Name)
+		}
+	}
+	return names
+}
+
+// NotesRescanned reports whether events were lost among these, so every note must be read again
+func NotesRescanned(events []NoteEvent) bool {
+	for _, event := range events {
+		if event.Op == NotesRescan {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeNoteEvents adds events to those already known, keeping one event per note:
+// a note created then modified was created, deleted then created was modified,
+// and created then deleted never existed as far as the subscribers are concerned
+func mergeNoteEvents(known, events []NoteEvent) []NoteEvent {
+	for _, event := range events {
+		index := -1
+		for i := range known {
+			if known[i].Name == event.Name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			known = append(known, event)
+			continue
+		}
+
+		previous := known[index].Op
+		switch {
+		case previous == NoteCreated && event.Op == NoteDeleted:
+			known = append(known[:index], known[index+1:]...)
+		case previous == NoteCreated && event.Op == NoteModified:
+		case previous == NoteDeleted && event.Op != NoteDeleted:
+			known[index].Op = NoteModified
+		default:
+			known[index].Op = event.Op
+		}
+	}
+	return known
+}
@@ -0,0 +1,80 @@
+package scripts
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// notesWatchMask covers every way a note appears, changes or goes away, including the
+// renames editors save through
+const notesWatchMask = unix.IN_CREATE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE | unix.IN_DELETE |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+// WatchNotes watches the notes directly in dirPath with inotify
+func WatchNotes(dirPath string) (*NoteWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify init failed: %w", err)
+	}
+	if _, err := unix.InotifyAddWatch(fd, dirPath, notesWatchMask); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to watch %s: %w", dirPath, err)
+	}
+
+	// A non-blocking file goes through the runtime poller, so closing it ends a pending read
+	file := os.NewFile(uintptr(fd), "inotify")
+	watcher := newNoteWatcher(file.Close)
+	go readInotifyEvents(file, watcher.events)
+	return watcher, nil
+}
+
+// readInotifyEvents turns the inotify events of notes into note events until the file is closed
+func readInotifyEvents(file *os.File, events chan<- NoteEvent) {
+	defer close(events)
+
+	buffer := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := file.Read(buffer)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			raw := (*unix.InotifyEvent)(unsafe.Pointer(&buffer[offset]))
+			nameStart := offset + unix.SizeofInotifyEvent
+			nameEnd := nameStart + int(raw.Len)
+			if nameEnd > n {
+				break
+			}
+			name := strings.TrimRight(string(buffer[nameStart:nameEnd]), "\x00")
+			offset = nameEnd
+
+			op, isNoteOp := inotifyNoteOp(raw.Mask)
+			if isNoteOp && (op == NotesRescan || strings.HasSuffix(name, ".md")) {
+				events <- NoteEvent{Name: name, Op: op}
+			}
+		}
+	}
+}
+
+// inotifyNoteOp returns what happened to a note, or NotesRescan when the kernel's queue
+// overflowed and dropped events
+func inotifyNoteOp(mask uint32) (NoteOp, bool) {
+	switch {
+	case mask&unix.IN_Q_OVERFLOW != 0:
+		return NotesRescan, true
+	case mask&unix.IN_ISDIR != 0:
+		return 0, false
+	case mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+		return NoteCreated, true
+	case mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0:
+		return NoteDeleted, true
+	case mask&(unix.IN_MODIFY|unix.IN_CLOSE_WRITE) != 0:
+		return NoteModified, true
+	}
+	return 0, false
+}
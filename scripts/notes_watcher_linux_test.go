@@ -0,0 +1,31 @@
+package scripts
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestInotifyNoteOp(t *testing.T) {
+	tests := []struct {
+		name     string
+		mask     uint32
+		expected NoteOp
+		isNoteOp bool
+	}{
+		{name: "created", mask: unix.IN_CREATE, expected: NoteCreated, isNoteOp: true},
+		{name: "renamed away", mask: unix.IN_MOVED_FROM, expected: NoteDeleted, isNoteOp: true},
+		{name: "written", mask: unix.IN_CLOSE_WRITE, expected: NoteModified, isNoteOp: true},
+		{name: "directory", mask: unix.IN_CREATE | unix.IN_ISDIR},
+		{name: "dropped events", mask: unix.IN_Q_OVERFLOW, expected: NotesRescan, isNoteOp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, isNoteOp := inotifyNoteOp(tt.mask)
+			if isNoteOp != tt.isNoteOp || (isNoteOp && op != tt.expected) {
+				t.Errorf("inotifyNoteOp() = %v, %v, want %v, %v", op, isNoteOp, tt.expected, tt.isNoteOp)
+			}
+		})
+	}
+}
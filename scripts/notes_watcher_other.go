@@ -0,0 +1,10 @@
+//go:build !linux
+
+package scripts
+
+import "fmt"
+
+// WatchNotes is only implemented with inotify, elsewhere views refresh when reopened
+func WatchNotes(dirPath string) (*NoteWatcher, error) {
+	return nil, fmt.Errorf("watching notes for changes is only supported on Linux")
+}
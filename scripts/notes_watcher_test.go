@@ -0,0 +1,113 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeNoteEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []NoteEvent
+		expected []NoteEvent
+	}{
+		{
+			name:     "created then modified is created",
+			events:   []NoteEvent{{"a.md", NoteCreated}, {"a.md", NoteModified}},
+			expected: []NoteEvent{{"a.md", NoteCreated}},
+		},
+		{
+			name:     "deleted then created is modified",
+			events:   []NoteEvent{{"a.md", NoteDeleted}, {"a.md", NoteCreated}, {"a.md", NoteModified}},
+			expected: []NoteEvent{{"a.md", NoteModified}},
+		},
+		{
+			name:     "created then deleted is dropped",
+			events:   []NoteEvent{{"a.md", NoteModified}, {"tmp.md", NoteCreated}, {"tmp.md", NoteDeleted}},
+			expected: []NoteEvent{{"a.md", NoteModified}},
+		},
+		{
+			name:     "modified then deleted is deleted, in first change order",
+			events:   []NoteEvent{{"a.md", NoteModified}, {"b.md", NoteCreated}, {"a.md", NoteDeleted}},
+			expected: []NoteEvent{{"a.md", NoteDeleted}, {"b.md", NoteCreated}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := mergeNoteEvents(nil, tt.events)
+			if !reflect.DeepEqual(merged, tt.expected) {
+				t.Errorf("mergeNoteEvents() = %v, want %v", merged, tt.expected)
+			}
+		})
+	}
+}
+
+// waitForNoteEvents takes the events of the next burst published to the subscription
+func waitForNoteEvents(t *testing.T, subscription *NoteSubscription) []NoteEvent {
+	t.Helper()
+	select {
+	case <-subscription.Changed():
+		return subscription.Take()
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for note events")
+		return nil
+	}
+}
+
+func TestWatchNotes_PublishesNoteChanges(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "plan.md"), []byte("# Plan"), 0644)
+
+	watcher, err := WatchNotes(dir)
+	if err != nil {
+		t.Skipf("Notes can't be watched here: %v", err)
+	}
+	defer watcher.Close()
+	subscription := watcher.Subscribe()
+	defer subscription.Close()
+
+	os.WriteFile(filepath.Join(dir, "ideas.md"), []byte("# Ideas"), 0644)
+	os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("not a note"), 0644)
+	if events := waitForNoteEvents(t, subscription); !reflect.DeepEqual(events, []NoteEvent{{"ideas.md", NoteCreated}}) {
+		t.Errorf("Expected ideas.md created, got %v", events)
+	}
+
+	// Saved the way editors do, through a temporary file renamed over the note
+	tempPath := filepath.Join(dir, ".plan.md.swp.md")
+	os.WriteFile(tempPath, []byte("# Plan v2"), 0644)
+	os.Rename(tempPath, filepath.Join(dir, "plan.md"))
+	if events := waitForNoteEvents(t, subscription); !reflect.DeepEqual(events, []NoteEvent{{"plan.md", NoteCreated}}) {
+		t.Errorf("Expected only plan.md replaced, got %v", events)
+	}
+
+	os.Remove(filepath.Join(dir, "ideas.md"))
+	if events := waitForNoteEvents(t, subscription); !reflect.DeepEqual(events, []NoteEvent{{"ideas.md", NoteDeleted}}) {
+		t.Errorf("Expected ideas.md deleted, got %v", events)
+	}
+}
+
+func TestNoteSubscription_NilNeverChanges(t *testing.T) {
+	var subscription *NoteSubscription
+	if subscription.Changed() != nil || subscription.Take() != nil {
+		t.Error("Expected a nil subscription to receive nothing")
+	}
+	subscription.Close()
+}
+
+func TestNoteEventNames_LeavesOutRescans(t *testing.T) {
+	events := mergeNoteEvents(nil, []NoteEvent{{"a.md", NoteModified}, {Op: NotesRescan}, {"b.md", NoteCreated}})
+
+	if names := NoteEventNames(events); !reflect.DeepEqual(names, []string{"a.md", "b.md"}) {
+		t.Errorf("Expected only the note names, got %v", names)
+	}
+	if !NotesRescanned(events) {
+		t.Error("Expected the rescan to be reported")
+	}
+	if NotesRescanned(events[:1]) {
+		t.Error("Expected no rescan without one")
+	}
+}
@@ -86,22 +86,7 @@ func CreateChildTodo(title string, parentObjective File, onFileCreated OnFileCre
 	content := fmt.Sprintf("# %s", title)
 
 	// Inherit tags from parent (exclude "objective" tag)
-	childTags := []string{"todo"}
-	for _, tag := range parentObjective.Tags {
-		if tag != "objective" {
-			// Check if child already has this tag
-			hasTag := false
-			for _, childTag := range childTags {
-				if childTag == tag {
-					hasTag = true
-					break
-				}
-			}
-			if !hasTag {
-				childTags = append(childTags, tag)
-			}
-		}
-	}
+	childTags := MergeInheritedTags([]string{"todo"}, parentObjective.Tags)
 
 	newFile := File{
 		Name:        name,
@@ -138,17 +123,8 @@ func LinkTodoToObjective(todo File, parentObjective File, writeFile WriteFile) e
 	// Set objective ID
 	updatedTodo.ObjectiveID = parentObjective.ObjectiveID
 
-	// Inherit tags from parent (only tags child doesn't already have)
-	childTagSet := make(map[string]bool)
-	for _, tag := range updatedTodo.Tags {
-		childTagSet[tag] = true
-	}
-
-	for _, parentTag := range parentObjective.Tags {
-		if parentTag != "objective" && !childTagSet[parentTag] {
-			updatedTodo.Tags = append(updatedTodo.Tags, parentTag)
-		}
-	}
+	// Inherit tags from parent (only tags the child's tags don't already cover)
+	updatedTodo.Tags = MergeInheritedTags(updatedTodo.Tags, parentObjective.Tags)
 
 	return writeFile(updatedTodo)
 }
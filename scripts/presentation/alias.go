@@ -0,0 +1,118 @@
+package presentation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxAliasDepth bounds how deeply aliases and macros may nest
+const maxAliasDepth = 10
+
+// macroParamPattern matches $1, $2, ... and $* placeholders in macro steps
+var macroParamPattern = regexp.MustCompile(`\$(\d+|\*)`)
+
+// CommandAliases holds the user-defined aliases and macros
+type CommandAliases struct {
+	Aliases map[string]string   // Shortcut -> command text
+	Macros  map[string][]string // Name -> command texts run in order
+}
+
+// Names returns every alias and macro name, sorted
+func (c CommandAliases) Names() []string {
+	names := make([]string, 0, len(c.Aliases)+len(c.Macros))
+	for name := range c.Aliases {
+		names = append(names, name)
+	}
+	for name := range c.Macros {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveCommand expands aliases and macros into the commands to run
+// Commands that are neither are returned unchanged
+// Returns an error if an alias or macro refers back to itself
+func (c CommandAliases) ResolveCommand(command CompletedCommand) ([]CompletedCommand, error) {
+	return c.resolve(command, nil)
+}
+
+func (c CommandAliases) resolve(command CompletedCommand, expanding []string) ([]CompletedCommand, error) {
+	expansion, isAlias := c.Aliases[command.Name]
+	steps, isMacro := c.Macros[command.Name]
+	if !isAlias && !isMacro {
+		return []CompletedCommand{command}, nil
+	}
+
+	for _, name := range expanding {
+		if name == command.Name {
+			chain := strings.Join(append(expanding, command.Name), " -> ")
+			return nil, fmt.Errorf("alias recursion detected: %s", chain)
+		}
+	}
+	if len(expanding) >= maxAliasDepth {
+		return nil, fmt.Errorf("alias nesting too deep: %s", strings.Join(expanding, " -> "))
+	}
+	expanding = append(expanding, command.Name)
+
+	args := nonEmptyQueries(command.Queries)
+
+	var texts []string
+	if isAlias {
+		text := expansion
+		if len(args) > 0 {
+			text += " " + strings.Join(args, ", ")
+		}
+		texts = []string{text}
+	} else {
+		for _, step := range steps {
+			texts = append(texts, substituteMacroParams(step, args))
+		}
+	}
+
+	resolved := make([]CompletedCommand, 0, len(texts))
+	for _, text := range texts {
+		next := ToCompletedCommand(WIPCommand{
+			Text:         text,
+			SelectedFile: command.SelectedFile,
+		})
+
+		commands, err := c.resolve(next, expanding)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, commands...)
+	}
+
+	return resolved, nil
+}
+
+// substituteMacroParams replaces $N with the Nth argument and $* with all arguments
+// Placeholders without a matching argument are replaced with an empty string
+func substituteMacroParams(step string, args []string) string {
+	return macroParamPattern.ReplaceAllStringFunc(step, func(param string) string {
+		if param == "$*" {
+			return strings.Join(args, ", ")
+		}
+
+		index, err := strconv.Atoi(strings.TrimPrefix(param, "$"))
+		if err != nil || index < 1 || index > len(args) {
+			return ""
+		}
+		return args[index-1]
+	})
+}
+
+// nonEmptyQueries drops the empty query produced by a command without arguments
+func nonEmptyQueries(queries []string) []string {
+	result := make([]string, 0, len(queries))
+	for _, query := range queries {
+		if query != "" {
+			result = append(result, query)
+		}
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+package presentation
+
+import (
+	"cli-notes/scripts"
+	"strings"
+	"testing"
+)
+
+func TestCommandAliases_ResolveCommand(t *testing.T) {
+	aliases := CommandAliases{
+		Aliases: map[string]string{
+			"wk":    "wp",
+			"todo":  "gt",
+			"later": "wk",
+		},
+		Macros: map[string][]string{
+			"prep": {"cm 1-1-$1", "tt $1"},
+			"all":  {"gta $*"},
+			"week": {"later", "gto"},
+		},
+	}
+	selected := scripts.File{Name: "selected.md"}
+
+	t.Run("Plain command is unchanged", func(t *testing.T) {
+		command := CompletedCommand{Name: "gt", Queries: []string{"api"}, SelectedFile: selected}
+		commands, err := aliases.ResolveCommand(command)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(commands) != 1 || commands[0].Name != "gt" || commands[0].Queries[0] != "api" {
+			t.Errorf("Expected command to be unchanged, got %+v", commands)
+		}
+	})
+
+	t.Run("Alias appends arguments", func(t *testing.T) {
+		command := CompletedCommand{Name: "todo", Queries: []string{"api", "android"}}
+		commands, err := aliases.ResolveCommand(command)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(commands) != 1 || commands[0].Name != "gt" {
+			t.Fatalf("Expected single gt command, got %+v", commands)
+		}
+		if strings.Join(commands[0].Queries, ",") != "api,android" {
+			t.Errorf("Expected queries [api android], got %v", commands[0].Queries)
+		}
+	})
+
+	t.Run("Nested alias resolves fully", func(t *testing.T) {
+		commands, err := aliases.ResolveCommand(CompletedCommand{Name: "later", Queries: []string{""}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(commands) != 1 || commands[0].Name != "wp" {
+			t.Errorf("Expected wp, got %+v", commands)
+		}
+	})
+
+	t.Run("Macro substitutes positional parameters", func(t *testing.T) {
+		command := CompletedCommand{Name: "prep", Queries: []string{"pedro"}, SelectedFile: selected}
+		commands, err := aliases.ResolveCommand(command)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(commands) != 2 {
+			t.Fatalf("Expected 2 commands, got %+v", commands)
+		}
+		if commands[0].Name != "cm" || commands[0].Queries[0] != "1-1-pedro" {
+			t.Errorf("Expected cm 1-1-pedro, got %+v", commands[0])
+		}
+		if commands[1].Name != "tt" || commands[1].Queries[0] != "pedro" {
+			t.Errorf("Expected tt pedro, got %+v", commands[1])
+		}
+		if commands[1].SelectedFile.Name != "selected.md" {
+			t.Errorf("Expected selected file to be kept, got '%s'", commands[1].SelectedFile.Name)
+		}
+	})
+
+	t.Run("Macro substitutes all parameters", func(t *testing.T) {
+		commands, err := aliases.ResolveCommand(CompletedCommand{Name: "all", Queries: []string{"work", "api"}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(commands[0].Queries, ",") != "work,api" {
+			t.Errorf("Expected queries [work api], got %v", commands[0].Queries)
+		}
+	})
+
+	t.Run("Macro steps can use aliases", func(t *testing.T) {
+		commands, err := aliases.ResolveCommand(CompletedCommand{Name: "week", Queries: []string{""}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(commands) != 2 || commands[0].Name != "wp" || commands[1].Name != "gto" {
+			t.Errorf("Expected wp then gto, got %+v", commands)
+		}
+	})
+}
+
+func TestCommandAliases_DetectsRecursion(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases CommandAliases
+		command string
+	}{
+		{
+			name:    "Self referencing alias",
+			aliases: CommandAliases{Aliases: map[string]string{"loop": "loop"}},
+			command: "loop",
+		},
+		{
+			name:    "Mutual aliases",
+			aliases: CommandAliases{Aliases: map[string]string{"a": "b", "b": "a"}},
+			command: "a",
+		},
+		{
+			name: "Macro calling itself through an alias",
+			aliases: CommandAliases{
+				Aliases: map[string]string{"again": "m"},
+				Macros:  map[string][]string{"m": {"gt", "again"}},
+			},
+			command: "m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.aliases.ResolveCommand(CompletedCommand{Name: tt.command, Queries: []string{""}})
+			if err == nil {
+				t.Fatal("Expected recursion error")
+			}
+			if !strings.Contains(err.Error(), "recursion") {
+				t.Errorf("Expected recursion error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSubstituteMacroParams(t *testing.T) {
+	tests := []struct {
+		step     string
+		args     []string
+		expected string
+	}{
+		{step: "cm 1-1-$1", args: []string{"pedro"}, expected: "cm 1-1-pedro"},
+		{step: "gt $2, $1", args: []string{"a", "b"}, expected: "gt b, a"},
+		{step: "gta $*", args: []string{"a", "b"}, expected: "gta a, b"},
+		{step: "tt $1", args: []string{}, expected: "tt "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.step, func(t *testing.T) {
+			result := substituteMacroParams(tt.step, tt.args)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
@@ -9,13 +9,14 @@ import (
 
 // AutocompleteState tracks the state of autocomplete during command input
 type AutocompleteState struct {
-	Input      string        // The user's input text (e.g., "an")
-	Candidates []scripts.File // All matching objectives
-	Index      int           // Current selected index in candidates (-1 if not cycling)
+	Prefix     string   // Command text kept in front of the completion (e.g., "ob ")
+	Input      string   // The user's input text (e.g., "an")
+	Candidates []string // All matching candidates
+	Index      int      // Current selected index in candidates (-1 if not cycling)
 }
 
 // NewAutocompleteState creates a new autocomplete state
-func NewAutocompleteState(input string, candidates []scripts.File) AutocompleteState {
+func NewAutocompleteState(input string, candidates []string) AutocompleteState {
 	return AutocompleteState{
 		Input:      input,
 		Candidates: candidates,
@@ -33,17 +34,22 @@ func (a *AutocompleteState) GetCurrentCompletion() string {
 
 	if a.Index >= 0 && a.Index < len(a.Candidates) {
 		// Cycling through candidates
-		return a.Candidates[a.Index].Title
+		return a.Candidates[a.Index]
 	}
 
 	// Not cycling - return longest common prefix if there are multiple candidates
 	if len(a.Candidates) == 1 {
-		return a.Candidates[0].Title
+		return a.Candidates[0]
 	}
 
 	return longestCommonPrefix(a.Candidates)
 }
 
+// GetCurrentText returns the full command text for the current completion
+func (a *AutocompleteState) GetCurrentText() string {
+	return a.Prefix + a.GetCurrentCompletion()
+}
+
 // CycleNext advances to the next candidate
 func (a *AutocompleteState) CycleNext() {
 	if len(a.Candidates) == 0 {
@@ -56,19 +62,19 @@ func (a *AutocompleteState) CycleNext() {
 	}
 }
 
-// longestCommonPrefix finds the longest common prefix among all candidate titles
-func longestCommonPrefix(candidates []scripts.File) string {
+// longestCommonPrefix finds the longest common prefix among all candidates
+func longestCommonPrefix(candidates []string) string {
 	if len(candidates) == 0 {
 		return ""
 	}
 
 	if len(candidates) == 1 {
-		return candidates[0].Title
+		return candidates[0]
 	}
 
-	prefix := candidates[0].Title
+	prefix := candidates[0]
 	for i := 1; i < len(candidates); i++ {
-		prefix = commonPrefix(prefix, candidates[i].Title)
+		prefix = commonPrefix(prefix, candidates[i])
 		if prefix == "" {
 			break
 		}
@@ -104,15 +110,12 @@ func FuzzyFilterObjectives(objectives []scripts.File, pattern string) []scripts.
 		return objectives
 	}
 
-	patternLower := strings.ToLower(pattern)
-
-	// Create lowercase titles for case-insensitive matching
-	lowercaseTitles := make([]string, len(objectives))
+	titles := make([]string, len(objectives))
 	for i, obj := range objectives {
-		lowercaseTitles[i] = strings.ToLower(obj.Title)
+		titles[i] = obj.Title
 	}
 
-	matches := fuzzy.Find(patternLower, lowercaseTitles)
+	matches := fuzzyFind(pattern, titles)
 
 	result := make([]scripts.File, len(matches))
 	for i, match := range matches {
@@ -121,3 +124,48 @@ func FuzzyFilterObjectives(objectives []scripts.File, pattern string) []scripts.
 
 	return result
 }
+
+// FuzzyFilterStrings filters candidates using fzf-style fuzzy matching
+// Results are sorted by match quality (best first)
+func FuzzyFilterStrings(candidates []string, pattern string) []string {
+	if pattern == "" {
+		return candidates
+	}
+
+	matches := fuzzyFind(pattern, candidates)
+
+	result := make([]string, len(matches))
+	for i, match := range matches {
+		result[i] = candidates[match.Index]
+	}
+
+	return result
+}
+
+// PrefixFilterStrings keeps candidates starting with pattern (case-insensitive)
+// Candidate order is preserved
+func PrefixFilterStrings(candidates []string, pattern string) []string {
+	patternLower := strings.ToLower(pattern)
+
+	result := make([]string, 0)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), patternLower) {
+			result = append(result, candidate)
+		}
+	}
+
+	return result
+}
+
+// fuzzyFind runs a case-insensitive fuzzy match of pattern against targets
+func fuzzyFind(pattern string, targets []string) fuzzy.Matches {
+	patternLower := strings.ToLower(pattern)
+
+	// Create lowercase targets for case-insensitive matching
+	lowercaseTargets := make([]string, len(targets))
+	for i, target := range targets {
+		lowercaseTargets[i] = strings.ToLower(target)
+	}
+
+	return fuzzy.Find(patternLower, lowercaseTargets)
+}
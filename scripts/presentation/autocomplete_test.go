@@ -85,7 +85,7 @@ func TestAutocompleteState_GetCurrentCompletion(t *testing.T) {
 	}
 
 	t.Run("Single candidate returns title", func(t *testing.T) {
-		state := NewAutocompleteState("q", []scripts.File{objectives[2]})
+		state := NewAutocompleteState("q", []string{objectives[2].Title})
 		result := state.GetCurrentCompletion()
 		if result != "quarterly-goals" {
 			t.Errorf("Expected 'quarterly-goals', got '%s'", result)
@@ -93,7 +93,7 @@ func TestAutocompleteState_GetCurrentCompletion(t *testing.T) {
 	})
 
 	t.Run("Multiple candidates return longest common prefix", func(t *testing.T) {
-		state := NewAutocompleteState("an", []scripts.File{objectives[0], objectives[1]})
+		state := NewAutocompleteState("an", []string{objectives[0].Title, objectives[1].Title})
 		result := state.GetCurrentCompletion()
 		if result != "annual-" {
 			t.Errorf("Expected 'annual-', got '%s'", result)
@@ -101,7 +101,7 @@ func TestAutocompleteState_GetCurrentCompletion(t *testing.T) {
 	})
 
 	t.Run("No candidates returns input", func(t *testing.T) {
-		state := NewAutocompleteState("xyz", []scripts.File{})
+		state := NewAutocompleteState("xyz", []string{})
 		result := state.GetCurrentCompletion()
 		if result != "xyz" {
 			t.Errorf("Expected 'xyz', got '%s'", result)
@@ -109,10 +109,7 @@ func TestAutocompleteState_GetCurrentCompletion(t *testing.T) {
 	})
 
 	t.Run("Case insensitive common prefix", func(t *testing.T) {
-		mixedCaseObjectives := []scripts.File{
-			{Title: "Annual-review", Name: "annual-review.md"},
-			{Title: "annual-planning", Name: "annual-planning.md"},
-		}
+		mixedCaseObjectives := []string{"Annual-review", "annual-planning"}
 		state := NewAutocompleteState("AN", mixedCaseObjectives)
 		result := state.GetCurrentCompletion()
 		// Should return "Annual-" (preserving case from first candidate)
@@ -123,11 +120,7 @@ func TestAutocompleteState_GetCurrentCompletion(t *testing.T) {
 }
 
 func TestAutocompleteState_CycleNext(t *testing.T) {
-	objectives := []scripts.File{
-		{Title: "objective-a", Name: "objective-a.md"},
-		{Title: "objective-b", Name: "objective-b.md"},
-		{Title: "objective-c", Name: "objective-c.md"},
-	}
+	objectives := []string{"objective-a", "objective-b", "objective-c"}
 
 	state := NewAutocompleteState("obj", objectives)
 
@@ -176,53 +169,38 @@ func TestAutocompleteState_CycleNext(t *testing.T) {
 func TestLongestCommonPrefix(t *testing.T) {
 	tests := []struct {
 		name       string
-		candidates []scripts.File
+		candidates []string
 		expected   string
 	}{
 		{
-			name: "Same prefix",
-			candidates: []scripts.File{
-				{Title: "test-one"},
-				{Title: "test-two"},
-				{Title: "test-three"},
-			},
-			expected: "test-",
+			name:       "Same prefix",
+			candidates: []string{"test-one", "test-two", "test-three"},
+			expected:   "test-",
 		},
 		{
-			name: "Exact match with prefix",
-			candidates: []scripts.File{
-				{Title: "annual-review"},
-				{Title: "annual-planning"},
-			},
-			expected: "annual-",
+			name:       "Exact match with prefix",
+			candidates: []string{"annual-review", "annual-planning"},
+			expected:   "annual-",
 		},
 		{
-			name: "No common prefix",
-			candidates: []scripts.File{
-				{Title: "apple"},
-				{Title: "banana"},
-			},
-			expected: "",
+			name:       "No common prefix",
+			candidates: []string{"apple", "banana"},
+			expected:   "",
 		},
 		{
-			name: "Single candidate",
-			candidates: []scripts.File{
-				{Title: "single"},
-			},
-			expected: "single",
+			name:       "Single candidate",
+			candidates: []string{"single"},
+			expected:   "single",
 		},
 		{
 			name:       "Empty list",
-			candidates: []scripts.File{},
+			candidates: []string{},
 			expected:   "",
 		},
 		{
-			name: "Case insensitive common prefix",
-			candidates: []scripts.File{
-				{Title: "Annual-review"},
-				{Title: "annual-planning"},
-			},
-			expected: "Annual-",
+			name:       "Case insensitive common prefix",
+			candidates: []string{"Annual-review", "annual-planning"},
+			expected:   "Annual-",
 		},
 	}
 
@@ -272,6 +250,7 @@ func TestCommandHandler_TabAutocomplete(t *testing.T) {
 	getObjectives := func() ([]scripts.File, error) {
 		return objectives, nil
 	}
+	completer := NewCompleter().Register(CompleteObjective, TitlesProvider(getObjectives))
 
 	t.Run("Tab without 'ob ' command returns unchanged", func(t *testing.T) {
 		cmd := WIPCommand{
@@ -287,7 +266,7 @@ func TestCommandHandler_TabAutocomplete(t *testing.T) {
 			func() scripts.File { return scripts.File{} },
 			func(scripts.File) ([]string, error) { return nil, nil },
 			func() {},
-			getObjectives,
+			completer,
 		)
 
 		if err != nil {
@@ -318,7 +297,7 @@ func TestCommandHandler_TabAutocomplete(t *testing.T) {
 			func() scripts.File { return scripts.File{} },
 			func(scripts.File) ([]string, error) { return nil, nil },
 			func() {},
-			getObjectives,
+			completer,
 		)
 
 		if err != nil {
@@ -347,7 +326,7 @@ func TestCommandHandler_TabAutocomplete(t *testing.T) {
 		}
 
 		// First Tab
-		result1, _ := CommandHandler('\t', 9, cmd, nil, nil, nil, func() {}, getObjectives)
+		result1, _ := CommandHandler('\t', 9, cmd, nil, nil, nil, func() {}, completer)
 		tabResult1 := result1.(TabPressedWIPCommand)
 
 		if tabResult1.Text != "ob annual-review" {
@@ -355,7 +334,7 @@ func TestCommandHandler_TabAutocomplete(t *testing.T) {
 		}
 
 		// Second Tab
-		result2, _ := CommandHandler('\t', 9, tabResult1.WIPCommand, nil, nil, nil, func() {}, getObjectives)
+		result2, _ := CommandHandler('\t', 9, tabResult1.WIPCommand, nil, nil, nil, func() {}, completer)
 		tabResult2 := result2.(TabPressedWIPCommand)
 
 		if tabResult2.Text != "ob annual-planning" {
@@ -363,7 +342,7 @@ func TestCommandHandler_TabAutocomplete(t *testing.T) {
 		}
 
 		// Third Tab (wrap around)
-		result3, _ := CommandHandler('\t', 9, tabResult2.WIPCommand, nil, nil, nil, func() {}, getObjectives)
+		result3, _ := CommandHandler('\t', 9, tabResult2.WIPCommand, nil, nil, nil, func() {}, completer)
 		tabResult3 := result3.(TabPressedWIPCommand)
 
 		if tabResult3.Text != "ob annual-review" {
@@ -54,7 +54,7 @@ func CommandHandler(
 	selectPrevFile func() scripts.File,
 	getTasksInFile func(scripts.File) ([]string, error),
 	onBackSpace func(),
-	getObjectives func() ([]scripts.File, error),
+	completer *Completer,
 ) (Command, error) {
 	switch key {
 	case keyboard.KeyArrowUp:
@@ -86,53 +86,40 @@ func CommandHandler(
 		}, nil
 
 	case keyboard.KeyTab:
-		// Handle Tab for autocomplete in "ob <text>" commands
-		// Works both with and without a file selected (for linking vs navigation)
-		if strings.HasPrefix(currentCommand.Text, "ob ") {
-			// Extract the input after "ob "
-			input := strings.TrimPrefix(currentCommand.Text, "ob ")
-
-			// If we're already in autocomplete mode, cycle to next candidate
-			if currentCommand.AutocompleteState != nil {
-				currentCommand.AutocompleteState.CycleNext()
-				completion := currentCommand.AutocompleteState.GetCurrentCompletion()
-
-				return TabPressedWIPCommand{
-					WIPCommand: WIPCommand{
-						Text:              "ob " + completion,
-						SelectedFile:      currentCommand.SelectedFile,
-						AutocompleteState: currentCommand.AutocompleteState,
-					},
-				}, nil
-			}
-
-			// First Tab press - initialize autocomplete
-			objectives, err := getObjectives()
-			if err != nil {
-				return nil, err
-			}
-
-			candidates := FuzzyFilterObjectives(objectives, input)
-			if len(candidates) == 0 {
-				// No matches, return unchanged
-				return currentCommand, nil
-			}
-
-			state := NewAutocompleteState(input, candidates)
-			state.CycleNext() // Move to first candidate
-			completion := state.GetCurrentCompletion()
+		// If we're already in autocomplete mode, cycle to next candidate
+		if currentCommand.AutocompleteState != nil {
+			currentCommand.AutocompleteState.CycleNext()
 
 			return TabPressedWIPCommand{
 				WIPCommand: WIPCommand{
-					Text:              "ob " + completion,
+					Text:              currentCommand.AutocompleteState.GetCurrentText(),
 					SelectedFile:      currentCommand.SelectedFile,
-					AutocompleteState: &state,
+					AutocompleteState: currentCommand.AutocompleteState,
 				},
 			}, nil
 		}
 
-		// Tab not applicable, return unchanged
-		return currentCommand, nil
+		// First Tab press - initialize autocomplete for the current context
+		// Works both with and without a file selected
+		state, err := completer.Start(currentCommand.Text)
+		if err != nil {
+			return nil, err
+		}
+
+		if state == nil {
+			// Tab not applicable or no matches, return unchanged
+			return currentCommand, nil
+		}
+
+		state.CycleNext() // Move to first candidate
+
+		return TabPressedWIPCommand{
+			WIPCommand: WIPCommand{
+				Text:              state.GetCurrentText(),
+				SelectedFile:      currentCommand.SelectedFile,
+				AutocompleteState: state,
+			},
+		}, nil
 
 	case keyboard.KeyEnter:
 		completed := ToCompletedCommand(currentCommand)
@@ -216,6 +203,22 @@ func CommandHandler(
 	}
 }
 
+// spaceSeparatedCommands take space separated arguments instead of comma separated ones
+var spaceSeparatedCommands = map[string]bool{
+	"gd":            true,
+	"asof":          true,
+	"churn":         true,
+	"doctor":        true,
+	"export-graph":  true,
+	"hist":          true,
+	"restore":       true,
+	"log":           true,
+	"verify-backup": true,
+	"tag-rename":    true,
+	"tag-merge":     true,
+	"tag-rm":        true,
+}
+
 func ToCompletedCommand(wip WIPCommand) CompletedCommand {
 	parts := strings.Split(wip.Text, " ")
 	name := parts[0]
@@ -223,10 +226,10 @@ func ToCompletedCommand(wip WIPCommand) CompletedCommand {
 	remaining := strings.Join(parts[1:], " ")
 
 	var queries []string
-	
-	// Special handling for gd command - use space-separated arguments
-	if name == "gd" {
-		// Split by spaces for date arguments
+
+	// Special handling for gd and the tag commands - use space-separated arguments
+	if spaceSeparatedCommands[name] {
+		// Split by spaces for date and tag arguments
 		spaceParts := strings.Fields(remaining)
 		queries = make([]string, len(spaceParts))
 		for i, part := range spaceParts {
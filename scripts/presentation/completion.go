@@ -0,0 +1,192 @@
+package presentation
+
+import (
+	"cli-notes/scripts"
+	"strings"
+)
+
+// CompletionContext identifies what kind of value is being completed
+type CompletionContext int
+
+const (
+	CompleteCommand   CompletionContext = iota // Command name at the start of the line
+	CompleteTag                                // Tag after "gta" or a tag command
+	CompletePerson                             // Person after "tt"
+	CompleteNoteTitle                          // Note title after "o" or inside "[["
+	CompleteObjective                          // Objective title after "ob"
+)
+
+// CompletionProvider returns every candidate for a completion context
+type CompletionProvider func() ([]string, error)
+
+// CompletionTarget describes the part of the command text being completed
+type CompletionTarget struct {
+	Context  CompletionContext
+	Prefix   string // Text kept in front of the completion
+	Fragment string // Text the user has typed so far for the completion
+}
+
+// contextsByCommand maps a command name to the context of its argument
+var contextsByCommand = map[string]CompletionContext{
+	"gta":        CompleteTag,
+	"tag-rename": CompleteTag,
+	"tag-merge":  CompleteTag,
+	"tag-rm":     CompleteTag,
+	"tt":         CompletePerson,
+	"o":          CompleteNoteTitle,
+	"ob":         CompleteObjective,
+}
+
+// Completer resolves Tab completion candidates using pluggable providers
+type Completer struct {
+	providers map[CompletionContext]CompletionProvider
+}
+
+// NewCompleter creates a completer with no providers registered
+func NewCompleter() *Completer {
+	return &Completer{
+		providers: make(map[CompletionContext]CompletionProvider),
+	}
+}
+
+// Register sets the provider for a completion context
+// Returns the completer so registrations can be chained
+func (c *Completer) Register(context CompletionContext, provider CompletionProvider) *Completer {
+	c.providers[context] = provider
+	return c
+}
+
+// Start builds a new autocomplete state for the given command text
+// Returns nil when no provider applies or nothing matches
+func (c *Completer) Start(text string) (*AutocompleteState, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	target, ok := ParseCompletionTarget(text)
+	if !ok {
+		return nil, nil
+	}
+
+	provider, ok := c.providers[target.Context]
+	if !ok {
+		return nil, nil
+	}
+
+	candidates, err := provider()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := filterCandidates(target.Context, candidates, target.Fragment)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	state := NewAutocompleteState(target.Fragment, matches)
+	state.Prefix = target.Prefix
+	return &state, nil
+}
+
+// ParseCompletionTarget works out which context applies to the command text
+// and which part of it should be replaced by the completion
+func ParseCompletionTarget(text string) (CompletionTarget, bool) {
+	// An unclosed [[ anywhere in the line completes a note title
+	if openIdx := strings.LastIndex(text, "[["); openIdx >= 0 {
+		if !strings.Contains(text[openIdx:], "]]") {
+			return CompletionTarget{
+				Context:  CompleteNoteTitle,
+				Prefix:   text[:openIdx+2],
+				Fragment: text[openIdx+2:],
+			}, true
+		}
+	}
+
+	spaceIdx := strings.Index(text, " ")
+	if spaceIdx < 0 {
+		if text == "" {
+			return CompletionTarget{}, false
+		}
+		return CompletionTarget{
+			Context:  CompleteCommand,
+			Fragment: text,
+		}, true
+	}
+
+	name := text[:spaceIdx]
+	context, ok := contextsByCommand[name]
+	if !ok {
+		return CompletionTarget{}, false
+	}
+
+	// Arguments are comma (or space) separated, so only the last one is completed
+	separator := ","
+	if spaceSeparatedCommands[name] {
+		separator = " "
+	}
+	argStart := spaceIdx + 1
+	if sepIdx := strings.LastIndex(text, separator); sepIdx >= argStart {
+		argStart = sepIdx + 1
+	}
+	for argStart < len(text) && text[argStart] == ' ' {
+		argStart++
+	}
+
+	return CompletionTarget{
+		Context:  context,
+		Prefix:   text[:argStart],
+		Fragment: text[argStart:],
+	}, true
+}
+
+// filterCandidates narrows candidates to those matching the fragment
+// Short identifiers match by prefix, titles match fuzzily
+func filterCandidates(context CompletionContext, candidates []string, fragment string) []string {
+	candidates = uniqueStrings(candidates)
+
+	switch context {
+	case CompleteNoteTitle, CompleteObjective:
+		return FuzzyFilterStrings(candidates, fragment)
+	default:
+		return PrefixFilterStrings(candidates, fragment)
+	}
+}
+
+// TitlesProvider adapts a file query into a provider of note titles
+func TitlesProvider(getFiles func() ([]scripts.File, error)) CompletionProvider {
+	return func() ([]string, error) {
+		files, err := getFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		titles := make([]string, 0, len(files))
+		for _, file := range files {
+			if file.Title != "" {
+				titles = append(titles, file.Title)
+			}
+		}
+		return titles, nil
+	}
+}
+
+// StaticProvider returns a provider that always offers the same candidates
+func StaticProvider(candidates []string) CompletionProvider {
+	return func() ([]string, error) {
+		return candidates, nil
+	}
+}
+
+// uniqueStrings removes duplicate values while preserving order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,237 @@
+package presentation
+
+import (
+	"cli-notes/scripts"
+	"errors"
+	"testing"
+)
+
+func TestParseCompletionTarget(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantOK       bool
+		wantContext  CompletionContext
+		wantPrefix   string
+		wantFragment string
+	}{
+		{
+			name:         "Command name",
+			text:         "gt",
+			wantOK:       true,
+			wantContext:  CompleteCommand,
+			wantPrefix:   "",
+			wantFragment: "gt",
+		},
+		{
+			name:   "Empty text",
+			text:   "",
+			wantOK: false,
+		},
+		{
+			name:         "Tag after gta",
+			text:         "gta wo",
+			wantOK:       true,
+			wantContext:  CompleteTag,
+			wantPrefix:   "gta ",
+			wantFragment: "wo",
+		},
+		{
+			name:         "Last comma separated tag",
+			text:         "gta work, and",
+			wantOK:       true,
+			wantContext:  CompleteTag,
+			wantPrefix:   "gta work, ",
+			wantFragment: "and",
+		},
+		{
+			name:         "Second space separated tag",
+			text:         "tag-merge todo ta",
+			wantOK:       true,
+			wantContext:  CompleteTag,
+			wantPrefix:   "tag-merge todo ",
+			wantFragment: "ta",
+		},
+		{
+			name:         "Person after tt",
+			text:         "tt pe",
+			wantOK:       true,
+			wantContext:  CompletePerson,
+			wantPrefix:   "tt ",
+			wantFragment: "pe",
+		},
+		{
+			name:         "Note title after o",
+			text:         "o meeting notes",
+			wantOK:       true,
+			wantContext:  CompleteNoteTitle,
+			wantPrefix:   "o ",
+			wantFragment: "meeting notes",
+		},
+		{
+			name:         "Objective after ob",
+			text:         "ob an",
+			wantOK:       true,
+			wantContext:  CompleteObjective,
+			wantPrefix:   "ob ",
+			wantFragment: "an",
+		},
+		{
+			name:         "Unclosed link",
+			text:         "ct call about [[pay",
+			wantOK:       true,
+			wantContext:  CompleteNoteTitle,
+			wantPrefix:   "ct call about [[",
+			wantFragment: "pay",
+		},
+		{
+			name:   "Closed link falls back to command",
+			text:   "ct call about [[payments]] now",
+			wantOK: false,
+		},
+		{
+			name:   "Command without completable argument",
+			text:   "d 3",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, ok := ParseCompletionTarget(tt.text)
+			if ok != tt.wantOK {
+				t.Fatalf("Expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if !ok {
+				return
+			}
+			if target.Context != tt.wantContext {
+				t.Errorf("Expected context %v, got %v", tt.wantContext, target.Context)
+			}
+			if target.Prefix != tt.wantPrefix {
+				t.Errorf("Expected prefix '%s', got '%s'", tt.wantPrefix, target.Prefix)
+			}
+			if target.Fragment != tt.wantFragment {
+				t.Errorf("Expected fragment '%s', got '%s'", tt.wantFragment, target.Fragment)
+			}
+		})
+	}
+}
+
+func TestCompleter_Start(t *testing.T) {
+	completer := NewCompleter().
+		Register(CompleteCommand, StaticProvider([]string{"gt", "gta", "gto", "ob"})).
+		Register(CompleteTag, StaticProvider([]string{"android", "api", "work"})).
+		Register(CompleteNoteTitle, TitlesProvider(func() ([]scripts.File, error) {
+			return []scripts.File{
+				{Title: "Payments API"},
+				{Title: "Payments API"},
+				{Title: "Standup"},
+			}, nil
+		}))
+
+	t.Run("Command names match by prefix", func(t *testing.T) {
+		state, err := completer.Start("gt")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state == nil {
+			t.Fatal("Expected autocomplete state")
+		}
+		if len(state.Candidates) != 3 {
+			t.Errorf("Expected 3 candidates, got %v", state.Candidates)
+		}
+	})
+
+	t.Run("Tags keep the earlier arguments", func(t *testing.T) {
+		state, err := completer.Start("gta work, an")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state == nil {
+			t.Fatal("Expected autocomplete state")
+		}
+		state.CycleNext()
+		if state.GetCurrentText() != "gta work, android" {
+			t.Errorf("Expected 'gta work, android', got '%s'", state.GetCurrentText())
+		}
+	})
+
+	t.Run("Duplicate titles are offered once", func(t *testing.T) {
+		state, err := completer.Start("see [[pay")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state == nil {
+			t.Fatal("Expected autocomplete state")
+		}
+		if len(state.Candidates) != 1 {
+			t.Errorf("Expected 1 candidate, got %v", state.Candidates)
+		}
+		state.CycleNext()
+		if state.GetCurrentText() != "see [[Payments API" {
+			t.Errorf("Expected 'see [[Payments API', got '%s'", state.GetCurrentText())
+		}
+	})
+
+	t.Run("Unregistered context returns nil", func(t *testing.T) {
+		state, err := completer.Start("tt pe")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state != nil {
+			t.Errorf("Expected nil state, got %+v", state)
+		}
+	})
+
+	t.Run("No matches returns nil", func(t *testing.T) {
+		state, err := completer.Start("gta xyz")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state != nil {
+			t.Errorf("Expected nil state, got %+v", state)
+		}
+	})
+
+	t.Run("Provider errors are returned", func(t *testing.T) {
+		failing := NewCompleter().Register(CompletePerson, func() ([]string, error) {
+			return nil, errors.New("scan failed")
+		})
+		_, err := failing.Start("tt pe")
+		if err == nil {
+			t.Error("Expected provider error")
+		}
+	})
+}
+
+func TestCommandHandler_TabCyclesTags(t *testing.T) {
+	completer := NewCompleter().
+		Register(CompleteTag, StaticProvider([]string{"android", "api", "work"}))
+
+	cmd := WIPCommand{Text: "gta a"}
+
+	result1, err := CommandHandler('\t', 9, cmd, nil, nil, nil, func() {}, completer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tabResult1, ok := result1.(TabPressedWIPCommand)
+	if !ok {
+		t.Fatalf("Expected TabPressedWIPCommand, got %T", result1)
+	}
+	if tabResult1.Text != "gta android" {
+		t.Errorf("First tab: Expected 'gta android', got '%s'", tabResult1.Text)
+	}
+
+	result2, _ := CommandHandler('\t', 9, tabResult1.WIPCommand, nil, nil, nil, func() {}, completer)
+	tabResult2 := result2.(TabPressedWIPCommand)
+	if tabResult2.Text != "gta api" {
+		t.Errorf("Second tab: Expected 'gta api', got '%s'", tabResult2.Text)
+	}
+
+	result3, _ := CommandHandler('\t', 9, tabResult2.WIPCommand, nil, nil, nil, func() {}, completer)
+	tabResult3 := result3.(TabPressedWIPCommand)
+	if tabResult3.Text != "gta android" {
+		t.Errorf("Third tab: Expected wrap to 'gta android', got '%s'", tabResult3.Text)
+	}
+}
@@ -2,7 +2,9 @@ package presentation
 
 import (
 	"cli-notes/scripts"
+	"cli-notes/scripts/data"
 	"fmt"
+	"strings"
 )
 
 func PrintAllFiles(files []scripts.File) {
@@ -28,3 +30,172 @@ func PrintAllFiles(files []scripts.File) {
 		isFirstFile = false
 	}
 }
+
+// PrintTagTree prints every tag with the number of notes using it,
+// indenting nested tags under their parent
+func PrintTagTree(tree []scripts.TagTreeNode) {
+	width := 0
+	for _, node := range tree {
+		if length := node.Depth*2 + len(node.Name); length > width {
+			width = length
+		}
+	}
+
+	for _, node := range tree {
+		label := strings.Repeat("  ", node.Depth) + node.Name
+		fmt.Printf("%-*s  %d\n", width, label, node.Count)
+	}
+}
+
+// PrintTagChanges prints the old and new tags of every note a tag operation touches
+func PrintTagChanges(changes []scripts.TagChange) {
+	for _, change := range changes {
+		fmt.Printf("  %s: %v -> %v\n", change.File.Name, change.OldTags, change.NewTags)
+	}
+}
+
+// PrintAmbiguousLinks prints every link that matches several notes with its candidates
+func PrintAmbiguousLinks(resolutions []data.LinkResolution) {
+	for _, resolution := range resolutions {
+		fmt.Printf("  [[%s]] matches %d notes:\n", resolution.LinkText, len(resolution.Candidates))
+		for _, candidate := range resolution.Candidates {
+			fmt.Printf("    %s  created: %s\n", candidate.Name, candidate.CreatedAt.Format("2006-01-02"))
+		}
+	}
+}
+
+// PrintDuplicateTitleGroup prints the notes sharing a title
+func PrintDuplicateTitleGroup(group scripts.DuplicateTitleGroup) {
+	fmt.Printf("%s (%d notes)\n", group.Title, len(group.Files))
+	for _, file := range group.Files {
+		fmt.Printf("  %s  created: %s\n", file.Name, file.CreatedAt.Format("2006-01-02"))
+	}
+}
+
+// PrintDatedTitles prints the title changes of a rename with dates
+func PrintDatedTitles(group scripts.DuplicateTitleGroup, renamed []scripts.File) {
+	for _, file := range renamed {
+		fmt.Printf("    %s: %s -> %s\n", file.Name, group.Title, file.Title)
+	}
+}
+
+// PrintLinkQualifications prints which note the links in every linking note will point at
+func PrintLinkQualifications(qualifications []scripts.LinkQualification) {
+	for _, qualification := range qualifications {
+		fmt.Printf("    %s: %d links -> %s\n", qualification.File.Name, qualification.Count, qualification.Target.Name)
+	}
+}
+
+// PrintMentions prints numbered mentions with the line they appear on
+func PrintMentions(mentions []scripts.Mention) {
+	for i, mention := range mentions {
+		fmt.Printf("  %d) %s:%d  %s\n", i+1, mention.File.Name, mention.Line, mention.Context())
+	}
+}
+
+// healthCheckTitles are the report headings of the doctor's checks
+var healthCheckTitles = map[string]string{
+	scripts.HealthOrphan:              "Orphan notes",
+	scripts.HealthDeadLink:            "Dead links",
+	scripts.HealthMissingParent:       "Children without a parent objective",
+	scripts.HealthMissingObjectiveTag: "Objectives without the objective tag",
+	scripts.HealthInvalidObjectiveID:  "Invalid objective IDs",
+	scripts.HealthBadDate:             "Unparseable dates",
+	scripts.HealthBadPriority:         "Priorities outside 1-3",
+	scripts.HealthMissingPriority:     "Notes without a priority",
+}
+
+// PrintHealthIssues prints the doctor's findings grouped by check, with the fix for each
+func PrintHealthIssues(issues []scripts.HealthIssue) {
+	for _, check := range scripts.HealthChecks {
+		found := make([]scripts.HealthIssue, 0)
+		for _, issue := range issues {
+			if issue.Check == check {
+				found = append(found, issue)
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		fmt.Printf("%s (%d):\n", healthCheckTitles[check], len(found))
+		for _, issue := range found {
+			fix := "no automated fix"
+			if issue.Fix != "" {
+				fix = "fix: " + issue.Fix
+			}
+			fmt.Printf("  %s  %s  (%s)\n", issue.File.Name, issue.Detail, fix)
+		}
+	}
+}
+
+// PrintHealthFixes prints the changes the doctor will make to each note
+func PrintHealthFixes(fixes []scripts.HealthFix) {
+	for _, fix := range fixes {
+		fmt.Printf("  %s: %s\n", fix.File.Name, strings.Join(fix.Changes, ", "))
+	}
+}
+
+// PrintRelatedNotes prints numbered related notes with why they are related
+func PrintRelatedNotes(related []scripts.RelatedNote) {
+	for i, note := range related {
+		fmt.Printf("  %d) %s  (%s)\n", i+1, note.File.Title, note.Reasons())
+	}
+}
+
+// maxChurnDues is how many due dates a churn line shows before eliding the middle ones
+const maxChurnDues = 5
+
+// PrintDueDateChurn prints how often each note's due date moved, with the dates it went through
+func PrintDueDateChurn(churn []scripts.DueDateChurn) {
+	for _, entry := range churn {
+		dues := entry.Dues
+		if len(dues) > maxChurnDues {
+			dues = []string{dues[0], dues[1], "…", dues[len(dues)-2], dues[len(dues)-1]}
+		}
+		fmt.Printf("  %2d  %s  %s\n", entry.Changes, entry.Title, strings.Join(dues, " → "))
+	}
+}
+
+// PrintBackupSnapshots prints the numbered backup snapshots with their note count and size
+// A note count below zero means the snapshot could not be read
+func PrintBackupSnapshots(snapshots []scripts.BackupSnapshot, noteCounts []int) {
+	for i, snapshot := range snapshots {
+		notes := fmt.Sprintf("%d notes", noteCounts[i])
+		if noteCounts[i] < 0 {
+			notes = "unreadable"
+		}
+		fmt.Printf("  %d) %s  %s  %.1f KB\n", i+1, snapshot.Time.Format("2006-01-02 15:04:05"), notes, float64(snapshot.Size)/1024)
+	}
+}
+
+// PrintBackupVerification prints what verifying a mirror of the notes found
+func PrintBackupVerification(verification scripts.BackupVerification) {
+	if verification.OK() {
+		fmt.Printf("Backup in %s: %d notes checked, all match\n", verification.Dir, verification.Checked)
+		return
+	}
+
+	fmt.Printf("Backup in %s: %d notes checked\n", verification.Dir, verification.Checked)
+	if verification.NoManifest {
+		fmt.Println("  No manifest of hashes, copies can only be compared with the vault")
+	}
+	sections := []struct {
+		title string
+		paths []string
+	}{
+		{"Missing from the backup", verification.Missing},
+		{"Only in the backup", verification.Extra},
+		{"Different from the vault", verification.Differing},
+		{"Changed since they were copied", verification.Corrupt},
+	}
+	for _, section := range sections {
+		if len(section.paths) == 0 {
+			continue
+		}
+		fmt.Printf("  %s (%d):\n", section.title, len(section.paths))
+		for _, path := range section.paths {
+			fmt.Printf("    %s\n", path)
+		}
+	}
+}
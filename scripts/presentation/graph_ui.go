@@ -30,12 +30,18 @@ func RenderGraphView(state *data.GraphViewState, termWidth, termHeight int) stri
 	sb.WriteString("║                         LINK GRAPH VIEW                              ║\n")
 	sb.WriteString("╚══════════════════════════════════════════════════════════════════════╝\n\n")
 
+	// Depth and active filters
+	sb.WriteString("  " + graphSettings(state) + "\n\n")
+
 	// Render the graph
 	renderGraph(&sb, state)
+	renderHopNodes(&sb, state)
+	renderPath(&sb, state)
 
 	// Help
 	sb.WriteString("\n───────────────────────────────────────────────────────────────────────\n")
 	sb.WriteString("j/k = navigate nodes │ Enter = go to selected │ o = open in editor │ q = quit\n")
+	sb.WriteString("+/- = depth │ t = tag │ s = status │ y = type │ x = clear filters │ p = path to note\n")
 
 	return sb.String()
 }
@@ -53,7 +59,7 @@ func renderGraph(sb *strings.Builder, state *data.GraphViewState) {
 			nodeIdx := i // Backlinks are first in the nodes list
 			selected := nodeIdx == state.SelectedIdx
 
-			title := truncateTitle(link.Title, boxWidth-4)
+			title := nodeTitle(state, nodeIdx, link.Title)
 			renderNodeBox(sb, title, selected, "│  ")
 			sb.WriteString("  │\n")
 		}
@@ -81,7 +87,7 @@ func renderGraph(sb *strings.Builder, state *data.GraphViewState) {
 			nodeIdx := len(state.BackLinks) + 1 + i // After backlinks and center
 			selected := nodeIdx == state.SelectedIdx
 
-			title := truncateTitle(link.Title, boxWidth-4)
+			title := nodeTitle(state, nodeIdx, link.Title)
 			renderNodeBox(sb, title, selected, "│  ")
 			sb.WriteString("  │\n")
 		}
@@ -90,9 +96,95 @@ func renderGraph(sb *strings.Builder, state *data.GraphViewState) {
 	}
 
 	// No links message
-	if len(state.BackLinks) == 0 && len(state.OutLinks) == 0 {
+	if len(state.BackLinks) == 0 && len(state.OutLinks) == 0 && len(state.HopNodes) == 0 {
 		sb.WriteString("\n  (No links found for this note)\n")
 	}
+
+	for _, node := range state.Nodes {
+		if node.Ambiguous {
+			sb.WriteString("\n  ? = ambiguous link, several notes share this title (run dupes to fix)\n")
+			break
+		}
+	}
+}
+
+// graphSettings describes the depth and the active filters of the graph
+func graphSettings(state *data.GraphViewState) string {
+	depth := state.Depth
+	if depth < 1 {
+		depth = 1
+	}
+	settings := fmt.Sprintf("Depth: %d", depth)
+
+	filters := make([]string, 0)
+	if state.Filter.Tag != "" {
+		filters = append(filters, "tag="+state.Filter.Tag)
+	}
+	switch state.Filter.Status {
+	case data.GraphShowOpen:
+		filters = append(filters, "open")
+	case data.GraphShowDone:
+		filters = append(filters, "done")
+	}
+	if state.Filter.Type != "" {
+		filters = append(filters, "type="+state.Filter.Type)
+	}
+	if len(filters) > 0 {
+		settings += " │ Filter: " + strings.Join(filters, ", ")
+	}
+
+	return settings
+}
+
+// renderHopNodes renders the notes beyond the direct links, grouped by distance
+func renderHopNodes(sb *strings.Builder, state *data.GraphViewState) {
+	firstHopIdx := len(state.Nodes) - len(state.HopNodes)
+
+	for i, node := range state.HopNodes {
+		if i == 0 || node.Distance != state.HopNodes[i-1].Distance {
+			sb.WriteString(fmt.Sprintf("\n  ── %d hops away ──\n", node.Distance))
+		}
+
+		selector := "  "
+		if firstHopIdx+i == state.SelectedIdx {
+			selector = "> "
+		}
+		sb.WriteString(fmt.Sprintf("  %s%s  (via %s)\n", selector, truncateTitle(node.File.Title, boxWidth-4), truncateTitle(node.Via, boxWidth-4)))
+	}
+}
+
+// renderPath renders the shortest link path to the chosen note
+// "→" means the note on the left links to the note on the right, "←" the other way round
+func renderPath(sb *strings.Builder, state *data.GraphViewState) {
+	if state.PathTarget == nil {
+		return
+	}
+
+	if len(state.Path) == 0 {
+		sb.WriteString(fmt.Sprintf("\n  No link path to %s\n", state.PathTarget.Title))
+		return
+	}
+
+	var path strings.Builder
+	for i, step := range state.Path {
+		if i > 0 {
+			if step.Forward {
+				path.WriteString(" → ")
+			} else {
+				path.WriteString(" ← ")
+			}
+		}
+		path.WriteString(step.File.Title)
+	}
+	sb.WriteString(fmt.Sprintf("\n  Path (%d hops): %s\n", len(state.Path)-1, path.String()))
+}
+
+// nodeTitle returns the box title of a node, marking ambiguous links with "?"
+func nodeTitle(state *data.GraphViewState, nodeIdx int, title string) string {
+	if nodeIdx < len(state.Nodes) && state.Nodes[nodeIdx].Ambiguous {
+		return "? " + truncateTitle(title, boxWidth-6)
+	}
+	return truncateTitle(title, boxWidth-4)
 }
 
 // renderCenterBox renders the center node with special styling
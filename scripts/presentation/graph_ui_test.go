@@ -116,6 +116,34 @@ func TestRenderGraphView_ShowsOutlinks(t *testing.T) {
 	}
 }
 
+func TestRenderGraphView_MarksAmbiguousLinks(t *testing.T) {
+	state := &data.GraphViewState{
+		CenterNode: scripts.File{Name: "center.md", Title: "Center"},
+		OutLinks: []scripts.File{
+			{Name: "standup-1.md", Title: "standup"},
+			{Name: "target.md", Title: "Target Note"},
+		},
+		BackLinks: []scripts.File{},
+		Nodes: []data.GraphNode{
+			{File: scripts.File{Name: "center.md", Title: "Center"}, IsCenter: true, Direction: "center"},
+			{File: scripts.File{Name: "standup-1.md", Title: "standup"}, Direction: "out", Ambiguous: true},
+			{File: scripts.File{Name: "target.md", Title: "Target Note"}, Direction: "out"},
+		},
+	}
+
+	output := RenderGraphView(state, 80, 24)
+
+	if !strings.Contains(output, "? standup") {
+		t.Error("Expected ambiguous link to be marked with '?'")
+	}
+	if strings.Contains(output, "? Target Note") {
+		t.Error("Expected unambiguous link not to be marked")
+	}
+	if !strings.Contains(output, "ambiguous link") {
+		t.Error("Expected a legend for the ambiguous marker")
+	}
+}
+
 func TestRenderGraphView_ShowsNoLinksMessage(t *testing.T) {
 	state := &data.GraphViewState{
 		CenterNode: scripts.File{
@@ -380,3 +408,53 @@ func TestRenderGraphView_WithRealState(t *testing.T) {
 		t.Error("Missing outgoing section")
 	}
 }
+
+func TestRenderGraphView_GroupsFurtherNotesByDistance(t *testing.T) {
+	hopNodes := []data.GraphNode{
+		{File: scripts.File{Name: "b.md", Title: "B"}, Direction: "hop", Distance: 2, Via: "A"},
+		{File: scripts.File{Name: "c.md", Title: "C"}, Direction: "hop", Distance: 3, Via: "B"},
+	}
+	state := &data.GraphViewState{
+		CenterNode: scripts.File{Name: "center.md", Title: "Center"},
+		OutLinks:   []scripts.File{{Name: "a.md", Title: "A"}},
+		HopNodes:   hopNodes,
+		Depth:      3,
+		Filter:     data.GraphFilter{Tag: "work", Status: data.GraphShowOpen},
+		Nodes: append([]data.GraphNode{
+			{File: scripts.File{Name: "center.md", Title: "Center"}, IsCenter: true, Direction: "center"},
+			{File: scripts.File{Name: "a.md", Title: "A"}, Direction: "out", Distance: 1},
+		}, hopNodes...),
+		SelectedIdx: 3,
+	}
+
+	output := RenderGraphView(state, 80, 24)
+
+	for _, expected := range []string{"Depth: 3 │ Filter: tag=work, open", "── 2 hops away ──", "    B  (via A)", "── 3 hops away ──", "  > C  (via B)"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestRenderGraphView_ShowsPath(t *testing.T) {
+	state := &data.GraphViewState{
+		CenterNode: scripts.File{Name: "center.md", Title: "Center"},
+		PathTarget: &scripts.File{Name: "d.md", Title: "D"},
+		Path: []data.GraphPathStep{
+			{File: scripts.File{Title: "Center"}, Forward: true},
+			{File: scripts.File{Title: "A"}, Forward: true},
+			{File: scripts.File{Title: "D"}, Forward: false},
+		},
+	}
+
+	output := RenderGraphView(state, 80, 24)
+	if !strings.Contains(output, "Path (2 hops): Center → A ← D") {
+		t.Errorf("Expected the path in output, got:\n%s", output)
+	}
+
+	state.Path = nil
+	output = RenderGraphView(state, 80, 24)
+	if !strings.Contains(output, "No link path to D") {
+		t.Errorf("Expected a no path message, got:\n%s", output)
+	}
+}
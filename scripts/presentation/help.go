@@ -0,0 +1,163 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommandHelp describes a single command for the help listing
+type CommandHelp struct {
+	Usage       string
+	Description string
+}
+
+// CommandHelpSection groups related commands under a heading
+type CommandHelpSection struct {
+	Title    string
+	Commands []CommandHelp
+}
+
+// CommandReference lists every built-in command shown by "help"
+var CommandReference = []CommandHelpSection{
+	{
+		Title: "Todos",
+		Commands: []CommandHelp{
+			{Usage: "gt [query, ...]", Description: "Open todos, optionally filtered"},
+			{Usage: "gto", Description: "Overdue todos"},
+			{Usage: "gtnd", Description: "Todos with no due date"},
+			{Usage: "gts", Description: "Todos due within a week"},
+			{Usage: "p1 | p2 | p3", Description: "Todos by priority"},
+			{Usage: "ct <title>[, item, ...]", Description: "Create a todo with optional checkboxes"},
+			{Usage: "asof <date|commit> [overdue|open|done]", Description: "Todos as they were at a past date, from git history"},
+			{Usage: "churn [start-date [end-date]]", Description: "Todos whose due date moved most, this quarter by default"},
+		},
+	},
+	{
+		Title: "Notes",
+		Commands: []CommandHelp{
+			{Usage: "cm <title>", Description: "Create a meeting note"},
+			{Usage: "cp <title>", Description: "Create a planning note"},
+			{Usage: "cs", Description: "Create a standup note"},
+			{Usage: "o <title>", Description: "Open a note in the editor, on the heading of o Note#Heading"},
+			{Usage: "r <title>", Description: "Rename the selected note"},
+			{Usage: "dupes [--dry-run]", Description: "Find duplicate titles and fix ambiguous links"},
+			{Usage: "doctor [--fix [--dry-run]]", Description: "Find orphans, dead links, broken objectives and bad frontmatter"},
+			{Usage: "export-graph <dot|mermaid|json> [--depth N] [--tags] [--all] [--out FILE]", Description: "Export the link graph"},
+			{Usage: "conflicts", Description: "List notes left with conflict markers by a remote sync"},
+			{Usage: "backups", Description: "List the backup snapshots of the notes"},
+			{Usage: "restore <n> [note]", Description: "Restore a note, or the whole vault, from a backup"},
+			{Usage: "verify-backup [--repair]", Description: "Check the backup copies against the notes and their hashes"},
+		},
+	},
+	{
+		Title: "Search",
+		Commands: []CommandHelp{
+			{Usage: "gs [query]", Description: "Interactive search"},
+			{Usage: "gq <query, ...>", Description: "Ranked search of all notes"},
+			{Usage: "gqa <query, ...>", Description: "Search within the previous results"},
+			{Usage: "gta <tag, ...>", Description: "Notes with all of the tags"},
+			{Usage: "gat", Description: "Open tasks in the previous results"},
+			{Usage: "gd <start> <end>", Description: "Summary of todos completed between dates"},
+		},
+	},
+	{
+		Title: "Tags",
+		Commands: []CommandHelp{
+			{Usage: "tags", Description: "All tags with usage counts"},
+			{Usage: "tag-rename <old> <new> [--dry-run]", Description: "Rename a tag across all notes"},
+			{Usage: "tag-merge <source> <target> [--dry-run]", Description: "Merge one tag into another"},
+			{Usage: "tag-rm <tag> [--dry-run]", Description: "Remove a tag from all notes"},
+		},
+	},
+	{
+		Title: "Selected note",
+		Commands: []CommandHelp{
+			{Usage: "p <1-3>", Description: "Set priority"},
+			{Usage: "d <days>", Description: "Delay the due date"},
+			{Usage: "t | m | tu | w | th | f | sa | su", Description: "Set the due date to today or the next weekday"},
+			{Usage: "cpo", Description: "Convert to a parent objective"},
+			{Usage: "gl", Description: "Outgoing links, with every match of ambiguous links"},
+			{Usage: "gb", Description: "Backlinks"},
+			{Usage: "um", Description: "Unlinked mentions, link one or all"},
+			{Usage: "rel [n]", Description: "Related notes by content, links and objective"},
+			{Usage: "ln", Description: "Link to another note by its stable ID"},
+			{Usage: "gg", Description: "Graph view, up to 3 hops with filters and paths"},
+		},
+	},
+	{
+		Title: "Views",
+		Commands: []CommandHelp{
+			{Usage: "wp | week", Description: "Weekly planner"},
+			{Usage: "ob [objective]", Description: "Objectives view, or link the selected note"},
+			{Usage: "tt [person]", Description: "Talk-to view"},
+			{Usage: "hist [--deleted]", Description: "Git history of the selected note, or deleted notes, with diff and restore"},
+		},
+	},
+	{
+		Title: "Program",
+		Commands: []CommandHelp{
+			{Usage: "help", Description: "Show this help"},
+			{Usage: "log [n]", Description: "Show the last n log entries, 20 by default, and clear the problem indicator"},
+			{Usage: "exit | quit | q", Description: "Exit the program"},
+		},
+	},
+}
+
+// CommandNames returns every built-in command name, in reference order
+func CommandNames() []string {
+	names := make([]string, 0)
+	for _, section := range CommandReference {
+		for _, command := range section.Commands {
+			names = append(names, commandNamesFromUsage(command.Usage)...)
+		}
+	}
+	return names
+}
+
+// commandNamesFromUsage extracts the command names from a usage line
+// e.g. "p1 | p2 | p3" -> [p1 p2 p3], "gt [query, ...]" -> [gt]
+func commandNamesFromUsage(usage string) []string {
+	names := make([]string, 0)
+	for _, alternative := range strings.Split(usage, "|") {
+		fields := strings.Fields(alternative)
+		if len(fields) > 0 {
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
+
+// RenderHelp formats the command reference followed by any aliases and macros
+func RenderHelp(aliases CommandAliases) string {
+	var sb strings.Builder
+
+	usageWidth := 0
+	for _, section := range CommandReference {
+		for _, command := range section.Commands {
+			if len(command.Usage) > usageWidth {
+				usageWidth = len(command.Usage)
+			}
+		}
+	}
+
+	for _, section := range CommandReference {
+		sb.WriteString(section.Title + ":\n")
+		for _, command := range section.Commands {
+			sb.WriteString(fmt.Sprintf("  %-*s  %s\n", usageWidth, command.Usage, command.Description))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(aliases.Aliases) > 0 || len(aliases.Macros) > 0 {
+		sb.WriteString("Aliases and macros:\n")
+		for _, name := range aliases.Names() {
+			if expansion, ok := aliases.Aliases[name]; ok {
+				sb.WriteString(fmt.Sprintf("  %-*s  = %s\n", usageWidth, name, expansion))
+			} else {
+				sb.WriteString(fmt.Sprintf("  %-*s  = %s\n", usageWidth, name, strings.Join(aliases.Macros[name], "; ")))
+			}
+		}
+	}
+
+	return sb.String()
+}
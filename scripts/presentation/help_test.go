@@ -0,0 +1,51 @@
+package presentation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandNames_ExpandsAlternatives(t *testing.T) {
+	names := CommandNames()
+
+	for _, expected := range []string{"gt", "p1", "p3", "week", "sa", "help", "q"} {
+		found := false
+		for _, name := range names {
+			if name == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected command names to include '%s'", expected)
+		}
+	}
+}
+
+func TestRenderHelp_ListsAliasesAndMacros(t *testing.T) {
+	output := RenderHelp(CommandAliases{
+		Aliases: map[string]string{"wk": "wp"},
+		Macros:  map[string][]string{"prep": {"cm 1-1-$1", "tt $1"}},
+	})
+
+	if !strings.Contains(output, "Weekly planner") {
+		t.Error("Expected built-in commands to be listed")
+	}
+	if !strings.Contains(output, "Aliases and macros:") {
+		t.Error("Expected aliases section")
+	}
+	if !strings.Contains(output, "= wp") {
+		t.Error("Expected alias expansion to be listed")
+	}
+	if !strings.Contains(output, "= cm 1-1-$1; tt $1") {
+		t.Error("Expected macro steps to be listed")
+	}
+}
+
+func TestRenderHelp_OmitsEmptyAliasSection(t *testing.T) {
+	output := RenderHelp(CommandAliases{})
+
+	if strings.Contains(output, "Aliases and macros:") {
+		t.Error("Expected no aliases section without aliases")
+	}
+}
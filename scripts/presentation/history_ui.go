@@ -0,0 +1,162 @@
+package presentation
+
+import (
+	"cli-notes/scripts"
+	"cli-notes/scripts/data"
+	"fmt"
+	"strings"
+)
+
+// historyDiffContext is how many unchanged lines are shown around each change in the diff preview
+const historyDiffContext = 2
+
+// RenderHistoryView renders the full-screen history of a note, or the deleted notes, with a preview
+func RenderHistoryView(state *data.HistoryViewState, termWidth, termHeight int) string {
+	var output strings.Builder
+	dims := calculateSearchDimensions(termWidth, termHeight)
+
+	// Clear screen
+	output.WriteString("\033[2J\033[H")
+
+	output.WriteString("┌" + strings.Repeat("─", termWidth-2) + "┐\n")
+	header := fmt.Sprintf(" HISTORY: %s (%d commits)", state.Title, len(state.Versions))
+	if state.IsDeletedNotes() {
+		header = fmt.Sprintf(" DELETED NOTES (%d)", len(state.Versions))
+	}
+	output.WriteString("│" + padRight(header, termWidth-2) + "│\n")
+
+	listHeader := " Commits"
+	previewHeader := " Changes since this version (- then, + now)"
+	if state.IsDeletedNotes() {
+		listHeader = " Deleted notes"
+		previewHeader = " Last version before deletion"
+	}
+	output.WriteString("├" + strings.Repeat("─", dims.leftPanelWidth) + "┬" + strings.Repeat("─", dims.rightPanelWidth) + "┤\n")
+	output.WriteString("│" + padRight(listHeader, dims.leftPanelWidth) + "│" + padRight(previewHeader, dims.rightPanelWidth) + "│\n")
+	output.WriteString("├" + strings.Repeat("─", dims.leftPanelWidth) + "┼" + strings.Repeat("─", dims.rightPanelWidth) + "┤\n")
+
+	leftLines := buildHistoryListPanel(state, dims)
+	rightLines := buildHistoryPreviewPanel(state, dims)
+	for i := 0; i < dims.visibleResults; i++ {
+		leftContent := ""
+		rightContent := ""
+		if i < len(leftLines) {
+			leftContent = leftLines[i]
+		}
+		if i < len(rightLines) {
+			rightContent = rightLines[i]
+		}
+		output.WriteString(renderSearchSplitLine(leftContent, rightContent, dims))
+	}
+
+	output.WriteString("├" + strings.Repeat("─", dims.leftPanelWidth) + "┴" + strings.Repeat("─", dims.rightPanelWidth) + "┤\n")
+	output.WriteString("│" + padRight(" j/k:Navigate  r:Restore this version  q:Quit", termWidth-2) + "│\n")
+	output.WriteString("└" + strings.Repeat("─", termWidth-2) + "┘\n")
+
+	return output.String()
+}
+
+// buildHistoryListPanel lists the commits, keeping the selected one in view
+func buildHistoryListPanel(state *data.HistoryViewState, dims searchDimensions) []string {
+	if len(state.Versions) == 0 {
+		if state.IsDeletedNotes() {
+			return []string{" No deleted notes in history"}
+		}
+		return []string{" No commits"}
+	}
+
+	start := 0
+	if state.SelectedIdx >= dims.visibleResults {
+		start = state.SelectedIdx - dims.visibleResults + 1
+	}
+
+	lines := make([]string, 0, dims.visibleResults)
+	for i := start; i < len(state.Versions) && len(lines) < dims.visibleResults; i++ {
+		version := state.Versions[i]
+		indicator := "  "
+		if i == state.SelectedIdx {
+			indicator = "► "
+		}
+
+		description := version.Subject
+		if state.IsDeletedNotes() {
+			description = version.Path
+		} else if version.Deleted {
+			description = "[deleted] " + description
+		}
+		line := fmt.Sprintf("%s%s %s  %s", indicator, version.Date.Format("2006-01-02 15:04"), version.ShortHash(), description)
+		lines = append(lines, truncateString(line, dims.leftPanelWidth-1))
+	}
+	return lines
+}
+
+// buildHistoryPreviewPanel shows the diff of the selected version against the current note,
+// or the content of a deleted note
+func buildHistoryPreviewPanel(state *data.HistoryViewState, dims searchDimensions) []string {
+	version := state.SelectedVersion()
+	if version == nil {
+		return []string{" (no selection)"}
+	}
+
+	lines := []string{
+		truncateString(fmt.Sprintf(" %s  %s", version.ShortHash(), version.Subject), dims.rightPanelWidth-1),
+		truncateString(" Restores to "+state.RestoreTarget(), dims.rightPanelWidth-1),
+		"",
+	}
+
+	if state.IsDeletedNotes() {
+		content, err := state.SelectedContent()
+		if err != nil {
+			return append(lines, truncateString(" Error: "+err.Error(), dims.rightPanelWidth-1))
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+			lines = append(lines, truncateString(" "+line, dims.rightPanelWidth-1))
+		}
+		return lines
+	}
+
+	diff, err := state.SelectedDiff()
+	if err != nil {
+		return append(lines, truncateString(" Error: "+err.Error(), dims.rightPanelWidth-1))
+	}
+	if state.IsCurrentDeleted() {
+		lines = append(lines, " The note has been deleted since")
+	} else if !scripts.DiffChanged(diff) {
+		return append(lines, " Same as the current version")
+	}
+	for _, line := range compactDiff(diff, historyDiffContext) {
+		lines = append(lines, truncateString(line, dims.rightPanelWidth-1))
+	}
+	return lines
+}
+
+// compactDiff renders the changed lines of a diff with some unchanged lines around them
+// Skipped unchanged lines are shown as "…"
+func compactDiff(diff []scripts.DiffLine, context int) []string {
+	shown := make([]bool, len(diff))
+	for i, line := range diff {
+		if line.Kind == scripts.DiffSame {
+			continue
+		}
+		for j := i - context; j <= i+context; j++ {
+			if j >= 0 && j < len(diff) {
+				shown[j] = true
+			}
+		}
+	}
+
+	lines := make([]string, 0)
+	skipped := false
+	for i, line := range diff {
+		if !shown[i] {
+			skipped = true
+			continue
+		}
+		if skipped && len(lines) > 0 {
+			lines = append(lines, " …")
+		}
+		skipped = false
+		lines = append(lines, fmt.Sprintf(" %c %s", line.Kind, line.Text))
+	}
+	return lines
+}
@@ -0,0 +1,45 @@
+package presentation
+
+import (
+	"cli-notes/scripts"
+	"fmt"
+)
+
+// RenderLogStatus returns the indicator of background problems, drawn over the right end of
+// the top line of a view without moving the cursor, or "" when nothing went wrong
+func RenderLogStatus(termWidth int) string {
+	problems, _ := scripts.UnseenLogProblems()
+	if problems == 0 {
+		return ""
+	}
+
+	indicator := fmt.Sprintf(" ⚠ %d background problems, run log ", problems)
+	if problems == 1 {
+		indicator = " ⚠ 1 background problem, run log "
+	}
+	column := termWidth - runeCount(indicator)
+	if column < 1 {
+		column = 1
+	}
+	return fmt.Sprintf("\0337\033[1;%dH%s\0338", column, indicator)
+}
+
+// LogStatusLine returns a line about the background problems logged since the log was last
+// looked at, for the command line, or "" when there are none
+func LogStatusLine() string {
+	problems, last := scripts.UnseenLogProblems()
+	switch problems {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("Background problem: %s (run log to see it)", last)
+	}
+	return fmt.Sprintf("%d background problems, latest: %s (run log to see them)", problems, last)
+}
+
+// PrintLogEntries prints log lines, oldest first
+func PrintLogEntries(entries []string) {
+	for _, entry := range entries {
+		fmt.Println(entry)
+	}
+}
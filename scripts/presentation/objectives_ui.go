@@ -129,7 +129,10 @@ func RenderSingleObjectiveView(state *data.ObjectivesViewState, termWidth, termH
 	var uncompletedTasks []string
 	selectedChild := state.GetSelectedChild()
 	if selectedChild != nil {
-		tasks, err := scripts.GetUncompletedTasksInFiles([]scripts.File{*selectedChild})
+		// Tasks in notes embedded by the child count as its open tasks
+		child := *selectedChild
+		child.Content = state.ExpandEmbeds(child)
+		tasks, err := scripts.GetUncompletedTasksInFiles([]scripts.File{child})
 		if err == nil {
 			uncompletedTasks = tasks
 		}
@@ -215,7 +218,7 @@ func buildLeftPanelLines(state *data.ObjectivesViewState, dims objectivesDimensi
 	lines = append(lines, parentIndicator+"[PARENT] "+state.CurrentObjective.Title)
 
 	// Parent content lines (first few lines of content)
-	content := state.CurrentObjective.Content
+	content := state.ExpandEmbeds(*state.CurrentObjective)
 	contentLines := strings.Split(content, "\n")
 	for i, line := range contentLines {
 		if i >= 3 { // Limit content preview to 3 lines
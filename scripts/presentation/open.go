@@ -1,18 +1,29 @@
 package presentation
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 )
 
 func OpenNoteInEditor(filePath string, onKeyboardClose func(), onKeyboardReopen func()) error {
+	return OpenNoteInEditorAtLine(filePath, 0, onKeyboardClose, onKeyboardReopen)
+}
+
+// OpenNoteInEditorAtLine opens a note with the cursor on the given line, e.g. nvim +12
+// A line of 0 opens the note at the top
+func OpenNoteInEditorAtLine(filePath string, line int, onKeyboardClose func(), onKeyboardReopen func()) error {
 	onKeyboardClose()
 
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = "nvim"
 	}
-	cmd := exec.Command(editor, filePath)
+	args := []string{filePath}
+	if line > 0 {
+		args = []string{fmt.Sprintf("+%d", line), filePath}
+	}
+	cmd := exec.Command(editor, args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
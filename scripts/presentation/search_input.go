@@ -33,6 +33,11 @@ const (
 	SearchOpenObjective  // O key - open objectives view
 	SearchCycleFilter    // f key - cycle filter mode (all/incomplete/complete)
 	SearchCycleMatchMode // s key - toggle fuzzy/strict matching
+	SearchOpenTagTree    // T key - open tag tree filter
+
+	// Tag tree actions
+	SearchApplyTagFilter // Enter in tag tree - filter by selected tag
+	SearchClearTagFilter // x in tag tree - remove tag filter
 
 	// Link mode actions
 	SearchLinkSelected     // Enter in link mode (ln flow) - links and exits
@@ -55,6 +60,8 @@ func ParseSearchInput(char rune, key keyboard.Key, mode data.SearchViewMode) Sea
 		return parseNormalModeInput(char, key)
 	case data.SearchModeActions:
 		return parseActionsModeInput(char, key)
+	case data.SearchModeTagTree:
+		return parseTagTreeModeInput(char, key)
 	default:
 		return SearchInput{Action: SearchNoAction}
 	}
@@ -69,6 +76,8 @@ func ParseSearchInputWithState(char rune, key keyboard.Key, state *data.SearchSt
 		return parseNormalModeInputWithState(char, key, state)
 	case data.SearchModeActions:
 		return parseActionsModeInput(char, key)
+	case data.SearchModeTagTree:
+		return parseTagTreeModeInput(char, key)
 	default:
 		return SearchInput{Action: SearchNoAction}
 	}
@@ -149,6 +158,8 @@ func parseNormalModeInput(char rune, key keyboard.Key) SearchInput {
 		return SearchInput{Action: SearchCycleFilter}
 	case 's':
 		return SearchInput{Action: SearchCycleMatchMode}
+	case 'T':
+		return SearchInput{Action: SearchOpenTagTree}
 	}
 
 	return SearchInput{Action: SearchNoAction}
@@ -249,6 +260,8 @@ func parseNormalModeInputWithState(char rune, key keyboard.Key, state *data.Sear
 		return SearchInput{Action: SearchCycleFilter}
 	case 's':
 		return SearchInput{Action: SearchCycleMatchMode}
+	case 'T':
+		return SearchInput{Action: SearchOpenTagTree}
 	}
 
 	return SearchInput{Action: SearchNoAction}
@@ -278,3 +291,30 @@ func parseActionsModeInput(char rune, key keyboard.Key) SearchInput {
 
 	return SearchInput{Action: SearchNoAction}
 }
+
+// parseTagTreeModeInput handles input in the tag tree filter picker
+func parseTagTreeModeInput(char rune, key keyboard.Key) SearchInput {
+	switch key {
+	case keyboard.KeyEnter:
+		return SearchInput{Action: SearchApplyTagFilter}
+	case keyboard.KeyEsc:
+		return SearchInput{Action: SearchEnterNormal}
+	case keyboard.KeyArrowUp:
+		return SearchInput{Action: SearchNavigateUp}
+	case keyboard.KeyArrowDown:
+		return SearchInput{Action: SearchNavigateDown}
+	}
+
+	switch char {
+	case 'j':
+		return SearchInput{Action: SearchNavigateDown}
+	case 'k':
+		return SearchInput{Action: SearchNavigateUp}
+	case 'x':
+		return SearchInput{Action: SearchClearTagFilter}
+	case 'q':
+		return SearchInput{Action: SearchEnterNormal}
+	}
+
+	return SearchInput{Action: SearchNoAction}
+}
@@ -1,6 +1,7 @@
 package presentation
 
 import (
+	"cli-notes/scripts"
 	"cli-notes/scripts/data"
 	"fmt"
 	"strings"
@@ -113,6 +114,9 @@ func RenderSearchView(state *data.SearchState, termWidth, termHeight int) string
 		filterLabel = "Done"
 	}
 	matchCount := fmt.Sprintf(" %d matches | %s | %s ", len(state.Results), filterLabel, state.GetMatchModeLabel())
+	if state.TagFilter != "" {
+		matchCount = fmt.Sprintf(" %d matches | %s | %s | Tag: %s ", len(state.Results), filterLabel, state.GetMatchModeLabel(), state.TagFilter)
+	}
 	separatorLen := termWidth - len(matchCount) - 2
 	leftSep := separatorLen / 2
 	rightSep := separatorLen - leftSep
@@ -155,16 +159,18 @@ func RenderSearchView(state *data.SearchState, termWidth, termHeight int) string
 	case data.SearchModeNormal:
 		if state.IsLinkMode() {
 			// In link mode (from ln command), Enter directly links
-			controls = " [NORMAL] i:Ins j/k:Nav f:Flt s:Srch Enter:Link q:Cancel"
+			controls = " [NORMAL] i:Ins j/k:Nav f:Flt s:Srch T:Tags Enter:Link q:Cancel"
 		} else if state.HasPendingLink() {
 			// Has pending link source, l will complete the link
 			controls = " [NORMAL] i:Ins j/k:Nav l:LinkTo Esc:Cancel q:Quit"
 		} else {
 			// Standard GS mode
-			controls = " [NORMAL] i:Ins j/k:Nav f:Flt s:Srch d:Done 1-3:Pri t:Today l:Link L:Graph o:Obj O:View q:Quit"
+			controls = " [NORMAL] i:Ins j/k:Nav f:Flt s:Srch T:Tags d:Done 1-3:Pri t:Today l:Link L:Graph o:Obj O:View q:Quit"
 		}
 	case data.SearchModeActions:
 		controls = " [ACTIONS] j/k:Navigate  Enter:Execute  Esc:Back"
+	case data.SearchModeTagTree:
+		controls = " [TAGS] j/k:Navigate  Enter:Filter  x:Clear filter  Esc:Back"
 	}
 	controlsPadding := termWidth - len([]rune(controls)) - 2
 	if controlsPadding < 0 {
@@ -180,6 +186,11 @@ func RenderSearchView(state *data.SearchState, termWidth, termHeight int) string
 		output.WriteString(renderActionsOverlay(state, dims))
 	}
 
+	// Render tag tree overlay if picking a tag filter
+	if state.ViewMode == data.SearchModeTagTree {
+		output.WriteString(renderTagTreeOverlay(state, dims))
+	}
+
 	return output.String()
 }
 
@@ -242,15 +253,50 @@ func buildSearchResultsPanel(state *data.SearchState, dims searchDimensions) []s
 }
 
 // buildSearchPreviewPanel builds the right panel content (preview)
+// Related notes are kept at the bottom of the panel, cutting the note content short if needed
 func buildSearchPreviewPanel(state *data.SearchState, dims searchDimensions) []string {
-	var lines []string
-
 	result := state.GetSelectedResult()
 	if result == nil {
-		lines = append(lines, " (no selection)")
+		return []string{" (no selection)"}
+	}
+
+	lines := buildSearchPreviewContent(state, result, dims)
+	related := buildRelatedPanel(state.RelatedNotes(result.File), dims)
+	if len(related) == 0 {
 		return lines
 	}
 
+	if maxContent := dims.visibleResults - len(related); len(lines) > maxContent {
+		lines = lines[:maxInt(maxContent, 0)]
+	}
+	return append(lines, related...)
+}
+
+// buildRelatedPanel lists the notes related to the selected one, with why
+func buildRelatedPanel(related []scripts.RelatedNote, dims searchDimensions) []string {
+	// A panel narrower than the separator and ellipsis has no room for the list
+	if len(related) == 0 || dims.rightPanelWidth < 4 {
+		return nil
+	}
+
+	lines := []string{
+		" " + strings.Repeat("─", dims.rightPanelWidth-3),
+		" Related (r in actions links the first):",
+	}
+	for _, note := range related {
+		line := fmt.Sprintf("  %s  (%s)", note.File.Title, note.Reasons())
+		if runes := []rune(line); len(runes) > dims.rightPanelWidth-1 {
+			line = string(runes[:dims.rightPanelWidth-4]) + "..."
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// buildSearchPreviewContent builds the details and content of the selected note
+func buildSearchPreviewContent(state *data.SearchState, result *data.SearchResult, dims searchDimensions) []string {
+	var lines []string
+
 	// Title
 	titleLine := " " + strings.ToUpper(result.File.Title)
 	titleRunes := []rune(titleLine)
@@ -289,6 +335,19 @@ func buildSearchPreviewPanel(state *data.SearchState, dims searchDimensions) []s
 	// Separator
 	lines = append(lines, " "+strings.Repeat("─", dims.rightPanelWidth-3))
 
+	// Section targeted by a heading or block link
+	if len(result.Section) > 0 {
+		lines = append(lines, fmt.Sprintf(" L%d:", result.SnippetLine))
+		section := result.File
+		section.Content = strings.Join(result.Section, "\n")
+		return appendPreviewContent(lines, state.ExpandEmbeds(section), dims)
+	}
+
+	// Notes embedding other notes show the expanded content
+	if scripts.HasEmbeds(result.File.Content) {
+		return appendPreviewContent(lines, strings.Trim(state.ExpandEmbeds(result.File), "\n"), dims)
+	}
+
 	// Content snippet
 	if result.ContentSnippet != "" {
 		snippetPrefix := " "
@@ -307,6 +366,19 @@ func buildSearchPreviewPanel(state *data.SearchState, dims searchDimensions) []s
 	return lines
 }
 
+// appendPreviewContent adds content to the preview, one truncated line per content line
+func appendPreviewContent(lines []string, content string, dims searchDimensions) []string {
+	for _, contentLine := range strings.Split(content, "\n") {
+		contentLine = " " + contentLine
+		contentRunes := []rune(contentLine)
+		if len(contentRunes) > dims.rightPanelWidth-1 {
+			contentLine = string(contentRunes[:dims.rightPanelWidth-4]) + "..."
+		}
+		lines = append(lines, contentLine)
+	}
+	return lines
+}
+
 // renderSearchSplitLine renders a line split between left and right panels
 func renderSearchSplitLine(leftContent, rightContent string, dims searchDimensions) string {
 	leftRunes := []rune(leftContent)
@@ -395,6 +467,73 @@ func renderActionsOverlay(state *data.SearchState, dims searchDimensions) string
 	return output.String()
 }
 
+// renderTagTreeOverlay renders the tag tree filter picker overlay
+func renderTagTreeOverlay(state *data.SearchState, dims searchDimensions) string {
+	var output strings.Builder
+
+	if len(state.TagTree) == 0 {
+		return ""
+	}
+
+	// Show as many tags as fit, scrolled so the selection stays visible
+	overlayWidth := 40
+	visibleRows := len(state.TagTree)
+	if maxRows := dims.terminalHeight - 8; visibleRows > maxRows {
+		visibleRows = maxRows
+	}
+	if visibleRows < 1 {
+		visibleRows = 1
+	}
+	offset := 0
+	if state.TagTreeIndex >= visibleRows {
+		offset = state.TagTreeIndex - visibleRows + 1
+	}
+	overlayHeight := visibleRows + 4 // +4 for borders and header
+
+	// Position in center of screen
+	startCol := (dims.terminalWidth - overlayWidth) / 2
+	startRow := (dims.terminalHeight - overlayHeight) / 2
+	if startRow < 1 {
+		startRow = 1
+	}
+
+	// Top border
+	output.WriteString(fmt.Sprintf("\033[%d;%dH", startRow, startCol))
+	output.WriteString("┌" + strings.Repeat("─", overlayWidth-2) + "┐")
+
+	// Header
+	output.WriteString(fmt.Sprintf("\033[%d;%dH", startRow+1, startCol))
+	output.WriteString("│" + padRight(" Filter by Tag", overlayWidth-2) + "│")
+
+	// Separator
+	output.WriteString(fmt.Sprintf("\033[%d;%dH", startRow+2, startCol))
+	output.WriteString("├" + strings.Repeat("─", overlayWidth-2) + "┤")
+
+	// Tags, nested tags indented under their parent
+	for row := 0; row < visibleRows; row++ {
+		i := offset + row
+		node := state.TagTree[i]
+
+		indicator := "  "
+		if i == state.TagTreeIndex {
+			indicator = "► "
+		}
+
+		label := indicator + strings.Repeat("  ", node.Depth) + node.Name
+		count := fmt.Sprintf("%d ", node.Count)
+		labelWidth := overlayWidth - 2 - len(count)
+
+		output.WriteString(fmt.Sprintf("\033[%d;%dH", startRow+3+row, startCol))
+		output.WriteString("│" + padRight(label, labelWidth) + count + "│")
+	}
+
+	// Bottom border
+	output.WriteString(fmt.Sprintf("\033[%d;%dH", startRow+3+visibleRows, startCol))
+	output.WriteString("└" + strings.Repeat("─", overlayWidth-2) + "┘")
+
+	return output.String()
+}
+
 // padRight pads a string to the specified length
 func padRight(s string, length int) string {
 	sRunes := []rune(s)
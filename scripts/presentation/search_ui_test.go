@@ -0,0 +1,23 @@
+package presentation
+
+import (
+	"cli-notes/scripts"
+	"testing"
+)
+
+func TestBuildRelatedPanel_HidesOnNarrowTerminals(t *testing.T) {
+	related := []scripts.RelatedNote{{File: scripts.File{Title: "billing"}, SharedLinks: 1}}
+
+	for _, width := range []int{1, 2, 5, 7} {
+		dims := calculateSearchDimensions(width, 24)
+		lines := buildRelatedPanel(related, dims)
+		if dims.rightPanelWidth < 4 && lines != nil {
+			t.Errorf("width %d: expected no related panel, got %v", width, lines)
+		}
+	}
+
+	dims := calculateSearchDimensions(80, 24)
+	if lines := buildRelatedPanel(related, dims); len(lines) != 3 {
+		t.Errorf("expected separator, header and one note, got %v", lines)
+	}
+}
@@ -333,6 +333,8 @@ func HandleTalkToInput(state *data.TalkToViewState, input TalkToInput) (bool, st
 			if err != nil {
 				return false, "", err
 			}
+			// Signal that the move was undone (committed in main.go)
+			return false, "UNDONE:", nil
 		}
 		return false, "", nil
 
@@ -391,7 +393,8 @@ func handleConfirm(state *data.TalkToViewState) (bool, string, error) {
 		if err != nil {
 			return false, fmt.Sprintf("Move failed: %v", err), nil
 		}
-		return false, "", nil
+		// Signal that the todos moved (committed in main.go)
+		return false, "MOVED:", nil
 
 	default:
 		return false, "", nil
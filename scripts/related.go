@@ -0,0 +1,253 @@
+package scripts
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters for text similarity
+const (
+	bm25K1 = 1.2  // How quickly repeated terms stop adding to the score
+	bm25B  = 0.75 // How much longer notes are penalised
+)
+
+// Weights of the signals that make notes related; text similarity is scaled to 0-1 first
+const (
+	relatedTextWeight       = 1.0
+	relatedSharedLinkWeight = 0.25 // Per note both notes are linked with
+	relatedObjectiveWeight  = 0.5
+	maxRelatedSharedLinks   = 4 // Shared links beyond this add nothing
+	titleTermWeight         = 3 // Title terms count as this many content terms
+	tagTermWeight           = 2 // Tag terms count as this many content terms
+	maxQueryTermWeight      = 3 // Cap on how often a term of the note counts in its query
+)
+
+// stopWords are common words that say nothing about what a note is about
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "but": true,
+	"by": true, "for": true, "from": true, "has": true, "have": true, "he": true, "her": true, "his": true,
+	"i": true, "if": true, "in": true, "into": true, "is": true, "it": true, "its": true, "me": true,
+	"my": true, "no": true, "not": true, "of": true, "on": true, "or": true, "our": true, "she": true,
+	"so": true, "that": true, "the": true, "their": true, "them": true, "then": true, "there": true,
+	"they": true, "this": true, "to": true, "us": true, "was": true, "we": true, "were": true, "what": true,
+	"when": true, "which": true, "who": true, "will": true, "with": true, "you": true, "your": true,
+	"id": true, // From ID-backed links
+}
+
+// Tokenize splits text into lowercase words, dropping stop words and single characters
+func Tokenize(text string) []string {
+	words := searchWords(text)
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 2 || stopWords[word] {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// searchWords splits text into lowercase words of letters and digits
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// RelatedNote is a note suggested as related to another, with why
+type RelatedNote struct {
+	File          File
+	Score         float64
+	TextScore     float64 // BM25 similarity, scaled so the most similar note scores 1
+	SharedLinks   int     // Notes both notes link to or are linked from
+	SameObjective bool    // Both belong to the same objective
+}
+
+// Reasons describes why the note is related
+func (r RelatedNote) Reasons() string {
+	reasons := make([]string, 0, 3)
+	if r.TextScore > 0 {
+		reasons = append(reasons, fmt.Sprintf("%.0f%% similar text", r.TextScore*100))
+	}
+	if r.SharedLinks == 1 {
+		reasons = append(reasons, "1 shared link")
+	} else if r.SharedLinks > 1 {
+		reasons = append(reasons, fmt.Sprintf("%d shared links", r.SharedLinks))
+	}
+	if r.SameObjective {
+		reasons = append(reasons, "same objective")
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// RelatedIndex ranks notes by how related they are to a note, built once from every note
+type RelatedIndex struct {
+	files      map[string]File
+	terms      map[string]map[string]int // Term frequencies by filename
+	lengths    map[string]int            // Weighted term count by filename
+	docFreq    map[string]int            // Number of notes with each term
+	avgLength  float64
+	outLinks   map[string]map[string]bool // Notes each note links to
+	neighbours map[string]map[string]bool // Notes each note links to or is linked from
+}
+
+// NewRelatedIndex indexes the terms of every note's title, tags and content,
+// and the resolved links between notes, by filename
+func NewRelatedIndex(files []File, outLinks map[string][]string) *RelatedIndex {
+	idx := &RelatedIndex{
+		files:      make(map[string]File, len(files)),
+		terms:      make(map[string]map[string]int, len(files)),
+		lengths:    make(map[string]int, len(files)),
+		docFreq:    make(map[string]int),
+		outLinks:   make(map[string]map[string]bool),
+		neighbours: make(map[string]map[string]bool),
+	}
+
+	totalLength := 0
+	for _, file := range files {
+		idx.files[file.Name] = file
+		terms := noteTerms(file)
+		idx.terms[file.Name] = terms
+		for term, count := range terms {
+			idx.docFreq[term]++
+			idx.lengths[file.Name] += count
+		}
+		totalLength += idx.lengths[file.Name]
+	}
+	if len(files) > 0 {
+		idx.avgLength = float64(totalLength) / float64(len(files))
+	}
+
+	addEdge := func(edges map[string]map[string]bool, from, to string) {
+		if edges[from] == nil {
+			edges[from] = make(map[string]bool)
+		}
+		edges[from][to] = true
+	}
+	for from, targets := range outLinks {
+		for _, to := range targets {
+			if from == to {
+				continue
+			}
+			addEdge(idx.outLinks, from, to)
+			addEdge(idx.neighbours, from, to)
+			addEdge(idx.neighbours, to, from)
+		}
+	}
+
+	return idx
+}
+
+// Related returns up to limit notes related to the note, most related first
+// Notes it already links to are left out, since they are only a link away
+func (idx *RelatedIndex) Related(fileName string, limit int) []RelatedNote {
+	source, exists := idx.files[fileName]
+	if !exists {
+		return []RelatedNote{}
+	}
+
+	query := idx.terms[fileName]
+	textScores := make(map[string]float64)
+	maxTextScore := 0.0
+	for name := range idx.files {
+		if name == fileName {
+			continue
+		}
+		score := idx.bm25(query, name)
+		textScores[name] = score
+		maxTextScore = math.Max(maxTextScore, score)
+	}
+
+	related := make([]RelatedNote, 0)
+	for name, file := range idx.files {
+		if name == fileName || idx.outLinks[fileName][name] {
+			continue
+		}
+
+		note := RelatedNote{
+			File:          file,
+			SharedLinks:   idx.sharedLinks(fileName, name),
+			SameObjective: source.ObjectiveID != "" && source.ObjectiveID == file.ObjectiveID,
+		}
+		if maxTextScore > 0 {
+			note.TextScore = textScores[name] / maxTextScore
+		}
+
+		note.Score = relatedTextWeight*note.TextScore +
+			relatedSharedLinkWeight*float64(minInt(note.SharedLinks, maxRelatedSharedLinks))
+		if note.SameObjective {
+			note.Score += relatedObjectiveWeight
+		}
+		if note.Score > 0 {
+			related = append(related, note)
+		}
+	}
+
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].File.Name < related[j].File.Name
+	})
+	if limit > 0 && len(related) > limit {
+		related = related[:limit]
+	}
+	return related
+}
+
+// bm25 scores a note against the terms of another note
+func (idx *RelatedIndex) bm25(query map[string]int, fileName string) float64 {
+	terms := idx.terms[fileName]
+	length := float64(idx.lengths[fileName])
+	total := float64(len(idx.files))
+
+	score := 0.0
+	for term, queryCount := range query {
+		frequency := float64(terms[term])
+		if frequency == 0 {
+			continue
+		}
+		docFreq := float64(idx.docFreq[term])
+		idf := math.Log(1 + (total-docFreq+0.5)/(docFreq+0.5))
+		norm := 1 - bm25B + bm25B*length/math.Max(idx.avgLength, 1)
+		score += float64(minInt(queryCount, maxQueryTermWeight)) * idf * frequency * (bm25K1 + 1) / (frequency + bm25K1*norm)
+	}
+	return score
+}
+
+// sharedLinks counts the notes both notes link to or are linked from
+func (idx *RelatedIndex) sharedLinks(a, b string) int {
+	shared := 0
+	for name := range idx.neighbours[a] {
+		if name != b && idx.neighbours[b][name] {
+			shared++
+		}
+	}
+	return shared
+}
+
+// noteTerms counts the terms of a note, weighting title and tag terms above content terms
+func noteTerms(file File) map[string]int {
+	terms := make(map[string]int)
+	for _, term := range Tokenize(file.Title) {
+		terms[term] += titleTermWeight
+	}
+	for _, term := range Tokenize(strings.Join(file.Tags, " ")) {
+		terms[term] += tagTermWeight
+	}
+	for _, term := range Tokenize(file.Content) {
+		terms[term]++
+	}
+	return terms
+}
+
+// minInt returns the smaller of two integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
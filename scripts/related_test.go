@@ -0,0 +1,80 @@
+package scripts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize_DropsStopWordsAndPunctuation(t *testing.T) {
+	tokens := Tokenize("The Payments-API is ready for [[id:1a2b3c4d|Launch]] v2!")
+
+	expected := []string{"payments", "api", "ready", "1a2b3c4d", "launch", "v2"}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Expected %v, got %v", expected, tokens)
+	}
+}
+
+func relatedTestFiles() []File {
+	return []File{
+		{Name: "payments.md", Title: "Payments rollout", Tags: []string{"work/payments"}, Content: "Roll out the payments service to android and ios"},
+		{Name: "android.md", Title: "Android release", Content: "Ship the payments screen on android"},
+		{Name: "holiday.md", Title: "Holiday", Content: "Book flights and hotel"},
+		{Name: "retro.md", Title: "Retro", Content: "Went well"},
+		{Name: "plan.md", Title: "Plan", ObjectiveID: "abcd1234", Content: "Quarter plan"},
+		{Name: "budget.md", Title: "Budget", ObjectiveID: "abcd1234", Content: "Numbers"},
+	}
+}
+
+func TestRelatedIndex_RanksBySimilarText(t *testing.T) {
+	idx := NewRelatedIndex(relatedTestFiles(), nil)
+
+	related := idx.Related("payments.md", 10)
+
+	if len(related) != 1 || related[0].File.Name != "android.md" {
+		t.Fatalf("Expected only android.md to be related, got %+v", related)
+	}
+	if related[0].TextScore != 1 || related[0].Reasons() != "100% similar text" {
+		t.Errorf("Expected the most similar note to score 1, got %+v (%s)", related[0], related[0].Reasons())
+	}
+}
+
+func TestRelatedIndex_SharedLinksAndObjective(t *testing.T) {
+	links := map[string][]string{
+		"holiday.md":  {"retro.md"},
+		"payments.md": {"retro.md"},
+	}
+	idx := NewRelatedIndex(relatedTestFiles(), links)
+
+	related := idx.Related("holiday.md", 10)
+	if len(related) != 1 || related[0].File.Name != "payments.md" || related[0].SharedLinks != 1 {
+		t.Fatalf("Expected payments.md through the shared link to retro.md, got %+v", related)
+	}
+	if related[0].Reasons() != "1 shared link" {
+		t.Errorf("Unexpected reasons %q", related[0].Reasons())
+	}
+
+	related = idx.Related("plan.md", 10)
+	if len(related) != 1 || related[0].File.Name != "budget.md" || !related[0].SameObjective {
+		t.Errorf("Expected budget.md through the shared objective, got %+v", related)
+	}
+}
+
+func TestRelatedIndex_LeavesOutLinkedNotesAndLimits(t *testing.T) {
+	files := append(relatedTestFiles(), File{Name: "ios.md", Title: "iOS release", Content: "Ship the payments screen on ios"})
+
+	idx := NewRelatedIndex(files, map[string][]string{"payments.md": {"android.md"}})
+	related := idx.Related("payments.md", 10)
+	for _, note := range related {
+		if note.File.Name == "android.md" {
+			t.Errorf("Expected linked android.md to be left out, got %+v", related)
+		}
+	}
+
+	idx = NewRelatedIndex(files, nil)
+	if related := idx.Related("payments.md", 1); len(related) != 1 {
+		t.Errorf("Expected the limit to apply, got %+v", related)
+	}
+	if related := idx.Related("missing.md", 5); len(related) != 0 {
+		t.Errorf("Expected nothing for an unknown note, got %+v", related)
+	}
+}
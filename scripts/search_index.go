@@ -0,0 +1,315 @@
+package scripts
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Note fields a search looks in, each weighted like in related note ranking
+const (
+	searchFieldTitle = iota
+	searchFieldTags
+	searchFieldContent
+	searchFieldCount
+)
+
+var searchFieldWeights = [searchFieldCount]float64{titleTermWeight, tagTermWeight, 1}
+
+// prefixTermWeight is how much a word that only starts with a query word counts,
+// e.g. "children" for "child", against an exact or stemmed match
+const prefixTermWeight = 0.5
+
+// phrasePattern matches "quoted phrases" in a query
+var phrasePattern = regexp.MustCompile(`"([^"]*)"`)
+
+// SearchHit is a note matching a search, with its relevance
+type SearchHit struct {
+	File  File
+	Score float64
+}
+
+// indexedNote is a note in the search index, with the stems of each field in order for phrase matching
+type indexedNote struct {
+	file   File
+	stems  [searchFieldCount][]string
+	words  map[string]bool // Distinct words of the note, for the vocabulary
+	length float64         // Field weighted term count
+}
+
+// SearchIndex is an inverted index over the title, tags and content of notes
+// Notes are added and removed one at a time, so the index can follow a changing vault
+type SearchIndex struct {
+	notes       map[string]*indexedNote
+	postings    map[string]map[string]float64 // Field weighted frequency of each stem, by filename
+	vocabulary  map[string]int                // Number of notes with each word, for prefix matching
+	sortedWords []string                      // Vocabulary in order, nil when it needs rebuilding
+	totalLength float64
+}
+
+// NewSearchIndex creates an empty search index
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		notes:      make(map[string]*indexedNote),
+		postings:   make(map[string]map[string]float64),
+		vocabulary: make(map[string]int),
+	}
+}
+
+// Len returns the number of indexed notes
+func (idx *SearchIndex) Len() int {
+	return len(idx.notes)
+}
+
+// Add indexes a note, replacing the note with the same filename
+func (idx *SearchIndex) Add(file File) {
+	idx.Remove(file.Name)
+
+	note := &indexedNote{file: file, words: make(map[string]bool)}
+	texts := [searchFieldCount]string{file.Title, strings.Join(file.Tags, " "), file.Content}
+	for field, text := range texts {
+		for _, word := range searchWords(text) {
+			stem := Stem(word)
+			note.stems[field] = append(note.stems[field], stem)
+			note.words[word] = true
+			if idx.postings[stem] == nil {
+				idx.postings[stem] = make(map[string]float64)
+			}
+			idx.postings[stem][file.Name] += searchFieldWeights[field]
+			note.length += searchFieldWeights[field]
+		}
+	}
+
+	for word := range note.words {
+		if idx.vocabulary[word] == 0 {
+			idx.sortedWords = nil
+		}
+		idx.vocabulary[word]++
+	}
+	idx.notes[file.Name] = note
+	idx.totalLength += note.length
+}
+
+// Remove drops a note from the index, if it is there
+func (idx *SearchIndex) Remove(fileName string) {
+	note, exists := idx.notes[fileName]
+	if !exists {
+		return
+	}
+
+	for _, stems := range note.stems {
+		for _, stem := range stems {
+			delete(idx.postings[stem], fileName)
+			if len(idx.postings[stem]) == 0 {
+				delete(idx.postings, stem)
+			}
+		}
+	}
+	for word := range note.words {
+		idx.vocabulary[word]--
+		if idx.vocabulary[word] <= 0 {
+			delete(idx.vocabulary, word)
+			idx.sortedWords = nil
+		}
+	}
+	idx.totalLength -= note.length
+	delete(idx.notes, fileName)
+}
+
+// Search returns the notes matching the query, most relevant first
+// Comma separated parts must all match. Within a part every word must match, by stem
+// ("meetings" finds "meeting") or as the start of a word ("child" finds "children"),
+// and "quoted phrases" must appear word for word. Relevance is BM25, with title and
+// tag matches weighing more than content matches. Parts without words, like "#",
+// fall back to a substring match
+func (idx *SearchIndex) Search(query string) []SearchHit {
+	var scores map[string]float64
+	for _, part := range strings.Split(query, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		partScores := idx.searchPart(part)
+		if scores == nil {
+			scores = partScores
+			continue
+		}
+		for name := range scores {
+			if partScore, matched := partScores[name]; matched {
+				scores[name] += partScore
+			} else {
+				delete(scores, name)
+			}
+		}
+	}
+
+	if scores == nil {
+		// An empty query matches every note
+		scores = make(map[string]float64, len(idx.notes))
+		for name := range idx.notes {
+			scores[name] = 0
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for name, score := range scores {
+		hits = append(hits, SearchHit{File: idx.notes[name].file, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].File.Name < hits[j].File.Name
+	})
+	return hits
+}
+
+// searchPart scores the notes matching every word and phrase of one query part
+func (idx *SearchIndex) searchPart(part string) map[string]float64 {
+	terms, phrases := parseSearchPart(part)
+	if len(terms) == 0 && len(phrases) == 0 {
+		return idx.substringMatches(part)
+	}
+
+	var scores map[string]float64
+	addScores := func(termScores map[string]float64) {
+		if scores == nil {
+			scores = termScores
+			return
+		}
+		for name := range scores {
+			if termScore, matched := termScores[name]; matched {
+				scores[name] += termScore
+			} else {
+				delete(scores, name)
+			}
+		}
+	}
+
+	for _, term := range terms {
+		addScores(idx.scoreTerm(idx.expandTerm(term)))
+	}
+	for _, phrase := range phrases {
+		addScores(idx.scorePhrase(phrase))
+	}
+	return scores
+}
+
+// expandTerm returns the stems a query word matches, with how much each counts
+func (idx *SearchIndex) expandTerm(word string) map[string]float64 {
+	stems := map[string]float64{Stem(word): 1}
+
+	words := idx.vocabularyInOrder()
+	for i := sort.SearchStrings(words, word); i < len(words) && strings.HasPrefix(words[i], word); i++ {
+		stem := Stem(words[i])
+		if _, exists := stems[stem]; !exists {
+			stems[stem] = prefixTermWeight
+		}
+	}
+	return stems
+}
+
+// scoreTerm scores the notes containing any of the stems
+func (idx *SearchIndex) scoreTerm(stems map[string]float64) map[string]float64 {
+	frequencies := make(map[string]float64)
+	for stem, weight := range stems {
+		for name, frequency := range idx.postings[stem] {
+			frequencies[name] += weight * frequency
+		}
+	}
+
+	scores := make(map[string]float64, len(frequencies))
+	for name, frequency := range frequencies {
+		scores[name] = idx.bm25(frequency, len(frequencies), name)
+	}
+	return scores
+}
+
+// scorePhrase scores the notes where the phrase's words appear in order within one field
+func (idx *SearchIndex) scorePhrase(phrase []string) map[string]float64 {
+	stems := make([]string, len(phrase))
+	for i, word := range phrase {
+		stems[i] = Stem(word)
+	}
+
+	scores := make(map[string]float64)
+	for name := range idx.postings[stems[0]] {
+		if !containsPhrase(idx.notes[name].stems, stems) {
+			continue
+		}
+		for _, stem := range stems {
+			scores[name] += idx.bm25(idx.postings[stem][name], len(idx.postings[stem]), name)
+		}
+	}
+	return scores
+}
+
+// substringMatches returns the notes whose title, tags or content contain the text, unscored
+func (idx *SearchIndex) substringMatches(text string) map[string]float64 {
+	text = strings.ToLower(text)
+	scores := make(map[string]float64)
+	for name, note := range idx.notes {
+		target := strings.ToLower(note.file.Title + " " + strings.Join(note.file.Tags, " ") + " " + note.file.Content)
+		if strings.Contains(target, text) {
+			scores[name] = 0
+		}
+	}
+	return scores
+}
+
+// bm25 scores a term's field weighted frequency in a note, given how many notes have the term
+func (idx *SearchIndex) bm25(frequency float64, docFreq int, fileName string) float64 {
+	total := float64(len(idx.notes))
+	avgLength := math.Max(idx.totalLength/math.Max(total, 1), 1)
+	idf := math.Log(1 + (total-float64(docFreq)+0.5)/(float64(docFreq)+0.5))
+	norm := 1 - bm25B + bm25B*idx.notes[fileName].length/avgLength
+	return idf * frequency * (bm25K1 + 1) / (frequency + bm25K1*norm)
+}
+
+// vocabularyInOrder returns the indexed words in order, rebuilding the list after words came or went
+func (idx *SearchIndex) vocabularyInOrder() []string {
+	if idx.sortedWords == nil {
+		idx.sortedWords = make([]string, 0, len(idx.vocabulary))
+		for word := range idx.vocabulary {
+			idx.sortedWords = append(idx.sortedWords, word)
+		}
+		sort.Strings(idx.sortedWords)
+	}
+	return idx.sortedWords
+}
+
+// containsPhrase checks whether the stems appear consecutively in one of the fields
+func containsPhrase(fields [searchFieldCount][]string, phrase []string) bool {
+	for _, stems := range fields {
+		for start := 0; start+len(phrase) <= len(stems); start++ {
+			matched := true
+			for i, stem := range phrase {
+				if stems[start+i] != stem {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseSearchPart splits a query part into loose words and "quoted phrases"
+// A single word in quotes must match exactly, without prefix matching
+func parseSearchPart(part string) ([]string, [][]string) {
+	phrases := make([][]string, 0)
+	for _, match := range phrasePattern.FindAllStringSubmatch(part, -1) {
+		if words := searchWords(match[1]); len(words) > 0 {
+			phrases = append(phrases, words)
+		}
+	}
+
+	// An unmatched quote is ignored
+	rest := strings.ReplaceAll(phrasePattern.ReplaceAllString(part, " "), `"`, " ")
+	return searchWords(rest), phrases
+}
@@ -0,0 +1,100 @@
+package scripts
+
+import "testing"
+
+func searchIndexTestFiles() []File {
+	return []File{
+		{Name: "standup.md", Title: "Standup", Content: "Short meeting about the payments release"},
+		{Name: "meetings.md", Title: "Meetings", Tags: []string{"work"}, Content: "Notes from every meeting"},
+		{Name: "children.md", Title: "Children", Content: "School run and child care"},
+		{Name: "care.md", Title: "Care plan", Content: "Care for the garden, then child minding"},
+	}
+}
+
+func newTestSearchIndex(files []File) *SearchIndex {
+	idx := NewSearchIndex()
+	for _, file := range files {
+		idx.Add(file)
+	}
+	return idx
+}
+
+func hitNames(hits []SearchHit) []string {
+	names := make([]string, len(hits))
+	for i, hit := range hits {
+		names[i] = hit.File.Name
+	}
+	return names
+}
+
+func TestSearchIndex_StemsAndRanksTitleMatchesFirst(t *testing.T) {
+	idx := newTestSearchIndex(searchIndexTestFiles())
+
+	names := hitNames(idx.Search("meetings"))
+	if len(names) != 2 || names[0] != "meetings.md" || names[1] != "standup.md" {
+		t.Errorf("Expected the title match before the content match, got %v", names)
+	}
+}
+
+func TestSearchIndex_PrefixMatchesAndAndsWords(t *testing.T) {
+	idx := newTestSearchIndex(searchIndexTestFiles())
+
+	names := hitNames(idx.Search("child"))
+	if len(names) != 2 || names[0] != "children.md" {
+		t.Errorf("Expected children.md first from its title, got %v", names)
+	}
+
+	if names := hitNames(idx.Search("child care")); len(names) != 2 {
+		t.Errorf("Expected both notes with child and care, got %v", names)
+	}
+	if names := hitNames(idx.Search("chldcare")); len(names) != 0 {
+		t.Errorf("Expected no matches for a misspelling, got %v", names)
+	}
+}
+
+func TestSearchIndex_PhrasesAndCommaParts(t *testing.T) {
+	idx := newTestSearchIndex(searchIndexTestFiles())
+
+	names := hitNames(idx.Search(`"child care"`))
+	if len(names) != 1 || names[0] != "children.md" {
+		t.Errorf("Expected only the note with the exact phrase, got %v", names)
+	}
+
+	names = hitNames(idx.Search("meeting, work"))
+	if len(names) != 1 || names[0] != "meetings.md" {
+		t.Errorf("Expected comma parts to be ANDed, got %v", names)
+	}
+
+	if names := hitNames(idx.Search(`"child`)); len(names) != 2 {
+		t.Errorf("Expected an unmatched quote to be ignored, got %v", names)
+	}
+}
+
+func TestSearchIndex_UpdatesIncrementally(t *testing.T) {
+	idx := newTestSearchIndex(searchIndexTestFiles())
+
+	idx.Add(File{Name: "standup.md", Title: "Standup", Content: "Blockers only"})
+	if names := hitNames(idx.Search("payments")); len(names) != 0 {
+		t.Errorf("Expected the replaced content to be gone, got %v", names)
+	}
+	if names := hitNames(idx.Search("blocker")); len(names) != 1 {
+		t.Errorf("Expected the new content to be found, got %v", names)
+	}
+
+	idx.Remove("children.md")
+	if names := hitNames(idx.Search("school")); len(names) != 0 || idx.Len() != 3 {
+		t.Errorf("Expected the removed note to be gone, got %v", names)
+	}
+}
+
+func TestSearchIndex_FallsBackToSubstringWithoutWords(t *testing.T) {
+	idx := newTestSearchIndex([]File{
+		{Name: "a.md", Title: "A", Content: "Use #hashtags"},
+		{Name: "b.md", Title: "B", Content: "Plain"},
+	})
+
+	names := hitNames(idx.Search("#"))
+	if len(names) != 1 || names[0] != "a.md" {
+		t.Errorf("Expected the substring match, got %v", names)
+	}
+}
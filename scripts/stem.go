@@ -0,0 +1,194 @@
+package scripts
+
+import "strings"
+
+// Stem reduces an English word to its stem with the Porter algorithm, so "meetings",
+// "meeting" and "meet" all index as "meet". Words with anything but a-z are returned as is
+func Stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	word = stemStep1a(word)
+	word = stemStep1b(word)
+	word = stemStep1c(word)
+	word = replaceSuffix(word, step2Suffixes, 0)
+	word = replaceSuffix(word, step3Suffixes, 0)
+	word = stemStep4(word)
+	word = stemStep5(word)
+	return word
+}
+
+// suffixRule replaces a suffix when the measure of the remaining stem is large enough
+type suffixRule struct {
+	suffix      string
+	replacement string
+}
+
+var step2Suffixes = []suffixRule{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"}, {"izer", "ize"},
+	{"abli", "able"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"},
+	{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"},
+	{"fulness", "ful"}, {"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+}
+
+var step3Suffixes = []suffixRule{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"}, {"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+// step4Suffixes are removed when the stem has measure above 1, longest first
+var step4Suffixes = []string{
+	"ement", "ance", "ence", "able", "ible", "ment", "ant", "ent", "ism", "ate", "iti", "ous", "ive", "ize",
+	"ion", "al", "er", "ic", "ou",
+}
+
+// replaceSuffix applies the rule for the longest matching suffix, if its stem measure is above minMeasure
+func replaceSuffix(word string, rules []suffixRule, minMeasure int) string {
+	longest := -1
+	for i, rule := range rules {
+		if strings.HasSuffix(word, rule.suffix) && (longest < 0 || len(rule.suffix) > len(rules[longest].suffix)) {
+			longest = i
+		}
+	}
+	if longest < 0 {
+		return word
+	}
+	stem := strings.TrimSuffix(word, rules[longest].suffix)
+	if measure(stem) > minMeasure {
+		return stem + rules[longest].replacement
+	}
+	return word
+}
+
+func stemStep1a(word string) string {
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ies"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func stemStep1b(word string) string {
+	if strings.HasSuffix(word, "eed") {
+		if measure(word[:len(word)-3]) > 0 {
+			return word[:len(word)-1]
+		}
+		return word
+	}
+
+	var stem string
+	switch {
+	case strings.HasSuffix(word, "ed") && hasVowel(word[:len(word)-2]):
+		stem = word[:len(word)-2]
+	case strings.HasSuffix(word, "ing") && hasVowel(word[:len(word)-3]):
+		stem = word[:len(word)-3]
+	default:
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(stem, "at"), strings.HasSuffix(stem, "bl"), strings.HasSuffix(stem, "iz"):
+		return stem + "e"
+	case endsWithDoubleConsonant(stem) && !strings.ContainsAny(stem[len(stem)-1:], "lsz"):
+		return stem[:len(stem)-1]
+	case measure(stem) == 1 && endsCVC(stem):
+		return stem + "e"
+	}
+	return stem
+}
+
+func stemStep1c(word string) string {
+	if strings.HasSuffix(word, "y") && hasVowel(word[:len(word)-1]) {
+		return word[:len(word)-1] + "i"
+	}
+	return word
+}
+
+func stemStep4(word string) string {
+	for _, suffix := range step4Suffixes {
+		if !strings.HasSuffix(word, suffix) {
+			continue
+		}
+		stem := strings.TrimSuffix(word, suffix)
+		if measure(stem) <= 1 {
+			return word
+		}
+		if suffix == "ion" && !strings.HasSuffix(stem, "s") && !strings.HasSuffix(stem, "t") {
+			return word
+		}
+		return stem
+	}
+	return word
+}
+
+func stemStep5(word string) string {
+	if strings.HasSuffix(word, "e") {
+		stem := word[:len(word)-1]
+		if m := measure(stem); m > 1 || m == 1 && !endsCVC(stem) {
+			word = stem
+		}
+	}
+	if strings.HasSuffix(word, "ll") && measure(word) > 1 {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+// isConsonant reports whether the letter at i is a consonant; y after a consonant is a vowel
+func isConsonant(word string, i int) bool {
+	switch word[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !isConsonant(word, i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in a stem
+func measure(stem string) int {
+	m := 0
+	previousVowel := false
+	for i := 0; i < len(stem); i++ {
+		vowel := !isConsonant(stem, i)
+		if previousVowel && !vowel {
+			m++
+		}
+		previousVowel = vowel
+	}
+	return m
+}
+
+func hasVowel(stem string) bool {
+	for i := 0; i < len(stem); i++ {
+		if !isConsonant(stem, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func endsWithDoubleConsonant(stem string) bool {
+	n := len(stem)
+	return n >= 2 && stem[n-1] == stem[n-2] && isConsonant(stem, n-1)
+}
+
+// endsCVC reports whether a stem ends consonant-vowel-consonant, the last not w, x or y
+func endsCVC(stem string) bool {
+	n := len(stem)
+	if n < 3 || !isConsonant(stem, n-3) || isConsonant(stem, n-2) || !isConsonant(stem, n-1) {
+		return false
+	}
+	return !strings.ContainsAny(stem[n-1:], "wxy")
+}
@@ -0,0 +1,32 @@
+package scripts
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	cases := map[string]string{
+		"caresses":        "caress",
+		"ponies":          "poni",
+		"cats":            "cat",
+		"agreed":          "agre",
+		"motoring":        "motor",
+		"hopping":         "hop",
+		"filing":          "file",
+		"happy":           "happi",
+		"relational":      "relat",
+		"hopefulness":     "hope",
+		"generalizations": "gener",
+		"adjustment":      "adjust",
+		"adoption":        "adopt",
+		"controll":        "control",
+		"meetings":        "meet",
+		"meeting":         "meet",
+		"is":              "is",
+		"v2":              "v2",
+		"café":            "café",
+	}
+	for word, expected := range cases {
+		if stem := Stem(word); stem != expected {
+			t.Errorf("Stem(%q) = %q, expected %q", word, stem, expected)
+		}
+	}
+}
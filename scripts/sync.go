@@ -1,48 +1,161 @@
 package scripts
 
 import (
+	"cli-notes/scripts/config"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
 const SYNC_DELAY_TIME_SECONDS = 30
 
-func StartBackupSync(srcDir string) {
+// BackupMirrorDirs returns the directories the notes are mirrored to: ~/Documents/notes,
+// then each extra directory, where a leading ~ stands for the home directory
+func BackupMirrorDirs(extra []string) ([]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := []string{filepath.Join(homeDir, "Documents", "notes")}
+	for _, dir := range extra {
+		if dir == "~" || strings.HasPrefix(dir, "~/") {
+			dir = filepath.Join(homeDir, strings.TrimPrefix(dir, "~"))
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// BackupSync mirrors and snapshots the notes in the background, and once more on exit
+type BackupSync struct {
+	srcDir     string
+	mirrorDirs []string
+	policy     BackupPolicy
+
+	// mu is held for a whole sync, so the final sync waits for a background one to finish
+	mu         sync.Mutex
+	stopped    bool
+	stop       chan struct{}
+	lastErrors map[string]string // The last error logged for each mirror, so a refused deletion isn't logged every sync
+}
+
+// NewBackupSync creates the backup sync of the notes in srcDir, started with Start
+func NewBackupSync(srcDir string, mirrorDirs []string, policy BackupPolicy) *BackupSync {
+	return &BackupSync{
+		srcDir:     srcDir,
+		mirrorDirs: mirrorDirs,
+		policy:     policy,
+		stop:       make(chan struct{}),
+		lastErrors: make(map[string]string),
+	}
+}
+
+// Start syncs right away and then every SYNC_DELAY_TIME_SECONDS, until RunFinal stops it
+func (b *BackupSync) Start() {
 	if os.Getenv("CLI_NOTES_TEST_MODE") == "true" {
 		return
 	}
 
-	syncToBackup(srcDir)
-
-	for {
-		time.Sleep(SYNC_DELAY_TIME_SECONDS * time.Second)
-		syncToBackup(srcDir)
+	for b.runUnlessStopped() {
+		select {
+		case <-b.stop:
+			return
+		case <-time.After(SYNC_DELAY_TIME_SECONDS * time.Second):
+		}
 	}
 }
 
-func RunFinalSync(srcDir string) {
+// RunFinal stops the background syncs, waiting for a running one, then mirrors the notes once more
+// and takes a snapshot if they changed since the latest one
+func (b *BackupSync) RunFinal() {
 	if os.Getenv("CLI_NOTES_TEST_MODE") == "true" {
 		return
 	}
 
-	syncToBackup(srcDir)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.stopped {
+		b.stopped = true
+		close(b.stop)
+	}
+	b.run(true)
 }
 
-func syncToBackup(srcDir string) {
-	homeDir, err := os.UserHomeDir()
+// runUnlessStopped runs a background sync and reports whether the syncs go on
+func (b *BackupSync) runUnlessStopped() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopped {
+		return false
+	}
+	b.run(false)
+	return true
+}
+
+// run mirrors the notes and takes a snapshot when one is due, with mu held
+func (b *BackupSync) run(onExit bool) {
+	b.syncToBackups()
+	snapshotBackup(b.srcDir, b.policy, onExit)
+}
+
+// snapshotBackup takes a snapshot when one is due, then prunes the snapshots the policy doesn't keep
+// On exit a snapshot is taken whenever the notes changed, however recent the latest one is
+func snapshotBackup(srcDir string, policy BackupPolicy, onExit bool) {
+	backupDir, err := BackupSnapshotsDir()
 	if err != nil {
-		fmt.Printf("Backup sync error: %v\n", err)
+		slog.Error("backup snapshot failed", "err", err)
 		return
 	}
 
-	dstDir := filepath.Join(homeDir, "Documents", "notes")
-	_, err = SyncNotesToBackup(srcDir, dstDir)
+	snapshots, err := ListBackupSnapshots(backupDir)
 	if err != nil {
-		fmt.Printf("Backup sync error: %v\n", err)
+		slog.Error("listing backup snapshots failed", "dir", backupDir, "err", err)
+		return
+	}
+	interval := policy.SnapshotInterval
+	if onExit {
+		interval = 0
+	}
+	if !BackupSnapshotDue(srcDir, snapshots, time.Now(), interval) {
+		return
+	}
+
+	snapshot, err := CreateBackupSnapshot(srcDir, backupDir, time.Now())
+	if err != nil {
+		slog.Error("backup snapshot failed", "dir", backupDir, "err", err)
+		return
+	}
+	slog.Info("backup snapshot taken", "path", snapshot.Path)
+
+	pruned, err := PruneBackupSnapshots(backupDir, policy)
+	if err != nil {
+		slog.Error("pruning backup snapshots failed", "dir", backupDir, "err", err)
+	}
+	for _, old := range pruned {
+		slog.Debug("backup snapshot pruned", "path", old.Path)
+	}
+}
+
+func (b *BackupSync) syncToBackups() {
+	for _, dstDir := range b.mirrorDirs {
+		_, err := SyncNotesToBackup(b.srcDir, dstDir)
+		if err != nil && err.Error() != b.lastErrors[dstDir] {
+			slog.Error("backup sync failed", "dir", dstDir, "err", err)
+		}
+		delete(b.lastErrors, dstDir)
+		if err != nil {
+			b.lastErrors[dstDir] = err.Error()
+		}
 	}
 }
 
@@ -54,6 +167,7 @@ func SyncNotesToBackup(srcDir, dstDir string) (int, error) {
 
 	// Track which source files exist for stale cleanup
 	sourceFiles := make(map[string]bool)
+	copiedHashes := make(map[string]string)
 	copied := 0
 
 	err = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
@@ -81,10 +195,11 @@ func SyncNotesToBackup(srcDir, dstDir string) (int, error) {
 			return nil
 		}
 
-		err = copyFile(path, dstPath)
+		hash, err := copyFile(path, dstPath)
 		if err != nil {
 			return fmt.Errorf("failed to copy %s: %w", relPath, err)
 		}
+		copiedHashes[relPath] = hash
 		copied++
 
 		return nil
@@ -93,7 +208,10 @@ func SyncNotesToBackup(srcDir, dstDir string) (int, error) {
 		return copied, err
 	}
 
-	// Remove stale files from destination
+	// Find stale files in destination
+	stalePaths := make([]string, 0)
+	backupFiles := make(map[string]bool)
+	mirrored := 0
 	err = filepath.Walk(dstDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -112,14 +230,36 @@ func SyncNotesToBackup(srcDir, dstDir string) (int, error) {
 			return err
 		}
 
+		mirrored++
+		backupFiles[relPath] = true
 		if !sourceFiles[relPath] {
-			return os.Remove(path)
+			stalePaths = append(stalePaths, relPath)
 		}
 
 		return nil
 	})
+	if err != nil {
+		return copied, err
+	}
+
+	var refusal error
+	if len(stalePaths) > config.ALWAYS_MIRRORED_DELETES && len(stalePaths)*100 > mirrored*config.MAX_MIRRORED_DELETE_PERCENT {
+		refusal = fmt.Errorf("refusing to mirror the deletion of %d of %d notes (more than %d%%), use restore to bring them back or delete them from %s by hand",
+			len(stalePaths), mirrored, config.MAX_MIRRORED_DELETE_PERCENT, dstDir)
+	} else {
+		// Remove stale files from destination
+		for _, relPath := range stalePaths {
+			if err := os.Remove(filepath.Join(dstDir, relPath)); err != nil {
+				return copied, err
+			}
+			delete(backupFiles, relPath)
+		}
+	}
 
-	return copied, err
+	if err := updateBackupManifest(srcDir, dstDir, backupFiles, copiedHashes); err != nil {
+		return copied, fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	return copied, refusal
 }
 
 func shouldSkipCopy(srcPath, dstPath string, srcInfo os.FileInfo) bool {
@@ -131,28 +271,30 @@ func shouldSkipCopy(srcPath, dstPath string, srcInfo os.FileInfo) bool {
 	return dstInfo.Size() == srcInfo.Size() && !dstInfo.ModTime().Before(srcInfo.ModTime())
 }
 
-func copyFile(src, dst string) error {
+// copyFile copies src to dst, keeping its modification time, and returns the SHA-256 of what was copied
+func copyFile(src, dst string) (string, error) {
 	srcFile, err := os.Open(src)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer srcFile.Close()
 
 	srcInfo, err := srcFile.Stat()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	dstFile, err := os.Create(dst)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer dstFile.Close()
 
-	_, err = io.Copy(dstFile, srcFile)
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(dstFile, hasher), srcFile)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return os.Chtimes(dst, srcInfo.ModTime(), srcInfo.ModTime())
+	return hex.EncodeToString(hasher.Sum(nil)), os.Chtimes(dst, srcInfo.ModTime(), srcInfo.ModTime())
 }
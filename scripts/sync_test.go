@@ -1,6 +1,7 @@
 package scripts
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -169,3 +170,63 @@ func TestSyncIgnoresNonMdFiles(t *testing.T) {
 		t.Error("expected data.json to not be copied")
 	}
 }
+
+func TestSyncRefusesToMirrorMassDeletion(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	for i := 0; i < 10; i++ {
+		os.WriteFile(filepath.Join(srcDir, fmt.Sprintf("note%d.md", i)), []byte("note"), 0644)
+	}
+	if _, err := SyncNotesToBackup(srcDir, dstDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Delete 4 of 10 notes, more than 20% and more than 3
+	for i := 0; i < 4; i++ {
+		os.Remove(filepath.Join(srcDir, fmt.Sprintf("note%d.md", i)))
+	}
+	os.WriteFile(filepath.Join(srcDir, "note9.md"), []byte("edited"), 0644)
+
+	_, err := SyncNotesToBackup(srcDir, dstDir)
+	if err == nil {
+		t.Fatal("expected the mass deletion to be refused")
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := os.Stat(filepath.Join(dstDir, fmt.Sprintf("note%d.md", i))); err != nil {
+			t.Errorf("expected note%d.md to be kept in destination", i)
+		}
+	}
+	if content, _ := os.ReadFile(filepath.Join(dstDir, "note9.md")); string(content) != "edited" {
+		t.Errorf("expected changes to still be mirrored, got %q", content)
+	}
+}
+
+func TestBackupSync_RunFinalStopsTheBackgroundSync(t *testing.T) {
+	t.Setenv("CLI_NOTES_TEST_MODE", "")
+	t.Setenv("HOME", t.TempDir())
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	os.WriteFile(filepath.Join(srcDir, "note.md"), []byte("# Note"), 0644)
+
+	backupSync := NewBackupSync(srcDir, []string{dstDir}, BackupPolicy{})
+	stopped := make(chan struct{})
+	go func() {
+		backupSync.Start()
+		close(stopped)
+	}()
+
+	os.WriteFile(filepath.Join(srcDir, "late.md"), []byte("# Late"), 0644)
+	backupSync.RunFinal()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the background sync to stop after the final sync")
+	}
+	for _, name := range []string{"note.md", "late.md"} {
+		if _, err := os.Stat(filepath.Join(dstDir, name)); err != nil {
+			t.Errorf("expected %s to be mirrored: %v", name, err)
+		}
+	}
+}
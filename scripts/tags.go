@@ -0,0 +1,305 @@
+package scripts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TagSeparator separates the levels of a hierarchical tag
+const TagSeparator = "/"
+
+// TagTreeNode is one level of a hierarchical tag such as "work/payments"
+type TagTreeNode struct {
+	Tag   string // Full tag path, e.g. "work/payments"
+	Name  string // Last segment, e.g. "payments"
+	Depth int    // 0 for top level tags
+	Count int    // Notes with this tag or any tag nested under it
+}
+
+// TagChange describes how a single note's tags change in a bulk tag operation
+type TagChange struct {
+	File    File
+	OldTags []string
+	NewTags []string
+}
+
+// NormalizeTag trims a tag and joins inner whitespace with dashes,
+// since tags are stored space separated in frontmatter
+// Empty levels of a nested tag are dropped, e.g. "/work//payments/" -> "work/payments"
+func NormalizeTag(tag string) string {
+	levels := make([]string, 0)
+	for _, level := range strings.Split(tag, TagSeparator) {
+		level = strings.Join(strings.Fields(level), "-")
+		if level != "" {
+			levels = append(levels, level)
+		}
+	}
+	return strings.Join(levels, TagSeparator)
+}
+
+// NormalizeTags normalizes every tag, dropping empty and duplicate tags
+// Order of first occurrence is preserved
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+// TagMatches reports whether tag is query or nested under it
+// e.g. "work" matches "work" and "work/payments" but not "workshop"
+func TagMatches(tag, query string) bool {
+	query = strings.TrimSuffix(query, TagSeparator)
+	if query == "" {
+		return false
+	}
+	return tag == query || strings.HasPrefix(tag, query+TagSeparator)
+}
+
+// HasMatchingTag reports whether any of the tags matches query hierarchically
+func HasMatchingTag(tags []string, query string) bool {
+	for _, tag := range tags {
+		if TagMatches(tag, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// TagAncestors returns the parent levels of a tag, outermost first
+// e.g. "work/payments/android" -> [work work/payments]
+func TagAncestors(tag string) []string {
+	parts := strings.Split(tag, TagSeparator)
+	ancestors := make([]string, 0, len(parts)-1)
+	for i := 1; i < len(parts); i++ {
+		ancestors = append(ancestors, strings.Join(parts[:i], TagSeparator))
+	}
+	return ancestors
+}
+
+// MergeInheritedTags adds the parent's tags (except "objective") to the child's
+// A tag already implied by a more specific child tag is skipped, and a child tag
+// that is an ancestor of an inherited tag is replaced by it
+func MergeInheritedTags(childTags, parentTags []string) []string {
+	merged := append([]string{}, childTags...)
+
+	for _, parentTag := range parentTags {
+		if parentTag == "objective" || HasMatchingTag(merged, parentTag) {
+			continue
+		}
+
+		kept := merged[:0]
+		for _, tag := range merged {
+			if !TagMatches(parentTag, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		merged = append(kept, parentTag)
+	}
+
+	return merged
+}
+
+// BuildTagTree arranges all tags into a tree, flattened in display order
+// Every level of a nested tag gets a node, so "work/payments" also yields "work"
+// Siblings are sorted by count (most used first), then alphabetically
+func BuildTagTree(files []File) []TagTreeNode {
+	counts := make(map[string]int)
+	children := make(map[string][]string)
+
+	for _, file := range files {
+		levels := make(map[string]bool)
+		for _, tag := range NormalizeTags(file.Tags) {
+			levels[tag] = true
+			for _, ancestor := range TagAncestors(tag) {
+				levels[ancestor] = true
+			}
+		}
+
+		for level := range levels {
+			if counts[level] == 0 {
+				parent := ""
+				if ancestors := TagAncestors(level); len(ancestors) > 0 {
+					parent = ancestors[len(ancestors)-1]
+				}
+				children[parent] = append(children[parent], level)
+			}
+			counts[level]++
+		}
+	}
+
+	tree := make([]TagTreeNode, 0, len(counts))
+	var walk func(parent string, depth int)
+	walk = func(parent string, depth int) {
+		levels := children[parent]
+		sort.Slice(levels, func(i, j int) bool {
+			if counts[levels[i]] != counts[levels[j]] {
+				return counts[levels[i]] > counts[levels[j]]
+			}
+			return levels[i] < levels[j]
+		})
+
+		for _, level := range levels {
+			tree = append(tree, TagTreeNode{
+				Tag:   level,
+				Name:  level[strings.LastIndex(level, TagSeparator)+1:],
+				Depth: depth,
+				Count: counts[level],
+			})
+			walk(level, depth+1)
+		}
+	}
+	walk("", 0)
+
+	return tree
+}
+
+// PlanTagRename returns the changes needed to rename oldTag to newTag
+// Tags nested under oldTag move along, e.g. "work/x" becomes "job/x"
+// Fails if newTag is already in use; use PlanTagMerge to combine existing tags
+func PlanTagRename(files []File, oldTag, newTag string) ([]TagChange, error) {
+	oldTag = NormalizeTag(oldTag)
+	newTag = NormalizeTag(newTag)
+
+	if oldTag == "" || newTag == "" {
+		return nil, fmt.Errorf("both the old and the new tag are required")
+	}
+	if oldTag == newTag {
+		return nil, fmt.Errorf("old and new tag are the same")
+	}
+	if !tagInUse(files, oldTag) {
+		return nil, fmt.Errorf("tag not found: %s", oldTag)
+	}
+	if tagInUse(files, newTag) {
+		return nil, fmt.Errorf("tag %s already exists, use tag-merge to combine tags", newTag)
+	}
+
+	return planTagReplacement(files, oldTag, newTag), nil
+}
+
+// PlanTagMerge returns the changes needed to fold sourceTag into targetTag
+// Tags nested under sourceTag move under targetTag
+// Notes that already have targetTag simply lose sourceTag
+func PlanTagMerge(files []File, sourceTag, targetTag string) ([]TagChange, error) {
+	sourceTag = NormalizeTag(sourceTag)
+	targetTag = NormalizeTag(targetTag)
+
+	if sourceTag == "" || targetTag == "" {
+		return nil, fmt.Errorf("both the source and the target tag are required")
+	}
+	if sourceTag == targetTag {
+		return nil, fmt.Errorf("cannot merge a tag into itself")
+	}
+	if !tagInUse(files, sourceTag) {
+		return nil, fmt.Errorf("tag not found: %s", sourceTag)
+	}
+	if !tagInUse(files, targetTag) {
+		return nil, fmt.Errorf("tag not found: %s", targetTag)
+	}
+
+	return planTagReplacement(files, sourceTag, targetTag), nil
+}
+
+// PlanTagRemoval returns the changes needed to remove tag from every note
+// Only the exact tag is removed; tags nested under it are kept
+func PlanTagRemoval(files []File, tag string) ([]TagChange, error) {
+	tag = NormalizeTag(tag)
+
+	if tag == "" {
+		return nil, fmt.Errorf("a tag is required")
+	}
+	if !tagUsedExactly(files, tag) {
+		return nil, fmt.Errorf("tag not found: %s", tag)
+	}
+
+	return planTagReplacement(files, tag, ""), nil
+}
+
+// ApplyTagChanges writes the new tags of every changed note
+func ApplyTagChanges(changes []TagChange, writeFile WriteFile) error {
+	for _, change := range changes {
+		// Read the latest content from the file to ensure we don't lose any updates
+		updatedFile, err := readLatestFileContent(change.File)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", change.File.Name, err)
+		}
+
+		updatedFile.Tags = change.NewTags
+
+		if err := writeFile(updatedFile); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.File.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// planTagReplacement replaces fromTag with toTag (or drops it when toTag is empty)
+// When replacing, tags nested under fromTag are moved under toTag as well
+func planTagReplacement(files []File, fromTag, toTag string) []TagChange {
+	changes := make([]TagChange, 0)
+
+	for _, file := range files {
+		oldTags := NormalizeTags(file.Tags)
+
+		changed := false
+		newTags := make([]string, 0, len(oldTags))
+		for _, tag := range oldTags {
+			switch {
+			case tag == fromTag:
+				changed = true
+				if toTag != "" {
+					newTags = append(newTags, toTag)
+				}
+			case toTag != "" && TagMatches(tag, fromTag):
+				changed = true
+				newTags = append(newTags, toTag+strings.TrimPrefix(tag, fromTag))
+			default:
+				newTags = append(newTags, tag)
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		changes = append(changes, TagChange{
+			File:    file,
+			OldTags: oldTags,
+			NewTags: NormalizeTags(newTags),
+		})
+	}
+
+	return changes
+}
+
+// tagInUse reports whether any note has the given tag or a tag nested under it
+func tagInUse(files []File, tag string) bool {
+	for _, file := range files {
+		if HasMatchingTag(NormalizeTags(file.Tags), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagUsedExactly reports whether any note has exactly the given tag
+func tagUsedExactly(files []File, tag string) bool {
+	for _, file := range files {
+		for _, t := range NormalizeTags(file.Tags) {
+			if t == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
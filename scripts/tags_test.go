@@ -0,0 +1,245 @@
+package scripts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []string
+	}{
+		{name: "Already normalized", tags: []string{"work", "todo"}, expected: []string{"work", "todo"}},
+		{name: "Trims whitespace", tags: []string{" work ", "todo"}, expected: []string{"work", "todo"}},
+		{name: "Joins inner whitespace", tags: []string{"team sync"}, expected: []string{"team-sync"}},
+		{name: "Drops empty and duplicate tags", tags: []string{"work", "", "work", " "}, expected: []string{"work"}},
+		{name: "Cleans nested tag levels", tags: []string{"/work// payments/"}, expected: []string{"work/payments"}},
+		{name: "Nil", tags: nil, expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NormalizeTags(tt.tags)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTagMatches(t *testing.T) {
+	tests := []struct {
+		tag      string
+		query    string
+		expected bool
+	}{
+		{tag: "work", query: "work", expected: true},
+		{tag: "work/payments/android", query: "work", expected: true},
+		{tag: "work/payments/android", query: "work/payments", expected: true},
+		{tag: "work/payments", query: "work/", expected: true},
+		{tag: "workshop", query: "work", expected: false},
+		{tag: "work", query: "work/payments", expected: false},
+		{tag: "work", query: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag+" "+tt.query, func(t *testing.T) {
+			if result := TagMatches(tt.tag, tt.query); result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMergeInheritedTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		childTags  []string
+		parentTags []string
+		expected   []string
+	}{
+		{
+			name:       "Adds parent tags except objective",
+			childTags:  []string{"todo"},
+			parentTags: []string{"objective", "work"},
+			expected:   []string{"todo", "work"},
+		},
+		{
+			name:       "Skips tags implied by a nested child tag",
+			childTags:  []string{"todo", "work/payments"},
+			parentTags: []string{"work"},
+			expected:   []string{"todo", "work/payments"},
+		},
+		{
+			name:       "Nested parent tag replaces its ancestor",
+			childTags:  []string{"todo", "work"},
+			parentTags: []string{"work/payments"},
+			expected:   []string{"todo", "work/payments"},
+		},
+		{
+			name:       "Exact duplicates are skipped",
+			childTags:  []string{"todo", "work"},
+			parentTags: []string{"work"},
+			expected:   []string{"todo", "work"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MergeInheritedTags(tt.childTags, tt.parentTags)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildTagTree(t *testing.T) {
+	files := []File{
+		{Name: "a.md", Tags: []string{"work/payments/android", "todo"}},
+		{Name: "b.md", Tags: []string{"work/payments", "todo"}},
+		{Name: "c.md", Tags: []string{"work/hiring", "todo"}},
+		{Name: "d.md", Tags: []string{"home"}},
+	}
+
+	expected := []TagTreeNode{
+		{Tag: "todo", Name: "todo", Depth: 0, Count: 3},
+		{Tag: "work", Name: "work", Depth: 0, Count: 3},
+		{Tag: "work/payments", Name: "payments", Depth: 1, Count: 2},
+		{Tag: "work/payments/android", Name: "android", Depth: 2, Count: 1},
+		{Tag: "work/hiring", Name: "hiring", Depth: 1, Count: 1},
+		{Tag: "home", Name: "home", Depth: 0, Count: 1},
+	}
+
+	result := BuildTagTree(files)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestPlanTagRename(t *testing.T) {
+	files := []File{
+		{Name: "a.md", Tags: []string{"work", "todo"}},
+		{Name: "b.md", Tags: []string{"todo"}},
+		{Name: "c.md", Tags: []string{"android"}},
+	}
+
+	t.Run("Renames only notes with the tag", func(t *testing.T) {
+		changes, err := PlanTagRename(files, "todo", "task")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(changes) != 2 {
+			t.Fatalf("Expected 2 changes, got %d", len(changes))
+		}
+		if !reflect.DeepEqual(changes[0].NewTags, []string{"work", "task"}) {
+			t.Errorf("Expected [work task], got %v", changes[0].NewTags)
+		}
+		if !reflect.DeepEqual(changes[1].NewTags, []string{"task"}) {
+			t.Errorf("Expected [task], got %v", changes[1].NewTags)
+		}
+	})
+
+	t.Run("Nested tags move along", func(t *testing.T) {
+		nested := []File{{Name: "n.md", Tags: []string{"work/payments", "todo"}}}
+		changes, err := PlanTagRename(nested, "work", "job")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(changes) != 1 || !reflect.DeepEqual(changes[0].NewTags, []string{"job/payments", "todo"}) {
+			t.Errorf("Expected [job/payments todo], got %v", changes)
+		}
+	})
+
+	t.Run("Existing target suggests merge", func(t *testing.T) {
+		_, err := PlanTagRename(files, "todo", "work")
+		if err == nil {
+			t.Error("Expected error when renaming to an existing tag")
+		}
+	})
+
+	t.Run("Unknown tag", func(t *testing.T) {
+		_, err := PlanTagRename(files, "missing", "task")
+		if err == nil {
+			t.Error("Expected error for unknown tag")
+		}
+	})
+}
+
+func TestPlanTagMerge(t *testing.T) {
+	files := []File{
+		{Name: "a.md", Tags: []string{"todo", "task"}},
+		{Name: "b.md", Tags: []string{"todo"}},
+		{Name: "c.md", Tags: []string{"task"}},
+	}
+
+	changes, err := PlanTagMerge(files, "todo", "task")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %d", len(changes))
+	}
+	if !reflect.DeepEqual(changes[0].NewTags, []string{"task"}) {
+		t.Errorf("Expected duplicate tag to collapse to [task], got %v", changes[0].NewTags)
+	}
+	if !reflect.DeepEqual(changes[1].NewTags, []string{"task"}) {
+		t.Errorf("Expected [task], got %v", changes[1].NewTags)
+	}
+
+	if _, err := PlanTagMerge(files, "todo", "missing"); err == nil {
+		t.Error("Expected error when merging into an unknown tag")
+	}
+}
+
+func TestPlanTagRemoval(t *testing.T) {
+	files := []File{
+		{Name: "a.md", Tags: []string{"work", "todo"}},
+		{Name: "b.md", Tags: []string{"work"}},
+	}
+
+	changes, err := PlanTagRemoval(files, "todo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(changes))
+	}
+	if !reflect.DeepEqual(changes[0].NewTags, []string{"work"}) {
+		t.Errorf("Expected [work], got %v", changes[0].NewTags)
+	}
+}
+
+func TestApplyTagChanges(t *testing.T) {
+	originalReadLatest := readLatestFileContent
+	defer func() { readLatestFileContent = originalReadLatest }()
+	readLatestFileContent = func(f File) (File, error) {
+		f.Content = "latest content"
+		return f, nil
+	}
+
+	written := make([]File, 0)
+	writeFile := func(f File) error {
+		written = append(written, f)
+		return nil
+	}
+
+	changes := []TagChange{
+		{File: File{Name: "a.md", Tags: []string{"todo"}}, OldTags: []string{"todo"}, NewTags: []string{"task"}},
+	}
+
+	if err := ApplyTagChanges(changes, writeFile); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(written) != 1 {
+		t.Fatalf("Expected 1 write, got %d", len(written))
+	}
+	if !reflect.DeepEqual(written[0].Tags, []string{"task"}) {
+		t.Errorf("Expected tags [task], got %v", written[0].Tags)
+	}
+	if written[0].Content != "latest content" {
+		t.Errorf("Expected latest content to be preserved, got '%s'", written[0].Content)
+	}
+}
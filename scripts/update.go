@@ -12,6 +12,9 @@ import (
 
 type WriteFile = func(File) error
 
+// WriteFiles writes several files as one operation
+type WriteFiles = func([]File) error
+
 // Make the function a variable so it can be overridden in tests
 var readLatestFileContent = func(file File) (File, error) {
 	// Get the current working directory
@@ -78,6 +81,8 @@ var readLatestFileContent = func(file File) (File, error) {
 					updatedFile.ObjectiveRole = value
 				case "objective-id":
 					updatedFile.ObjectiveID = value
+				case "id":
+					updatedFile.ID = value
 				}
 			}
 		} else {
@@ -208,6 +213,22 @@ func ChangePriority(newPriority Priority, file File, writeFile WriteFile) error
 // RenameFile renames a file by extracting the date suffix, creating a new filename,
 // updating the title in metadata and content, and renaming the file on disk
 func RenameFile(newTitle string, file File, writeFile WriteFile) (File, error) {
+	renamedFile, _, err := RenameFileWithLinks(newTitle, file, nil, func(files []File) error {
+		for _, f := range files {
+			if err := writeFile(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return renamedFile, err
+}
+
+// RenameFileWithLinks renames a file like RenameFile and also rewrites the links
+// in the referencing files so they point at the new title or filename
+// The renamed file and every rewritten file are written in a single writeFiles call
+// Returns the renamed file and the number of other notes that were updated
+func RenameFileWithLinks(newTitle string, file File, referencing []File, writeFiles WriteFiles) (File, int, error) {
 	// Extract date suffix from current filename
 	// Example: "test-2-2025-07-29.md" -> "2025-07-29"
 	fileName := file.Name
@@ -216,14 +237,14 @@ func RenameFile(newTitle string, file File, writeFile WriteFile) (File, error) {
 
 	// Find the date suffix (last 10 characters should be YYYY-MM-DD format)
 	if len(nameWithoutExt) < 10 {
-		return File{}, fmt.Errorf("filename %s does not have a valid date suffix", fileName)
+		return File{}, 0, fmt.Errorf("filename %s does not have a valid date suffix", fileName)
 	}
 
 	dateSuffix := nameWithoutExt[len(nameWithoutExt)-10:]
 	// Validate date format
 	_, err := time.Parse("2006-01-02", dateSuffix)
 	if err != nil {
-		return File{}, fmt.Errorf("filename %s does not have a valid date suffix: %v", fileName, err)
+		return File{}, 0, fmt.Errorf("filename %s does not have a valid date suffix: %v", fileName, err)
 	}
 
 	// Create new filename
@@ -232,8 +253,9 @@ func RenameFile(newTitle string, file File, writeFile WriteFile) (File, error) {
 	// Read the latest content from the file
 	updatedFile, err := readLatestFileContent(file)
 	if err != nil {
-		return File{}, err
+		return File{}, 0, err
 	}
+	originalFile := updatedFile
 
 	// Update the title
 	updatedFile.Title = newTitle
@@ -254,7 +276,7 @@ func RenameFile(newTitle string, file File, writeFile WriteFile) (File, error) {
 	// Get the current working directory
 	currentDir, err := os.Getwd()
 	if err != nil {
-		return File{}, err
+		return File{}, 0, err
 	}
 
 	// Construct file paths
@@ -264,17 +286,29 @@ func RenameFile(newTitle string, file File, writeFile WriteFile) (File, error) {
 	// Update the filename in the struct
 	updatedFile.Name = newFileName
 
-	// Write the updated content to the new file
-	if err := writeFile(updatedFile); err != nil {
-		return File{}, err
+	// Rewrite links in other notes, and any links the note has to itself
+	rename := NewLinkRename(originalFile, updatedFile)
+	updatedFile.Content, _ = RewriteLinks(updatedFile.Content, rename)
+
+	others := make([]File, 0, len(referencing))
+	for _, ref := range referencing {
+		if ref.Name != fileName {
+			others = append(others, ref)
+		}
+	}
+	rewritten := PlanLinkRewrites(others, rename)
+
+	// Write the renamed file together with every rewritten note
+	if err := writeFiles(append([]File{updatedFile}, rewritten...)); err != nil {
+		return File{}, 0, err
 	}
 
 	// Remove the old file if it's different from the new one
 	if oldPath != newPath {
 		if err := os.Remove(oldPath); err != nil {
-			return File{}, fmt.Errorf("failed to remove old file: %v", err)
+			return File{}, 0, fmt.Errorf("failed to remove old file: %v", err)
 		}
 	}
 
-	return updatedFile, nil
+	return updatedFile, len(rewritten), nil
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"cli-notes/input"
+	"cli-notes/scripts"
+	"cli-notes/scripts/config"
+	"cli-notes/scripts/data"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/eiannone/keyboard"
+)
+
+// interruptRequested is set when Ctrl+C closed a view, so the command line exits once the view returns
+var interruptRequested atomic.Bool
+
+// interrupted reports whether the key is Ctrl+C, which raw mode reads as a key instead of a signal,
+// and then asks the command line to exit once the view returns
+func interrupted(key keyboard.Key) bool {
+	if key != keyboard.KeyCtrlC {
+		return false
+	}
+	interruptRequested.Store(true)
+	return true
+}
+
+// notifyShutdownSignals returns the signals that stop the program: Ctrl+C outside raw mode,
+// a kill and a closed terminal
+func notifyShutdownSignals() <-chan os.Signal {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	return signals
+}
+
+// shutDownOnSignal restores the terminal and points to the drafts journaling the unsaved work of open views
+func shutDownOnSignal(sig os.Signal) {
+	input.ShutDownKeyboard()
+	fmt.Println()
+	slog.Info("shutting down on signal", "signal", sig.String())
+
+	for _, path := range data.PendingDrafts() {
+		fmt.Printf("Unsaved changes kept in %s\n", path)
+	}
+}
+
+// runFinalSyncs runs the final backup, commit and remote sync, giving up on them after the timeout
+// or when another signal arrives. A remote sync given up on is stopped and gets a moment to abort
+// its rebase or merge, so the notes repository isn't left in the middle of one
+func runFinalSyncs(backupSync *scripts.BackupSync, timeout time.Duration, signals <-chan os.Signal) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		backupSync.RunFinal()
+		scripts.RunFinalGitCommit("./notes")
+		scripts.RunFinalRemoteSync(ctx, "./notes", remoteSyncOptions())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+		slog.Error("final sync timed out", "timeout", timeout)
+		fmt.Printf("Final backup and commit did not finish within %s, exiting anyway\n", timeout)
+	case sig := <-signals:
+		slog.Warn("final sync skipped", "signal", sig.String())
+		fmt.Println("Skipping the final backup and commit")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(config.SHUTDOWN_ABORT_SECONDS * time.Second):
+	case <-signals:
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"cli-notes/input"
 	"cli-notes/scripts"
 	"cli-notes/scripts/data"
 	"cli-notes/scripts/presentation"
@@ -13,7 +14,14 @@ import (
 
 func runTestMode(fileStore *data.SearchedFilesStore, onClose func()) {
 	reader := bufio.NewReader(os.Stdin)
+	offerDraftRecovery(input.NewStdinReader(reader))
+	if interruptRequested.Load() {
+		onClose()
+		return
+	}
+
 	command := presentation.WIPCommand{}
+	completer := newCompleter()
 
 	fmt.Print("> ")
 	for {
@@ -35,6 +43,9 @@ func runTestMode(fileStore *data.SearchedFilesStore, onClose func()) {
 			key = keyboard.KeyTab
 		case '\x7f': // Backspace
 			key = keyboard.KeyBackspace
+		case '\x03': // Ctrl+C
+			onClose()
+			return
 		case '\x1b': // Escape or start of sequence
 			// Check if there are more bytes
 			if reader.Buffered() > 0 {
@@ -72,7 +83,7 @@ func runTestMode(fileStore *data.SearchedFilesStore, onClose func()) {
 				return scripts.GetUncompletedTasksInFiles(files)
 			},
 			func() { fmt.Print("\b \b") },
-			data.QueryNonFinishedObjectives,
+			completer,
 		)
 
 		if err != nil {
@@ -128,6 +139,11 @@ func runTestMode(fileStore *data.SearchedFilesStore, onClose func()) {
 			}
 
 			handleCommand(completedCommand, onClose, fileStore, reader)
+			if interruptRequested.Load() {
+				onClose()
+				return
+			}
+			printNewLogProblems()
 			fmt.Print("> ")
 			command = presentation.WIPCommand{}
 